@@ -1,24 +1,167 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"oa-gogolook/internal"
 	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/eventbus"
+	"oa-gogolook/internal/job"
+	"oa-gogolook/internal/notifier"
+	"oa-gogolook/internal/repository/eventsource"
 	"oa-gogolook/internal/repository/inmemory"
+	"oa-gogolook/internal/repository/localfs"
+	"oa-gogolook/internal/stream"
 	"oa-gogolook/internal/usecase"
+	"oa-gogolook/internal/webhook"
+	"time"
 )
 
 func main() {
-	r := inmemory.NewTaskRepository()
-	u := usecase.NewTaskUsecase(r)
 	config, err := domain.LoadConfig("configs", "app.env")
 	if err != nil {
 		log.Fatal("can not load config. ", err)
 	}
 
-	server, err := internal.NewHttpServer(u, config)
+	r, err := newTaskRepository(config)
+	if err != nil {
+		log.Fatal("can not create task repository. ", err)
+	}
+	userRepository := inmemory.NewUserRepository()
+	commentRepository := inmemory.NewCommentRepository()
+	events := eventbus.NewBus()
+	u := usecase.NewTaskUsecase(r, userRepository, commentRepository, config.CommentPolicy, events)
+	userUsecase := usecase.NewUserUsecase(userRepository)
+	commentUsecase := usecase.NewCommentUsecase(commentRepository, r, userRepository)
+	blobStore, err := localfs.NewBlobStore(config.AttachmentDir)
+	if err != nil {
+		log.Fatal("can not create blob store. ", err)
+	}
+	attachmentUsecase := usecase.NewAttachmentUsecase(inmemory.NewAttachmentRepository(), r, blobStore, config.MaxAttachmentSize)
+	events.Subscribe("attachments", attachmentUsecase.HandleTaskEvent)
+
+	go job.Every(context.Background(), "purge trash", config.PurgeInterval, func(ctx context.Context) error {
+		purged, err := u.Purge(ctx, time.Now().Add(-config.TrashRetention))
+		if purged > 0 {
+			log.Printf("purged %d tasks from trash", purged)
+		}
+		return err
+	})
+
+	if config.AutoArchiveAfter > 0 {
+		go job.Every(context.Background(), "auto archive", config.ArchiveInterval, func(ctx context.Context) error {
+			archived, err := u.AutoArchive(ctx, time.Now().Add(-config.AutoArchiveAfter))
+			if archived > 0 {
+				log.Printf("archived %d completed tasks", archived)
+			}
+			return err
+		})
+	}
+
+	reminderRepository, err := localfs.NewReminderRepository(config.ReminderDir)
+	if err != nil {
+		log.Fatal("can not create reminder repository. ", err)
+	}
+	reminderUsecase := usecase.NewReminderUsecase(reminderRepository, r, newNotifier(config), config.ReminderAttempts)
+	events.Subscribe("reminders", reminderUsecase.HandleTaskEvent)
+	fireReminders := func(ctx context.Context) error {
+		fired, err := reminderUsecase.FireDue(ctx)
+		if fired > 0 {
+			log.Printf("fired %d reminders", fired)
+		}
+		return err
+	}
+	// Reminders that came due while the service was down fire right away.
+	if err := fireReminders(context.Background()); err != nil {
+		log.Printf("job reminders failed: %v", err)
+	}
+	go job.Every(context.Background(), "reminders", config.ReminderInterval, fireReminders)
+
+	webhookUsecase := usecase.NewWebhookUsecase(inmemory.NewWebhookRepository(), inmemory.NewWebhookDeliveryRepository(), webhook.NewSender(nil), config.WebhookMaxAttempts, config.WebhookBackoff)
+	events.Subscribe("webhooks", webhookUsecase.HandleTaskEvent)
+	go job.Every(context.Background(), "webhooks", config.WebhookInterval, func(ctx context.Context) error {
+		_, err := webhookUsecase.DeliverDue(ctx)
+		return err
+	})
+
+	outboxUsecase := usecase.NewOutboxUsecase(r, newPublisher(config), config.OutboxBatchSize)
+	relayOutbox := func(ctx context.Context) error {
+		_, err := outboxUsecase.Relay(ctx)
+		return err
+	}
+	// Messages recorded before a crash or a failed relay go out right away.
+	if err := relayOutbox(context.Background()); err != nil {
+		log.Printf("job outbox failed: %v", err)
+	}
+	go job.Every(context.Background(), "outbox", config.OutboxInterval, relayOutbox)
+
+	taskEvents := stream.NewBroker(config.TaskEventBuffer)
+	events.Subscribe("task events", taskEvents.HandleTaskEvent)
+
+	historyRepository := inmemory.NewHistoryRepository()
+	taskUsecase := usecase.NewUndoTaskUsecase(
+		usecase.NewHistoryTaskUsecase(u, historyRepository),
+		inmemory.NewUndoRepository(),
+		config.UndoWindow,
+	)
+	usecases := internal.Usecases{
+		Task:         taskUsecase,
+		User:         userUsecase,
+		Comment:      commentUsecase,
+		Attachment:   attachmentUsecase,
+		Checklist:    usecase.NewChecklistUsecase(taskUsecase),
+		History:      usecase.NewHistoryUsecase(historyRepository),
+		Undo:         taskUsecase,
+		TimeTracking: usecase.NewTimeTrackingUsecase(inmemory.NewTimeEntryRepository(), r, userRepository),
+		Velocity:     usecase.NewVelocityUsecase(r),
+		Template:     usecase.NewTemplateUsecase(inmemory.NewTemplateRepository(), taskUsecase),
+		Reminder:     reminderUsecase,
+		Webhook:      webhookUsecase,
+		TaskEvents:   taskEvents,
+		Sync:         usecase.NewSyncUsecase(r),
+	}
+	grpcServer, err := internal.NewGrpcServer(usecases, config)
+	if err != nil {
+		log.Fatal("can not create grpc server ", err.Error())
+	}
+	go grpcServer.Start()
+	server, err := internal.NewHttpServer(usecases, config)
 	if err != nil {
 		log.Fatal("can not create server ", err.Error())
 	}
 	server.Start()
 }
+
+func newNotifier(config domain.AppConfig) domain.Notifier {
+	if config.ReminderWebhook == "" {
+		return notifier.NewLogNotifier(nil)
+	}
+	return notifier.NewWebhookNotifier(config.ReminderWebhook, nil)
+}
+
+func newPublisher(config domain.AppConfig) domain.Publisher {
+	if config.OutboxPublishURL == "" {
+		return notifier.NewLogNotifier(nil)
+	}
+	return notifier.NewWebhookNotifier(config.OutboxPublishURL, nil)
+}
+
+func newTaskRepository(config domain.AppConfig) (domain.TaskRepository, error) {
+	switch config.TaskRepository {
+	case domain.TaskRepositoryInMemory:
+		return inmemory.NewTaskRepositoryWithRetention(config.VersionRetention), nil
+	case domain.TaskRepositoryEventSource:
+		events, err := eventsource.NewFileEventStore(config.EventStoreDir)
+		if err != nil {
+			return nil, err
+		}
+		snapshots, err := eventsource.NewFileSnapshotStore(config.EventStoreDir)
+		if err != nil {
+			return nil, err
+		}
+		return eventsource.NewTaskRepository(context.Background(), events, snapshots, config.SnapshotEvery)
+	default:
+		return nil, fmt.Errorf("unknown task repository %q", config.TaskRepository)
+	}
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"oa-gogolook/internal/delevery/grpc/pb"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func fromTimestamp(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	rtn := t.AsTime()
+	return &rtn
+}
+
+func fromEstimate(e *pb.Estimate) *domain.Estimate {
+	if e == nil {
+		return nil
+	}
+	return &domain.Estimate{Value: e.Value, Unit: domain.EstimateUnit(e.Unit)}
+}
+
+func toTask(task domain.Task) *pb.Task {
+	rtn := &pb.Task{
+		Id:          task.ID,
+		Status:      int64(task.Status),
+		Name:        task.Name,
+		Description: task.Description,
+		AssigneeId:  task.AssigneeID,
+		DueAt:       toTimestamp(task.DueAt),
+		Tags:        task.Tags,
+		Rank:        task.Rank,
+		CompletedAt: toTimestamp(task.CompletedAt),
+		ArchivedAt:  toTimestamp(task.ArchivedAt),
+		DeletedAt:   toTimestamp(task.DeletedAt),
+	}
+	if task.Estimate != nil {
+		rtn.Estimate = &pb.Estimate{Value: task.Estimate.Value, Unit: string(task.Estimate.Unit)}
+	}
+	for _, item := range task.Checklist {
+		rtn.Checklist = append(rtn.Checklist, &pb.ChecklistItem{Id: item.ID, Text: item.Text, Checked: item.Checked})
+	}
+	return rtn
+}
+
+func toTaskEvent(event domain.TaskEvent) *pb.TaskEvent {
+	return &pb.TaskEvent{
+		Id:         event.ID,
+		Type:       string(event.Type),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+		Task:       toTask(event.Task),
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"oa-gogolook/internal/domain"
+)
+
+// errorDomain is the domain of the google.rpc.ErrorInfo detail of an error.
+const errorDomain = "oa-gogolook"
+
+var statusCodes = map[error]codes.Code{
+	domain.ErrInvalidParameters: codes.InvalidArgument,
+	domain.ErrInvalidPayload:    codes.InvalidArgument,
+	domain.ErrSystemError:       codes.Internal,
+	domain.ErrNetworkError:      codes.Unavailable,
+	domain.ErrUnauthorized:      codes.Unauthenticated,
+	domain.ErrServiceTimeout:    codes.DeadlineExceeded,
+	domain.ErrDataNotFound:      codes.NotFound,
+	domain.ErrWrongID:           codes.AlreadyExists,
+	domain.ErrTaskNameNotMatch:  codes.FailedPrecondition,
+	domain.ErrUserNotFound:      codes.NotFound,
+	domain.ErrNotCommentAuthor:  codes.PermissionDenied,
+	domain.ErrAttachmentTooBig:  codes.InvalidArgument,
+	domain.ErrChecklistNotFound: codes.NotFound,
+	domain.ErrChecklistOrder:    codes.InvalidArgument,
+	domain.ErrTaskNotDeleted:    codes.FailedPrecondition,
+	domain.ErrTaskArchived:      codes.FailedPrecondition,
+	domain.ErrTaskNotArchived:   codes.FailedPrecondition,
+	domain.ErrUndoNotFound:      codes.NotFound,
+	domain.ErrUndoExpired:       codes.FailedPrecondition,
+	domain.ErrUndoConflict:      codes.Aborted,
+	domain.ErrTimerRunning:      codes.FailedPrecondition,
+	domain.ErrTimerNotRunning:   codes.FailedPrecondition,
+	domain.ErrTaskNoDueDate:     codes.FailedPrecondition,
+	domain.ErrDeliveryNotDead:   codes.FailedPrecondition,
+	domain.ErrSyncTokenExpired:  codes.FailedPrecondition,
+	domain.ErrHistoryExpired:    codes.FailedPrecondition,
+}
+
+// statusError turns an error of the use case into a gRPC status carrying the ERR_TASK code
+// as the reason of an ErrorInfo detail. Errors that are no domain errors are logged and
+// answered with domain.ErrSystemError.
+func statusError(err error) error {
+	switch err {
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	e, ok := err.(domain.ErrorResponse)
+	if !ok {
+		log.Printf("grpc: %v", err)
+		e = domain.ErrSystemError
+	}
+	code, ok := statusCodes[e]
+	if !ok {
+		code = codes.Internal
+	}
+	st := status.New(code, e.Error())
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: e.Code(), Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,215 @@
+// Package grpc serves the task use case over gRPC, next to the HTTP API.
+package grpc
+
+import (
+	"context"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"oa-gogolook/internal/delevery/grpc/pb"
+	"oa-gogolook/internal/domain"
+	"strconv"
+	"strings"
+)
+
+type TaskServer struct {
+	pb.UnimplementedTaskServiceServer
+	taskUsecse domain.TaskUseCase
+	broker     domain.TaskEventBroker
+}
+
+func NewTaskServer(s *grpc.Server, taskUsecse domain.TaskUseCase, broker domain.TaskEventBroker) {
+	pb.RegisterTaskServiceServer(s, &TaskServer{
+		taskUsecse: taskUsecse,
+		broker:     broker,
+	})
+}
+
+// userIDMetadata is the metadata key of the caller's user ID, the counterpart of the
+// X-User-ID header.
+var userIDMetadata = strings.ToLower(domain.UserIDHeader)
+
+// EpochMetadata is the metadata key of the event epoch. Watch sends it in the response
+// header and a client resuming with last_event_id sends it back in the request.
+const EpochMetadata = "event-epoch"
+
+// actorContext adds the caller's user ID from the metadata to ctx.
+func actorContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(userIDMetadata)
+	if len(values) == 0 {
+		return ctx
+	}
+	id, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || id < 1 {
+		return ctx
+	}
+	return domain.ContextWithActor(ctx, id)
+}
+
+// UnaryActorInterceptor and StreamActorInterceptor record the caller as the actor of
+// the changes, like the actor middleware of the HTTP API.
+func UnaryActorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(actorContext(ctx), req)
+	}
+}
+
+func StreamActorInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &actorStream{ServerStream: ss, ctx: actorContext(ss.Context())})
+	}
+}
+
+type actorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *actorStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *TaskServer) List(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	listReq := domain.ListTaskRequest{
+		IncludeDeleted:  req.IncludeDeleted,
+		IncludeArchived: req.IncludeArchived,
+	}
+	if req.AsOf != nil {
+		listReq.AsOf = req.AsOf.AsTime()
+	}
+	rtn, err := s.taskUsecse.List(ctx, listReq)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	tasks := make([]*pb.Task, 0, len(rtn.Result))
+	for _, task := range rtn.Result {
+		tasks = append(tasks, toTask(task))
+	}
+	return &pb.ListTasksResponse{Tasks: tasks}, nil
+}
+
+func (s *TaskServer) Create(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
+	createReq := domain.CreateTaskRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Estimate:    fromEstimate(req.Estimate),
+		DueAt:       fromTimestamp(req.DueAt),
+		Tags:        req.Tags,
+	}
+	if err := binding.Validator.ValidateStruct(&createReq); err != nil {
+		return nil, statusError(domain.ErrInvalidPayload)
+	}
+	rtn, err := s.taskUsecse.Create(ctx, createReq)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.CreateTaskResponse{Task: toTask(rtn.Result)}, nil
+}
+
+func (s *TaskServer) Update(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	updateReq := domain.UpdateTaskRequest{
+		ID:          req.Id,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if req.Status != nil {
+		status := domain.Status(*req.Status)
+		updateReq.Status = &status
+	}
+	if err := binding.Validator.ValidateStruct(&updateReq); err != nil {
+		return nil, statusError(domain.ErrInvalidPayload)
+	}
+	rtn, err := s.taskUsecse.Update(ctx, updateReq)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.UpdateTaskResponse{Task: toTask(rtn.Result), UndoToken: rtn.UndoToken}, nil
+}
+
+func (s *TaskServer) Delete(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	if req.Id < 1 {
+		return nil, statusError(domain.ErrInvalidParameters)
+	}
+	rtn, err := s.taskUsecse.Delete(ctx, req.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.DeleteTaskResponse{UndoToken: rtn.UndoToken}, nil
+}
+
+func (s *TaskServer) Get(ctx context.Context, req *pb.GetTaskRequest) (*pb.GetTaskResponse, error) {
+	if req.Id < 1 {
+		return nil, statusError(domain.ErrInvalidParameters)
+	}
+	var task domain.Task
+	var err error
+	if req.AsOf == nil {
+		task, err = s.taskUsecse.Get(ctx, req.Id)
+	} else {
+		task, err = s.taskUsecse.GetAsOf(ctx, req.Id, req.AsOf.AsTime())
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.GetTaskResponse{Task: toTask(task)}, nil
+}
+
+// Watch sends the changes kept for a resuming client before the live ones. A client that
+// falls too far behind is cut off with Unavailable and resumes from its last event and
+// the epoch of the stream.
+func (s *TaskServer) Watch(req *pb.WatchTasksRequest, stream pb.TaskService_WatchServer) error {
+	if req.LastEventId != nil && *req.LastEventId < 0 {
+		return statusError(domain.ErrInvalidParameters)
+	}
+	wanted := make(map[int64]bool, len(req.TaskIds))
+	for _, id := range req.TaskIds {
+		wanted[id] = true
+	}
+	send := func(event domain.TaskEvent) error {
+		if len(wanted) > 0 && !wanted[event.Task.ID] {
+			return nil
+		}
+		return stream.Send(toTaskEvent(event))
+	}
+
+	var epoch string
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		if values := md.Get(EpochMetadata); len(values) > 0 {
+			epoch = values[0]
+		}
+	}
+	sub, cancel := s.broker.Subscribe(epoch, req.LastEventId)
+	defer cancel()
+	if err := stream.SendHeader(metadata.Pairs(EpochMetadata, sub.Epoch)); err != nil {
+		return err
+	}
+	if sub.Missed {
+		if err := stream.Send(&pb.TaskEvent{Id: sub.LastID, Type: domain.TaskEventReset}); err != nil {
+			return err
+		}
+	}
+	for _, event := range sub.Replay {
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "too slow")
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
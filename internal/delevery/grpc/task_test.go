@@ -0,0 +1,209 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"oa-gogolook/internal/delevery/grpc/pb"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/eventbus"
+	"oa-gogolook/internal/repository/inmemory"
+	"oa-gogolook/internal/stream"
+	"oa-gogolook/internal/usecase"
+	"testing"
+	"time"
+)
+
+const testTaskEventBuffer = 3
+
+func newTestClient(t *testing.T) (pb.TaskServiceClient, domain.TaskUseCase) {
+	events := eventbus.NewBus()
+	taskEvents := stream.NewBroker(testTaskEventBuffer)
+	events.Subscribe("task events", taskEvents.HandleTaskEvent)
+	historyRepository := inmemory.NewHistoryRepository()
+	u := usecase.NewHistoryTaskUsecase(
+		usecase.NewTaskUsecase(inmemory.NewTaskRepository(), inmemory.NewUserRepository(), inmemory.NewCommentRepository(), domain.CommentPolicyDelete, events),
+		historyRepository,
+	)
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryActorInterceptor()),
+		grpc.StreamInterceptor(StreamActorInterceptor()),
+	)
+	NewTaskServer(s, u, taskEvents)
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+		s.Stop()
+	})
+	return pb.NewTaskServiceClient(conn), u
+}
+
+// requireStatus checks the status code and the ERR_TASK code of err.
+func requireStatus(t *testing.T, err error, code codes.Code, want error) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, want.(domain.ErrorResponse).Code(), info.Reason)
+}
+
+func TestTaskServer(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), userIDMetadata, "7")
+
+	created, err := client.Create(ctx, &pb.CreateTaskRequest{Name: "task1", Tags: []string{"ops"}, Estimate: &pb.Estimate{Value: 3, Unit: "points"}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created.Task.Id)
+	require.Equal(t, "points", created.Task.Estimate.Unit)
+
+	status := int64(domain.StatusComplete)
+	updated, err := client.Update(ctx, &pb.UpdateTaskRequest{Id: 1, Name: "task1", Status: &status})
+	require.NoError(t, err)
+	require.Equal(t, status, updated.Task.Status)
+	require.NotNil(t, updated.Task.CompletedAt)
+
+	got, err := client.Get(ctx, &pb.GetTaskRequest{Id: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ops"}, got.Task.Tags)
+
+	_, err = client.Delete(ctx, &pb.DeleteTaskRequest{Id: 1})
+	require.NoError(t, err)
+	list, err := client.List(ctx, &pb.ListTasksRequest{})
+	require.NoError(t, err)
+	require.Empty(t, list.Tasks)
+	list, err = client.List(ctx, &pb.ListTasksRequest{IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, list.Tasks, 1)
+	require.NotNil(t, list.Tasks[0].DeletedAt)
+}
+
+func TestTaskServer_Errors(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	_, err := client.Create(ctx, &pb.CreateTaskRequest{Name: "task1"})
+	require.NoError(t, err)
+	status := int64(domain.StatusComplete)
+
+	_, err = client.Create(ctx, &pb.CreateTaskRequest{})
+	requireStatus(t, err, codes.InvalidArgument, domain.ErrInvalidPayload)
+	_, err = client.Create(ctx, &pb.CreateTaskRequest{Name: "task2", Estimate: &pb.Estimate{Value: 1, Unit: "days"}})
+	requireStatus(t, err, codes.InvalidArgument, domain.ErrInvalidPayload)
+	_, err = client.Update(ctx, &pb.UpdateTaskRequest{Id: 1, Name: "task1"})
+	requireStatus(t, err, codes.InvalidArgument, domain.ErrInvalidPayload)
+	_, err = client.Update(ctx, &pb.UpdateTaskRequest{Id: 1, Name: "other", Status: &status})
+	requireStatus(t, err, codes.FailedPrecondition, domain.ErrTaskNameNotMatch)
+	_, err = client.Get(ctx, &pb.GetTaskRequest{Id: 9})
+	requireStatus(t, err, codes.NotFound, domain.ErrDataNotFound)
+	_, err = client.Delete(ctx, &pb.DeleteTaskRequest{})
+	requireStatus(t, err, codes.InvalidArgument, domain.ErrInvalidParameters)
+}
+
+func TestTaskServer_Watch(t *testing.T) {
+	client, u := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := u.Create(ctx, domain.CreateTaskRequest{Name: "task1"})
+	require.NoError(t, err)
+	live, err := client.Watch(ctx, &pb.WatchTasksRequest{})
+	require.NoError(t, err)
+	header, err := live.Header()
+	require.NoError(t, err)
+	epoch := header.Get(EpochMetadata)
+	require.Len(t, epoch, 1)
+	// Resuming after the first event gets the later ones whether they happen before or
+	// after the subscription, task 1 is filtered out.
+	lastEventID := int64(1)
+	resumeCtx := metadata.AppendToOutgoingContext(ctx, EpochMetadata, epoch[0])
+	watch, err := client.Watch(resumeCtx, &pb.WatchTasksRequest{LastEventId: &lastEventID, TaskIds: []int64{2}})
+	require.NoError(t, err)
+	_, err = u.Delete(ctx, 1)
+	require.NoError(t, err)
+	_, err = u.Create(ctx, domain.CreateTaskRequest{Name: "task2"})
+	require.NoError(t, err)
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	require.Equal(t, string(domain.TaskEventCreated), event.Type)
+	require.Equal(t, int64(2), event.Task.Id)
+	require.Equal(t, int64(3), event.Id)
+
+	// An ID of another epoch was handed out before a restart.
+	restarted, err := client.Watch(metadata.AppendToOutgoingContext(ctx, EpochMetadata, "other"), &pb.WatchTasksRequest{LastEventId: &lastEventID})
+	require.NoError(t, err)
+	event, err = restarted.Recv()
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskEventReset, event.Type)
+	require.Equal(t, int64(3), event.Id)
+
+	for i := 0; i < testTaskEventBuffer+1; i++ {
+		_, err := u.Create(ctx, domain.CreateTaskRequest{Name: "filler"})
+		require.NoError(t, err)
+	}
+	missed, err := client.Watch(resumeCtx, &pb.WatchTasksRequest{LastEventId: &lastEventID})
+	require.NoError(t, err)
+	event, err = missed.Recv()
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskEventReset, event.Type)
+}
+
+func Test_statusError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		wantErr  error
+	}{
+		{
+			name:     "NotFound",
+			err:      domain.ErrDataNotFound,
+			wantCode: codes.NotFound,
+			wantErr:  domain.ErrDataNotFound,
+		},
+		{
+			name:     "UndoConflict",
+			err:      domain.ErrUndoConflict,
+			wantCode: codes.Aborted,
+			wantErr:  domain.ErrUndoConflict,
+		},
+		{
+			name:     "UnmappedDomainError",
+			err:      domain.NewErrorResponse("ERR_TASK_9999", "unmapped"),
+			wantCode: codes.Internal,
+			wantErr:  domain.NewErrorResponse("ERR_TASK_9999", "unmapped"),
+		},
+		{
+			name:     "OtherError",
+			err:      errors.New("disk full"),
+			wantCode: codes.Internal,
+			wantErr:  domain.ErrSystemError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requireStatus(t, statusError(tt.err), tt.wantCode, tt.wantErr)
+		})
+	}
+	require.Equal(t, codes.Canceled, status.Code(statusError(context.Canceled)))
+}
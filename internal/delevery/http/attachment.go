@@ -0,0 +1,159 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"net/http"
+	"oa-gogolook/internal/domain"
+	"path/filepath"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and headers around the file part.
+const multipartOverhead = 1 << 20
+
+// limitedBody counts what was read through http.MaxBytesReader, reaching the limit tells a
+// too large request apart from a broken one when the request is chunked.
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *limitedBody) exceeded() bool {
+	return b.read >= b.limit
+}
+
+type AttachmentHandler struct {
+	attachmentUsecase domain.AttachmentUseCase
+	maxSize           int64
+}
+
+func NewAttachmentHandler(e *gin.Engine, attachmentUsecase domain.AttachmentUseCase, maxSize int64) {
+	h := &AttachmentHandler{
+		attachmentUsecase: attachmentUsecase,
+		maxSize:           maxSize,
+	}
+	e.GET("/task/:task_id/attachments", h.List)
+	e.POST("/task/:task_id/attachments", h.Upload)
+	e.GET("/task/:task_id/attachments/:attachment_id", h.Download)
+	e.DELETE("/task/:task_id/attachments/:attachment_id", h.Delete)
+}
+
+func (h *AttachmentHandler) List(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.attachmentUsecase.List(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+// Upload streams the "file" part of a multipart request without buffering it in memory.
+func (h *AttachmentHandler) Upload(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if ctx.Request.ContentLength > h.maxSize+multipartOverhead {
+		ctx.JSON(http.StatusRequestEntityTooLarge, domain.ErrAttachmentTooBig)
+		return
+	}
+	body := &limitedBody{limit: h.maxSize + multipartOverhead}
+	body.ReadCloser = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, body.limit)
+	ctx.Request.Body = body
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil && body.exceeded() {
+			ctx.JSON(http.StatusRequestEntityTooLarge, domain.ErrAttachmentTooBig)
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			continue
+		}
+
+		rtn, err := h.attachmentUsecase.Upload(ctx, para.ID, domain.UploadAttachmentRequest{
+			FileName:    filepath.Base(part.FileName()),
+			ContentType: part.Header.Get("Content-Type"),
+			Content:     part,
+		})
+		if err != nil && body.exceeded() {
+			ctx.JSON(http.StatusRequestEntityTooLarge, domain.ErrAttachmentTooBig)
+			return
+		}
+		if err != nil {
+			h.handleError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, rtn)
+		return
+	}
+}
+
+// Download serves the attachment content, http.ServeContent takes care of Range requests.
+func (h *AttachmentHandler) Download(ctx *gin.Context) {
+	var para domain.AttachmentUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	attachment, content, err := h.attachmentUsecase.Open(ctx, para.TaskID, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	defer content.Close()
+	ctx.Header("Content-Type", attachment.ContentType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(ctx.Writer, ctx.Request, attachment.FileName, attachment.CreatedAt, content)
+}
+
+func (h *AttachmentHandler) Delete(ctx *gin.Context) {
+	var para domain.AttachmentUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	err := h.attachmentUsecase.Delete(ctx, para.TaskID, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (h *AttachmentHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	case domain.ErrAttachmentTooBig:
+		ctx.JSON(http.StatusRequestEntityTooLarge, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
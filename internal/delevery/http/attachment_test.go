@@ -0,0 +1,196 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	httpdelivery "oa-gogolook/internal/delevery/http"
+	"oa-gogolook/internal/domain"
+	"strings"
+	"testing"
+)
+
+func newUploadRequest(t *testing.T, taskID int64, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="notes.txt"`)
+	header.Set("Content-Type", "text/plain")
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/task/%d/attachments", taskID), body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		content       string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			taskID:  1,
+			content: "hello attachment",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var rtn domain.UploadAttachmentResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, "notes.txt", rtn.Result.FileName)
+				require.Equal(t, "text/plain", rtn.Result.ContentType)
+				require.Equal(t, int64(len("hello attachment")), rtn.Result.Size)
+			},
+		},
+		{
+			name:    "TooLarge",
+			taskID:  1,
+			content: strings.Repeat("x", testMaxAttachmentSize+1),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:    "TaskNotFound",
+			taskID:  5,
+			content: "hello attachment",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			recorder := httptest.NewRecorder()
+			server.Router.ServeHTTP(recorder, newUploadRequest(t, tt.taskID, tt.content))
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAttachmentHandler_UploadNotMultipart(t *testing.T) {
+	server := newTestServer(t)
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/task/1/attachments", strings.NewReader("{}"))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		rangeHeader   string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  "/task/1/attachments/1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+				require.Equal(t, "0123456789", recorder.Body.String())
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "notes.txt")
+			},
+		},
+		{
+			name:        "Range",
+			url:         "/task/1/attachments/1",
+			rangeHeader: "bytes=2-5",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPartialContent, recorder.Code)
+				require.Equal(t, "2345", recorder.Body.String())
+				require.Equal(t, "bytes 2-5/10", recorder.Header().Get("Content-Range"))
+			},
+		},
+		{
+			name: "WrongTask",
+			url:  "/task/2/attachments/1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			upload := httptest.NewRecorder()
+			server.Router.ServeHTTP(upload, newUploadRequest(t, 1, "0123456789"))
+			require.Equal(t, http.StatusCreated, upload.Code)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			if tt.rangeHeader != "" {
+				request.Header.Set("Range", tt.rangeHeader)
+			}
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAttachmentHandler_ListAndDelete(t *testing.T) {
+	server := newTestServer(t)
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	upload := httptest.NewRecorder()
+	server.Router.ServeHTTP(upload, newUploadRequest(t, 1, "0123456789"))
+	require.Equal(t, http.StatusCreated, upload.Code)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodDelete, "/task/1/attachments/1", nil)
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/task/1/attachments", nil)
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rtn domain.ListAttachmentResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rtn))
+	require.Equal(t, 0, len(rtn.Result))
+}
+
+func TestAttachmentHandler_UploadChunkedTooLarge(t *testing.T) {
+	server := newTestServer(t)
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("note", strings.Repeat("x", httpdelivery.MultipartOverhead)))
+	part, err := writer.CreateFormFile("file", "notes.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(strings.Repeat("x", testMaxAttachmentSize)))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	request, err := http.NewRequest(http.MethodPost, "/task/1/attachments", ioutil.NopCloser(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.ContentLength = -1
+
+	recorder := httptest.NewRecorder()
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type ChecklistHandler struct {
+	checklistUsecase domain.ChecklistUseCase
+}
+
+func NewChecklistHandler(e *gin.Engine, checklistUsecase domain.ChecklistUseCase) {
+	h := &ChecklistHandler{
+		checklistUsecase: checklistUsecase,
+	}
+	e.POST("/task/:task_id/checklist", h.AddItem)
+	e.PUT("/task/:task_id/checklist/order", h.Reorder)
+	e.POST("/task/:task_id/checklist/:item_id/toggle", h.ToggleItem)
+	e.DELETE("/task/:task_id/checklist/:item_id", h.RemoveItem)
+}
+
+func (h *ChecklistHandler) AddItem(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.AddChecklistItemRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.checklistUsecase.AddItem(ctx, para.ID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *ChecklistHandler) Reorder(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.ReorderChecklistRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.checklistUsecase.Reorder(ctx, para.ID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *ChecklistHandler) ToggleItem(ctx *gin.Context) {
+	var para domain.ChecklistItemUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.checklistUsecase.ToggleItem(ctx, para.TaskID, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *ChecklistHandler) RemoveItem(ctx *gin.Context) {
+	var para domain.ChecklistItemUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.checklistUsecase.RemoveItem(ctx, para.TaskID, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *ChecklistHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound, domain.ErrChecklistNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	case domain.ErrChecklistOrder:
+		ctx.JSON(http.StatusBadRequest, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
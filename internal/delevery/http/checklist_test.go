@@ -0,0 +1,77 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func TestChecklistHandler(t *testing.T) {
+	server := newTestServer(t)
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+
+	do := func(method string, url string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			var err error
+			data, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+		request, err := http.NewRequest(method, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		server.Router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := do(http.MethodPost, "/task/1/checklist", domain.AddChecklistItemRequest{Text: "item1"})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	recorder = do(http.MethodPost, "/task/1/checklist", domain.AddChecklistItemRequest{Text: "item2"})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder = do(http.MethodPost, "/task/1/checklist/2/toggle", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rtn struct {
+		Result struct {
+			Checklist []domain.ChecklistItem `json:"checklist"`
+			Progress  int                    `json:"progress"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rtn))
+	require.Equal(t, 50, rtn.Result.Progress)
+
+	recorder = do(http.MethodPut, "/task/1/checklist/order", domain.ReorderChecklistRequest{ItemIDs: []int64{2, 1}})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rtn))
+	require.Equal(t, "item2", rtn.Result.Checklist[0].Text)
+
+	recorder = do(http.MethodPut, "/task/1/checklist/order", domain.ReorderChecklistRequest{ItemIDs: []int64{2}})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = do(http.MethodDelete, "/task/1/checklist/2", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rtn))
+	require.Equal(t, 1, len(rtn.Result.Checklist))
+	require.Equal(t, 0, rtn.Result.Progress)
+
+	recorder = do(http.MethodDelete, "/task/1/checklist/2", nil)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = do(http.MethodPost, "/task/5/checklist", domain.AddChecklistItemRequest{Text: "item1"})
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTask_MarshalJSONProgress(t *testing.T) {
+	data, err := json.Marshal(domain.Task{ID: 1, Name: "TaskName1"})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "progress")
+
+	data, err = json.Marshal(domain.Task{ID: 1, Name: "TaskName1", Checklist: []domain.ChecklistItem{{ID: 1, Checked: true}, {ID: 2}}})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"progress":50`)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type CommentHandler struct {
+	commentUsecase domain.CommentUseCase
+}
+
+func NewCommentHandler(e *gin.Engine, commentUsecase domain.CommentUseCase) {
+	h := &CommentHandler{
+		commentUsecase: commentUsecase,
+	}
+	e.GET("/task/:task_id/comments", h.List)
+	e.POST("/task/:task_id/comments", h.Create)
+	e.PUT("/task/:task_id/comments/:comment_id", h.Update)
+	e.DELETE("/task/:task_id/comments/:comment_id", h.Delete)
+}
+
+func (h *CommentHandler) List(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.ListCommentRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.commentUsecase.List(ctx, para.ID, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *CommentHandler) Create(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.CreateCommentRequest
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.commentUsecase.Create(ctx, para.ID, userID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *CommentHandler) Update(ctx *gin.Context) {
+	var para domain.CommentUriParameter
+	var req domain.UpdateCommentRequest
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.commentUsecase.Update(ctx, para.TaskID, para.ID, userID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *CommentHandler) Delete(ctx *gin.Context) {
+	var para domain.CommentUriParameter
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	err := h.commentUsecase.Delete(ctx, para.TaskID, para.ID, userID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (h *CommentHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	case domain.ErrUserNotFound:
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+	case domain.ErrNotCommentAuthor:
+		ctx.JSON(http.StatusForbidden, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,210 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func buildCommentStubs(s TestServer) {
+	_, _ = s.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	_, _ = s.User.Create(context.Background(), domain.CreateUserRequest{Name: "UserName1"})
+	_, _ = s.User.Create(context.Background(), domain.CreateUserRequest{Name: "UserName2"})
+}
+
+func TestCommentHandler_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		userID        string
+		query         domain.CreateCommentRequest
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			taskID: 1,
+			userID: "1",
+			query:  domain.CreateCommentRequest{Body: "first"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var rtn domain.CreateCommentResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, "first", rtn.Result.Body)
+				require.Equal(t, int64(1), rtn.Result.AuthorID)
+			},
+		},
+		{
+			name:   "Unauthorized",
+			taskID: 1,
+			userID: "",
+			query:  domain.CreateCommentRequest{Body: "first"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "TaskNotFound",
+			taskID: 5,
+			userID: "1",
+			query:  domain.CreateCommentRequest{Body: "first"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "EmptyBody",
+			taskID: 1,
+			userID: "1",
+			query:  domain.CreateCommentRequest{Body: ""},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			buildCommentStubs(server)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/task/%d/comments", tt.taskID)
+			data, err := json.Marshal(tt.query)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			if tt.userID != "" {
+				request.Header.Set(domain.UserIDHeader, tt.userID)
+			}
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCommentHandler_List(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?page=2&pageSize=2",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.ListCommentResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, 1, len(rtn.Result))
+				require.Equal(t, 3, rtn.Total)
+				require.Equal(t, "body3", rtn.Result[0].Body)
+			},
+		},
+		{
+			name:  "BadPageSize",
+			query: "?pageSize=1000",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			buildCommentStubs(server)
+			for i := 1; i <= 3; i++ {
+				_, _ = server.Comment.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Body: fmt.Sprintf("body%d", i)})
+			}
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/task/1/comments"+tt.query, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCommentHandler_Update(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: "1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.UpdateCommentResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, "edited", rtn.Result.Body)
+			},
+		},
+		{
+			name:   "NotAuthor",
+			userID: "2",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			buildCommentStubs(server)
+			_, _ = server.Comment.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Body: "body"})
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(domain.UpdateCommentRequest{Body: "edited"})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPut, "/task/1/comments/1", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(domain.UserIDHeader, tt.userID)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCommentHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		commentID     int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			commentID: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			commentID: 5,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			buildCommentStubs(server)
+			_, _ = server.Comment.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Body: "body"})
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/task/1/comments/%d", tt.commentID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			request.Header.Set(domain.UserIDHeader, "1")
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package http
+
+// MultipartOverhead lets the tests of package http_test build a request just over the
+// limit.
+const MultipartOverhead = multipartOverhead
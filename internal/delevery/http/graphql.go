@@ -0,0 +1,209 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+// graphQLListCost is how many times the selection of a list field counts towards the
+// complexity of a query, one for every item it is expected to return. Introspection is
+// answered from the schema, its lists count once.
+const graphQLListCost = 10
+
+type GraphQLHandler struct {
+	schema        graphql.Schema
+	maxDepth      int
+	maxComplexity int
+}
+
+// NewGraphQLHandler serves the task queries and mutations at /graphql. A query nested more
+// than maxDepth fields deep or more complex than maxComplexity is rejected before it runs.
+func NewGraphQLHandler(e *gin.Engine, taskUsecse domain.TaskUseCase, maxDepth int, maxComplexity int) error {
+	schema, err := newTaskSchema(taskUsecse)
+	if err != nil {
+		return err
+	}
+	h := &GraphQLHandler{
+		schema:        schema,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}
+	e.POST("/graphql", h.Query)
+	return nil
+}
+
+func (h *GraphQLHandler) Query(ctx *gin.Context) {
+	var req domain.GraphQLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, graphQLErrors(domain.ErrInvalidPayload, nil))
+		return
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, graphQLErrors(domain.ErrInvalidPayload, nil, gqlerrors.FormatError(err)))
+		return
+	}
+	if result := graphql.ValidateDocument(&h.schema, doc, nil); !result.IsValid {
+		ctx.JSON(http.StatusBadRequest, graphQLErrors(domain.ErrInvalidPayload, nil, result.Errors...))
+		return
+	}
+	m := queryMeasure{schema: h.schema, fragments: make(map[string]*ast.FragmentDefinition)}
+	depth, complexity := m.document(doc, req.OperationName)
+	if depth > h.maxDepth {
+		ctx.JSON(http.StatusBadRequest, graphQLErrors(domain.ErrQueryTooDeep, map[string]interface{}{
+			"depth":    depth,
+			"maxDepth": h.maxDepth,
+		}))
+		return
+	}
+	if complexity > h.maxComplexity {
+		ctx.JSON(http.StatusBadRequest, graphQLErrors(domain.ErrQueryTooComplex, map[string]interface{}{
+			"complexity":    complexity,
+			"maxComplexity": h.maxComplexity,
+		}))
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	ctx.JSON(http.StatusOK, result)
+}
+
+// graphQLErrors answers a request rejected before it ran, every error carries the code of
+// reason and the extra extensions. Without errors the message of reason is the error.
+func graphQLErrors(reason domain.ErrorResponse, extensions map[string]interface{}, errs ...gqlerrors.FormattedError) *graphql.Result {
+	if len(errs) == 0 {
+		errs = []gqlerrors.FormattedError{{Message: reason.Error()}}
+	}
+	for i := range errs {
+		errs[i].Extensions = map[string]interface{}{"code": reason.Code()}
+		for k, v := range extensions {
+			errs[i].Extensions[k] = v
+		}
+	}
+	return &graphql.Result{Errors: errs}
+}
+
+// queryMeasure works out the depth and the complexity of an operation. Every field adds
+// one to the complexity, the selection of a list field counts listCost times.
+type queryMeasure struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+func (m *queryMeasure) document(doc *ast.Document, operationName string) (int, int) {
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			m.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operation == nil && (operationName == "" || d.Name != nil && d.Name.Value == operationName) {
+				operation = d
+			}
+		}
+	}
+	if operation == nil {
+		// Execute reports the unknown operation.
+		return 0, 0
+	}
+	var root graphql.Type = m.schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = m.schema.MutationType()
+	}
+	return m.selectionSet(operation.SelectionSet, root, graphQLListCost, make(map[string]bool))
+}
+
+// selectionSet measures the selections on parent, visiting tracks the fragments being
+// expanded so a fragment cycle can not recurse forever.
+func (m *queryMeasure) selectionSet(set *ast.SelectionSet, parent graphql.Type, listCost int, visiting map[string]bool) (depth int, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			var fieldType graphql.Type
+			if def := fieldDefinition(m.schema, parent, s.Name.Value); def != nil {
+				fieldType = def.Type
+			}
+			var named graphql.Type
+			if fieldType != nil {
+				named, _ = graphql.GetNamed(fieldType).(graphql.Type)
+			}
+			cost := listCost
+			if s.Name.Value == graphql.SchemaMetaFieldDef.Name || s.Name.Value == graphql.TypeMetaFieldDef.Name {
+				cost = 1
+			}
+			d, c = m.selectionSet(s.SelectionSet, named, cost, visiting)
+			if isListType(fieldType) {
+				c *= cost
+			}
+			d, c = d+1, c+1
+		case *ast.InlineFragment:
+			t := parent
+			if s.TypeCondition != nil {
+				t = m.schema.Type(s.TypeCondition.Name.Value)
+			}
+			d, c = m.selectionSet(s.SelectionSet, t, listCost, visiting)
+		case *ast.FragmentSpread:
+			f, ok := m.fragments[s.Name.Value]
+			if !ok || visiting[f.Name.Value] {
+				continue
+			}
+			visiting[f.Name.Value] = true
+			d, c = m.selectionSet(f.SelectionSet, m.schema.Type(f.TypeCondition.Name.Value), listCost, visiting)
+			delete(visiting, f.Name.Value)
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+func fieldDefinition(schema graphql.Schema, parent graphql.Type, name string) *graphql.FieldDefinition {
+	switch name {
+	case graphql.TypeNameMetaFieldDef.Name:
+		return graphql.TypeNameMetaFieldDef
+	case graphql.SchemaMetaFieldDef.Name:
+		if parent == schema.QueryType() {
+			return graphql.SchemaMetaFieldDef
+		}
+	case graphql.TypeMetaFieldDef.Name:
+		if parent == schema.QueryType() {
+			return graphql.TypeMetaFieldDef
+		}
+	}
+	switch t := parent.(type) {
+	case *graphql.Object:
+		return t.Fields()[name]
+	case *graphql.Interface:
+		return t.Fields()[name]
+	}
+	return nil
+}
+
+func isListType(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
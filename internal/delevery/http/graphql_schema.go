@@ -0,0 +1,389 @@
+package http
+
+import (
+	"context"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graphql-go/graphql"
+	"log"
+	"oa-gogolook/internal/domain"
+	"strconv"
+	"time"
+)
+
+// graphQLError carries the ERR_TASK code of a resolver error in the "code" extension.
+type graphQLError struct {
+	domain.ErrorResponse
+}
+
+func (e graphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code()}
+}
+
+// resolveError hands domain errors to the client, any other error is logged and answered
+// with domain.ErrSystemError.
+func resolveError(err error) error {
+	if e, ok := err.(domain.ErrorResponse); ok {
+		return graphQLError{e}
+	}
+	log.Printf("graphql: %v", err)
+	return graphQLError{domain.ErrSystemError}
+}
+
+// parseID reads an ID argument, IDs are int64 sent as strings.
+func parseID(value interface{}) (int64, error) {
+	s, _ := value.(string)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 1 {
+		return 0, graphQLError{domain.ErrInvalidParameters}
+	}
+	return id, nil
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func timeOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// taskField resolves a field of a task with fn.
+func taskField(fieldType graphql.Output, fn func(task domain.Task) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return fn(p.Source.(domain.Task)), nil
+		},
+	}
+}
+
+func newTaskSchema(taskUsecse domain.TaskUseCase) (graphql.Schema, error) {
+	estimateType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Estimate",
+		Fields: graphql.Fields{
+			"value": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Float),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(domain.Estimate).Value, nil
+				},
+			},
+			"unit": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return string(p.Source.(domain.Estimate).Unit), nil
+				},
+			},
+		},
+	})
+	checklistItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ChecklistItem",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return formatID(p.Source.(domain.ChecklistItem).ID), nil
+				},
+			},
+			"text":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"checked": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+	taskType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.Fields{
+			"id": taskField(graphql.NewNonNull(graphql.ID), func(task domain.Task) interface{} {
+				return formatID(task.ID)
+			}),
+			"status": taskField(graphql.NewNonNull(graphql.Int), func(task domain.Task) interface{} {
+				return int(task.Status)
+			}),
+			"name": taskField(graphql.NewNonNull(graphql.String), func(task domain.Task) interface{} {
+				return task.Name
+			}),
+			"description": taskField(graphql.NewNonNull(graphql.String), func(task domain.Task) interface{} {
+				return task.Description
+			}),
+			"assigneeId": taskField(graphql.ID, func(task domain.Task) interface{} {
+				if task.AssigneeID == nil {
+					return nil
+				}
+				return formatID(*task.AssigneeID)
+			}),
+			"estimate": taskField(estimateType, func(task domain.Task) interface{} {
+				if task.Estimate == nil {
+					return nil
+				}
+				return *task.Estimate
+			}),
+			"dueAt": taskField(graphql.DateTime, func(task domain.Task) interface{} {
+				return timeOrNil(task.DueAt)
+			}),
+			"tags": taskField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), func(task domain.Task) interface{} {
+				if task.Tags == nil {
+					return []string{}
+				}
+				return task.Tags
+			}),
+			"checklist": taskField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(checklistItemType))), func(task domain.Task) interface{} {
+				if task.Checklist == nil {
+					return []domain.ChecklistItem{}
+				}
+				return task.Checklist
+			}),
+			"rank": taskField(graphql.NewNonNull(graphql.String), func(task domain.Task) interface{} {
+				return task.Rank
+			}),
+			"createdAt": taskField(graphql.NewNonNull(graphql.DateTime), func(task domain.Task) interface{} {
+				return task.CreatedAt
+			}),
+			"completedAt": taskField(graphql.DateTime, func(task domain.Task) interface{} {
+				return timeOrNil(task.CompletedAt)
+			}),
+			"archivedAt": taskField(graphql.DateTime, func(task domain.Task) interface{} {
+				return timeOrNil(task.ArchivedAt)
+			}),
+			"deletedAt": taskField(graphql.DateTime, func(task domain.Task) interface{} {
+				return timeOrNil(task.DeletedAt)
+			}),
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"task": &graphql.Field{
+				Type:        taskType,
+				Description: "The task with the given ID, as it was at asOf when it is set.",
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"asOf": &graphql.ArgumentConfig{Type: graphql.DateTime},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, err
+					}
+					var task domain.Task
+					if asOf, ok := p.Args["asOf"].(time.Time); ok {
+						task, err = taskUsecse.GetAsOf(p.Context, id, asOf)
+					} else {
+						task, err = taskUsecse.Get(p.Context, id)
+					}
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					return task, nil
+				},
+			},
+			"tasks": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(taskType))),
+				Description: "The tasks in rank order, narrowed down by the given filters.",
+				Args: graphql.FieldConfigArgument{
+					"includeDeleted":  &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+					"includeArchived": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+					"asOf":            &graphql.ArgumentConfig{Type: graphql.DateTime},
+					"status":          &graphql.ArgumentConfig{Type: graphql.Int},
+					"assigneeId":      &graphql.ArgumentConfig{Type: graphql.ID},
+					"tag":             &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return listTasks(p.Context, taskUsecse, p.Args)
+				},
+			},
+		},
+	})
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": &graphql.Field{
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+						Name: "CreateTaskInput",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+							"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+							"estimate": &graphql.InputObjectFieldConfig{Type: graphql.NewInputObject(graphql.InputObjectConfig{
+								Name: "EstimateInput",
+								Fields: graphql.InputObjectConfigFieldMap{
+									"value": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+									"unit":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+								},
+							})},
+							"dueAt": &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+							"tags":  &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+						},
+					}))},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					input, _ := p.Args["input"].(map[string]interface{})
+					req := domain.CreateTaskRequest{}
+					req.Name, _ = input["name"].(string)
+					req.Description, _ = input["description"].(string)
+					if estimate, ok := input["estimate"].(map[string]interface{}); ok {
+						value, _ := estimate["value"].(float64)
+						unit, _ := estimate["unit"].(string)
+						req.Estimate = &domain.Estimate{Value: value, Unit: domain.EstimateUnit(unit)}
+					}
+					if dueAt, ok := input["dueAt"].(time.Time); ok {
+						req.DueAt = &dueAt
+					}
+					if tags, ok := input["tags"].([]interface{}); ok {
+						for _, tag := range tags {
+							s, _ := tag.(string)
+							req.Tags = append(req.Tags, s)
+						}
+					}
+					if err := binding.Validator.ValidateStruct(&req); err != nil {
+						return nil, graphQLError{domain.ErrInvalidPayload}
+					}
+					rtn, err := taskUsecse.Create(p.Context, req)
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					return rtn.Result, nil
+				},
+			},
+			"updateTask": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+					Name: "UpdateTaskPayload",
+					Fields: graphql.Fields{
+						"task": &graphql.Field{
+							Type: graphql.NewNonNull(taskType),
+							Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+								return p.Source.(domain.UpdateTaskResponse).Result, nil
+							},
+						},
+						"undoToken": &graphql.Field{
+							Type: graphql.String,
+							Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+								return p.Source.(domain.UpdateTaskResponse).UndoToken, nil
+							},
+						},
+					},
+				})),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+						Name:        "UpdateTaskInput",
+						Description: "name must match the current name of the task, description is left unchanged when it is not set.",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"id":          &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+							"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+							"status":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+							"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+						},
+					}))},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					input, _ := p.Args["input"].(map[string]interface{})
+					id, err := parseID(input["id"])
+					if err != nil {
+						return nil, err
+					}
+					req := domain.UpdateTaskRequest{ID: id}
+					req.Name, _ = input["name"].(string)
+					if status, ok := input["status"].(int); ok {
+						s := domain.Status(status)
+						req.Status = &s
+					}
+					if description, ok := input["description"].(string); ok {
+						req.Description = &description
+					}
+					if err := binding.Validator.ValidateStruct(&req); err != nil {
+						return nil, graphQLError{domain.ErrInvalidPayload}
+					}
+					rtn, err := taskUsecse.Update(p.Context, req)
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					return rtn, nil
+				},
+			},
+			"deleteTask": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+					Name: "DeleteTaskPayload",
+					Fields: graphql.Fields{
+						"undoToken": &graphql.Field{
+							Type: graphql.String,
+							Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+								return p.Source.(domain.DeleteTaskResponse).UndoToken, nil
+							},
+						},
+					},
+				})),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := parseID(p.Args["id"])
+					if err != nil {
+						return nil, err
+					}
+					rtn, err := taskUsecse.Delete(p.Context, id)
+					if err != nil {
+						return nil, resolveError(err)
+					}
+					return rtn, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	})
+}
+
+// listTasks lists the tasks the way GET /tasks does and applies the filters the use case
+// does not know.
+func listTasks(ctx context.Context, taskUsecse domain.TaskUseCase, args map[string]interface{}) (interface{}, error) {
+	req := domain.ListTaskRequest{}
+	req.IncludeDeleted, _ = args["includeDeleted"].(bool)
+	req.IncludeArchived, _ = args["includeArchived"].(bool)
+	if asOf, ok := args["asOf"].(time.Time); ok {
+		req.AsOf = asOf
+	}
+	var assigneeID int64
+	if value, ok := args["assigneeId"]; ok && value != nil {
+		id, err := parseID(value)
+		if err != nil {
+			return nil, err
+		}
+		assigneeID = id
+	}
+	status, filterStatus := args["status"].(int)
+	tag, filterTag := args["tag"].(string)
+
+	rtn, err := taskUsecse.List(ctx, req)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	tasks := make([]domain.Task, 0, len(rtn.Result))
+	for _, task := range rtn.Result {
+		if filterStatus && task.Status != domain.Status(status) {
+			continue
+		}
+		if assigneeID != 0 && (task.AssigneeID == nil || *task.AssigneeID != assigneeID) {
+			continue
+		}
+		if filterTag && !hasTag(task, tag) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func hasTag(task domain.Task, tag string) bool {
+	for _, t := range task.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
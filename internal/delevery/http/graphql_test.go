@@ -0,0 +1,196 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/graphql-go/graphql/testutil"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"strings"
+	"testing"
+)
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func decodeGraphQL(t *testing.T, recorder *httptest.ResponseRecorder) graphQLResponse {
+	var got graphQLResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	return got
+}
+
+func requireGraphQLCode(t *testing.T, recorder *httptest.ResponseRecorder, status int, code string) {
+	require.Equal(t, status, recorder.Code, recorder.Body.String())
+	got := decodeGraphQL(t, recorder)
+	require.NotEmpty(t, got.Errors)
+	require.Equal(t, code, got.Errors[0].Extensions["code"])
+}
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	var tooComplex strings.Builder
+	tooComplex.WriteString("{")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&tooComplex, " t%d: tasks { id name status description rank tags }", i)
+	}
+	tooComplex.WriteString(" }")
+
+	tests := []struct {
+		name          string
+		body          domain.GraphQLRequest
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Task",
+			body: domain.GraphQLRequest{Query: `{ task(id: "1") { id name status assigneeId tags checklist { id } } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"data":{"task":{"id":"1","name":"TaskName1","status":1,"assigneeId":null,"tags":["work"],"checklist":[]}}}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "TaskNotFound",
+			body: domain.GraphQLRequest{Query: `{ task(id: "9") { id } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusOK, domain.ErrDataNotFound.Code())
+			},
+		},
+		{
+			name: "TasksFiltered",
+			body: domain.GraphQLRequest{
+				Query:     `query Done($status: Int) { tasks(status: $status) { name } }`,
+				Variables: map[string]interface{}{"status": 0},
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"data":{"tasks":[{"name":"TaskName2"}]}}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "TasksByTag",
+			body: domain.GraphQLRequest{Query: `{ tasks(tag: "work") { name } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"data":{"tasks":[{"name":"TaskName1"}]}}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "CreateTask",
+			body: domain.GraphQLRequest{
+				Query:     `mutation Create($input: CreateTaskInput!) { createTask(input: $input) { id name estimate { value unit } } }`,
+				Variables: map[string]interface{}{"input": map[string]interface{}{"name": "TaskName3", "estimate": map[string]interface{}{"value": 2, "unit": "hours"}}},
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"data":{"createTask":{"id":"3","name":"TaskName3","estimate":{"value":2,"unit":"hours"}}}}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "CreateTaskInvalid",
+			body: domain.GraphQLRequest{Query: `mutation { createTask(input: {name: ""}) { id } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusOK, domain.ErrInvalidPayload.Code())
+			},
+		},
+		{
+			name: "UpdateTask",
+			body: domain.GraphQLRequest{Query: `mutation { updateTask(input: {id: "2", name: "TaskName2", status: 1}) { task { status completedAt } undoToken } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got struct {
+					UpdateTask struct {
+						Task struct {
+							Status      int     `json:"status"`
+							CompletedAt *string `json:"completedAt"`
+						} `json:"task"`
+						UndoToken string `json:"undoToken"`
+					} `json:"updateTask"`
+				}
+				require.NoError(t, json.Unmarshal(decodeGraphQL(t, recorder).Data["updateTask"], &got.UpdateTask))
+				require.Equal(t, 1, got.UpdateTask.Task.Status)
+				require.NotNil(t, got.UpdateTask.Task.CompletedAt)
+				require.NotEmpty(t, got.UpdateTask.UndoToken)
+			},
+		},
+		{
+			name: "UpdateTaskNameNotMatch",
+			body: domain.GraphQLRequest{Query: `mutation { updateTask(input: {id: "2", name: "Other", status: 1}) { undoToken } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusOK, domain.ErrTaskNameNotMatch.Code())
+			},
+		},
+		{
+			name: "DeleteTask",
+			body: domain.GraphQLRequest{Query: `mutation { deleteTask(id: "1") { undoToken } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotEmpty(t, decodeGraphQL(t, recorder).Data["deleteTask"])
+				require.Empty(t, decodeGraphQL(t, recorder).Errors)
+			},
+		},
+		{
+			name: "SyntaxError",
+			body: domain.GraphQLRequest{Query: `{ task(id: "1") { id }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusBadRequest, domain.ErrInvalidPayload.Code())
+			},
+		},
+		{
+			name: "UnknownField",
+			body: domain.GraphQLRequest{Query: `{ task(id: "1") { owner } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusBadRequest, domain.ErrInvalidPayload.Code())
+			},
+		},
+		{
+			name: "TooDeep",
+			body: domain.GraphQLRequest{Query: `{ __schema { types { fields { type { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { name } } } } } } } } } } } } } } } } }`},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusBadRequest, domain.ErrQueryTooDeep.Code())
+				require.EqualValues(t, 17, decodeGraphQL(t, recorder).Errors[0].Extensions["depth"])
+			},
+		},
+		{
+			name: "TooComplex",
+			body: domain.GraphQLRequest{Query: tooComplex.String()},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireGraphQLCode(t, recorder, http.StatusBadRequest, domain.ErrQueryTooComplex.Code())
+				require.EqualValues(t, 40*61, decodeGraphQL(t, recorder).Errors[0].Extensions["complexity"])
+			},
+		},
+		{
+			name: "Introspection",
+			body: domain.GraphQLRequest{Query: testutil.IntrospectionQuery},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				got := decodeGraphQL(t, recorder)
+				require.Empty(t, got.Errors)
+				require.Contains(t, string(got.Data["__schema"]), `"CreateTaskInput"`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1", Tags: []string{"work"}})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			done := domain.Status(1)
+			_, _ = server.U.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Name: "TaskName1", Status: &done})
+			body, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type HistoryHandler struct {
+	historyUsecase domain.HistoryUseCase
+}
+
+func NewHistoryHandler(e *gin.Engine, historyUsecase domain.HistoryUseCase) {
+	h := &HistoryHandler{
+		historyUsecase: historyUsecase,
+	}
+	e.GET("/task/:task_id/history", h.List)
+}
+
+// ActorMiddleware stores the caller's user ID from the X-User-ID header in the request
+// context, so use cases can attribute changes via domain.ActorFromContext.
+func ActorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if userID, ok := currentUserID(ctx); ok {
+			ctx.Set(domain.ActorContextKey, userID)
+		}
+		ctx.Next()
+	}
+}
+
+func (h *HistoryHandler) List(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.historyUsecase.List(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
@@ -0,0 +1,63 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func TestHistoryHandler_List(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			taskID: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got domain.ListHistoryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Result, 2)
+				require.Equal(t, domain.HistoryActionCreate, got.Result[0].Action)
+				require.Equal(t, domain.HistoryActionUpdate, got.Result[1].Action)
+				require.NotNil(t, got.Result[1].ActorID)
+				require.Equal(t, int64(5), *got.Result[1].ActorID)
+				require.Equal(t, "status", got.Result[1].Changes[0].Field)
+			},
+		},
+		{
+			name:   "NotFound",
+			taskID: 9,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			status := domain.StatusComplete
+			data, err := json.Marshal(domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "TaskName1"})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPut, "/task/1", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(domain.UserIDHeader, "5")
+			server.Router.ServeHTTP(httptest.NewRecorder(), request)
+
+			recorder := httptest.NewRecorder()
+			request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("/task/%d/history", tt.taskID), nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
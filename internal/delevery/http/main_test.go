@@ -1,30 +1,110 @@
-package http
+package http_test
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"oa-gogolook/internal"
 	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/eventbus"
+	"oa-gogolook/internal/notifier"
 	"oa-gogolook/internal/repository/inmemory"
+	"oa-gogolook/internal/repository/localfs"
+	"oa-gogolook/internal/stream"
 	"oa-gogolook/internal/usecase"
+	"oa-gogolook/internal/webhook"
 	"os"
 	"testing"
+	"time"
 )
 
 type TestServer struct {
-	Router *gin.Engine
-	U      domain.TaskUseCase
+	Router  *gin.Engine
+	U       domain.TaskUseCase
+	User    domain.UserUseCase
+	Comment domain.CommentUseCase
+	Webhook domain.WebhookUseCase
 }
 
+const (
+	testMaxAttachmentSize  = 1024
+	testTaskEventBuffer    = 3
+	testTaskEventHeartbeat = 20 * time.Millisecond
+	testSocketPingInterval = time.Second
+	testSocketSendBuffer   = 8
+	testGraphQLMaxDepth    = 15
+	testGraphQLComplexity  = 2000
+	testReminderAttempts   = 3
+)
+
+// newTestServer serves the use cases through the routes of the real server.
 func newTestServer(t *testing.T) TestServer {
+	config := domain.AppConfig{
+		CommentPolicy:      domain.CommentPolicyDelete,
+		MaxAttachmentSize:  testMaxAttachmentSize,
+		UndoWindow:         time.Minute,
+		ReminderAttempts:   testReminderAttempts,
+		WebhookMaxAttempts: 3,
+		WebhookBackoff:     time.Second,
+		TaskEventBuffer:    testTaskEventBuffer,
+		TaskEventHeartbeat: testTaskEventHeartbeat,
+		SocketPingInterval: testSocketPingInterval,
+		SocketSendBuffer:   testSocketSendBuffer,
+		GraphQLMaxDepth:    testGraphQLMaxDepth,
+		GraphQLComplexity:  testGraphQLComplexity,
+	}
 	r := inmemory.NewTaskRepository()
-	u := usecase.NewTaskUsecase(r)
+	userRepository := inmemory.NewUserRepository()
+	commentRepository := inmemory.NewCommentRepository()
+	historyRepository := inmemory.NewHistoryRepository()
+	events := eventbus.NewBus()
+	taskEvents := stream.NewBroker(config.TaskEventBuffer)
+	events.Subscribe("task events", taskEvents.HandleTaskEvent)
+	webhookUsecase := usecase.NewWebhookUsecase(inmemory.NewWebhookRepository(), inmemory.NewWebhookDeliveryRepository(), webhook.NewSender(&http.Client{Timeout: webhook.DefaultTimeout}), config.WebhookMaxAttempts, config.WebhookBackoff)
+	events.Subscribe("webhooks", webhookUsecase.HandleTaskEvent)
+	u := usecase.NewUndoTaskUsecase(
+		usecase.NewHistoryTaskUsecase(
+			usecase.NewTaskUsecase(r, userRepository, commentRepository, config.CommentPolicy, events),
+			historyRepository,
+		),
+		inmemory.NewUndoRepository(),
+		config.UndoWindow,
+	)
+	userUsecase := usecase.NewUserUsecase(userRepository)
+	commentUsecase := usecase.NewCommentUsecase(commentRepository, r, userRepository)
+	blobStore, err := localfs.NewBlobStore(t.TempDir())
+	require.NoError(t, err)
+	attachmentUsecase := usecase.NewAttachmentUsecase(inmemory.NewAttachmentRepository(), r, blobStore, config.MaxAttachmentSize)
+	events.Subscribe("attachments", attachmentUsecase.HandleTaskEvent)
+	reminderRepository, err := localfs.NewReminderRepository(t.TempDir())
+	require.NoError(t, err)
+	reminderUsecase := usecase.NewReminderUsecase(reminderRepository, r, notifier.NewLogNotifier(nil), config.ReminderAttempts)
+	events.Subscribe("reminders", reminderUsecase.HandleTaskEvent)
 
-	router := gin.Default()
-	NewTaskHandler(router, u)
-	server := TestServer{
-		Router: router,
-		U:      u,
+	server, err := internal.NewHttpServer(internal.Usecases{
+		Task:         u,
+		User:         userUsecase,
+		Comment:      commentUsecase,
+		Attachment:   attachmentUsecase,
+		Checklist:    usecase.NewChecklistUsecase(u),
+		History:      usecase.NewHistoryUsecase(historyRepository),
+		Undo:         u,
+		TimeTracking: usecase.NewTimeTrackingUsecase(inmemory.NewTimeEntryRepository(), r, userRepository),
+		Velocity:     usecase.NewVelocityUsecase(r),
+		Template:     usecase.NewTemplateUsecase(inmemory.NewTemplateRepository(), u),
+		Reminder:     reminderUsecase,
+		Webhook:      webhookUsecase,
+		TaskEvents:   taskEvents,
+		Sync:         usecase.NewSyncUsecase(r),
+	}, config)
+	require.NoError(t, err)
+	return TestServer{
+		Router:  server.Router,
+		U:       u,
+		User:    userUsecase,
+		Comment: commentUsecase,
+		Webhook: webhookUsecase,
 	}
-	return server
 }
 
 func TestMain(m *testing.M) {
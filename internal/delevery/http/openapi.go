@@ -0,0 +1,598 @@
+package http
+
+import (
+	"embed"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed openapi.html
+var openAPIDocsPage []byte
+
+// swaggerUI holds the Swagger UI assets the docs page loads, so it works offline.
+//
+//go:embed swagger-ui/swagger-ui.css swagger-ui/swagger-ui-bundle.js
+var swaggerUI embed.FS
+
+// swaggerUIContentTypes are the content types of the files in swaggerUI.
+var swaggerUIContentTypes = map[string]string{
+	"swagger-ui.css":       "text/css; charset=utf-8",
+	"swagger-ui-bundle.js": "application/javascript; charset=utf-8",
+}
+
+type docsAssetUriParameter struct {
+	Name string `uri:"asset" binding:"required"`
+}
+
+// openAPIObject is a piece of the OpenAPI document written by hand, it is used as is where
+// a Go type would otherwise be reflected.
+type openAPIObject map[string]interface{}
+
+// apiRoute describes one route of the HTTP API. Params are the structs bound with
+// ShouldBindUri and ShouldBindQuery, or parameters written by hand; Body and Response are
+// reflected into schemas, a nil Response meaning the JSON null the handler writes. Errors
+// are the statuses answered with an ErrorResponse besides 400 and 500.
+type apiRoute struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Params      []interface{}
+	Body        interface{}
+	Status      int
+	Response    interface{}
+	ContentType string
+	Errors      []int
+}
+
+var lastEventIDParameter = openAPIObject{
+	"name":        domain.LastEventIDHeader,
+	"in":          "header",
+	"description": "Replays the buffered events after this one.",
+	"schema":      openAPIObject{"type": "integer", "format": "int64", "minimum": 0},
+}
+
+// apiRoutes lists every route of NewHttpServer, TestOpenAPIHandler_Routes fails when they
+// drift apart.
+var apiRoutes = []apiRoute{
+	{Method: http.MethodGet, Path: "/tasks", Tag: "tasks", Summary: "List the tasks in rank order",
+		Params: []interface{}{domain.ListTaskRequest{}}, Status: http.StatusOK, Response: domain.ListTaskResponse{}},
+	{Method: http.MethodGet, Path: "/tasks/mine", Tag: "tasks", Summary: "List the tasks assigned to the caller",
+		Status: http.StatusOK, Response: domain.ListTaskResponse{}, Errors: []int{http.StatusUnauthorized}},
+	{Method: http.MethodPost, Path: "/task", Tag: "tasks", Summary: "Create a task",
+		Body: domain.CreateTaskRequest{}, Status: http.StatusCreated, Response: domain.CreateTaskResponse{}},
+	{Method: http.MethodGet, Path: "/task/:task_id", Tag: "tasks", Summary: "Get a task, as it was at asOf when it is set",
+		Params: []interface{}{domain.TaskUriParameter{}, domain.GetTaskRequest{}}, Status: http.StatusOK, Response: domain.GetTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPut, Path: "/task/:task_id", Tag: "tasks", Summary: "Update the status and description of a task",
+		Params: []interface{}{domain.UpdateTaskUriParameter{}}, Body: domain.UpdateTaskRequest{}, Status: http.StatusOK, Response: domain.UpdateTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/task/:task_id", Tag: "tasks", Summary: "Move a task to the trash",
+		Params: []interface{}{domain.DeleteTaskRequest{}}, Status: http.StatusOK, Response: domain.DeleteTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPut, Path: "/task/:task_id/assignee", Tag: "tasks", Summary: "Assign a task to a user",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.AssignTaskRequest{}, Status: http.StatusOK, Response: domain.AssignTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/task/:task_id/assignee", Tag: "tasks", Summary: "Unassign a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.AssignTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPut, Path: "/task/:task_id/estimate", Tag: "tasks", Summary: "Set the estimate of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.Estimate{}, Status: http.StatusOK, Response: domain.EstimateTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/task/:task_id/estimate", Tag: "tasks", Summary: "Clear the estimate of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.EstimateTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/task/:task_id/move", Tag: "tasks", Summary: "Move a task between two others",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.MoveTaskRequest{}, Status: http.StatusOK, Response: domain.MoveTaskResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/task/:task_id/restore", Tag: "tasks", Summary: "Restore a task from the trash",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.RestoreTaskResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/task/:task_id/archive", Tag: "tasks", Summary: "Archive a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.ArchiveTaskResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/task/:task_id/unarchive", Tag: "tasks", Summary: "Unarchive a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.ArchiveTaskResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodGet, Path: "/tasks/events", Tag: "tasks", Summary: "Stream the task events as server-sent events",
+		Params: []interface{}{lastEventIDParameter}, Status: http.StatusOK, Response: domain.TaskEvent{}, ContentType: "text/event-stream"},
+	{Method: http.MethodGet, Path: "/tasks/ws", Tag: "tasks", Summary: "Collaborate on tasks over a WebSocket of SocketRequest and SocketResponse messages",
+		Status: http.StatusSwitchingProtocols},
+	{Method: http.MethodGet, Path: "/sync", Tag: "tasks", Summary: "Get the task changes since a sync token",
+		Params: []interface{}{domain.SyncRequest{}}, Status: http.StatusOK, Response: domain.SyncResponse{},
+		Errors: []int{http.StatusGone}},
+	{Method: http.MethodPost, Path: "/graphql", Tag: "tasks", Summary: "Run a GraphQL query or mutation on tasks",
+		Body: domain.GraphQLRequest{}, Status: http.StatusOK, Response: openAPIObject{
+			"type": "object",
+			"properties": openAPIObject{
+				"data":   openAPIObject{"type": "object", "nullable": true},
+				"errors": openAPIObject{"type": "array", "items": openAPIObject{"type": "object"}},
+			},
+		}},
+
+	{Method: http.MethodGet, Path: "/users", Tag: "users", Summary: "List the users",
+		Status: http.StatusOK, Response: domain.ListUserResponse{}},
+	{Method: http.MethodPost, Path: "/user", Tag: "users", Summary: "Create a user",
+		Body: domain.CreateUserRequest{}, Status: http.StatusCreated, Response: domain.CreateUserResponse{}},
+	{Method: http.MethodGet, Path: "/user/:user_id", Tag: "users", Summary: "Get a user",
+		Params: []interface{}{domain.GetUserUriParameter{}}, Status: http.StatusOK, Response: domain.User{},
+		Errors: []int{http.StatusNotFound}},
+
+	{Method: http.MethodGet, Path: "/task/:task_id/comments", Tag: "comments", Summary: "List the comments of a task",
+		Params: []interface{}{domain.TaskUriParameter{}, domain.ListCommentRequest{}}, Status: http.StatusOK, Response: domain.ListCommentResponse{}},
+	{Method: http.MethodPost, Path: "/task/:task_id/comments", Tag: "comments", Summary: "Comment on a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.CreateCommentRequest{}, Status: http.StatusCreated, Response: domain.CreateCommentResponse{},
+		Errors: []int{http.StatusUnauthorized, http.StatusNotFound}},
+	{Method: http.MethodPut, Path: "/task/:task_id/comments/:comment_id", Tag: "comments", Summary: "Edit a comment of the caller",
+		Params: []interface{}{domain.CommentUriParameter{}}, Body: domain.UpdateCommentRequest{}, Status: http.StatusOK, Response: domain.UpdateCommentResponse{},
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/task/:task_id/comments/:comment_id", Tag: "comments", Summary: "Delete a comment of the caller",
+		Params: []interface{}{domain.CommentUriParameter{}}, Status: http.StatusOK,
+		Errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+
+	{Method: http.MethodGet, Path: "/task/:task_id/attachments", Tag: "attachments", Summary: "List the attachments of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.ListAttachmentResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/task/:task_id/attachments", Tag: "attachments", Summary: "Upload the file part as an attachment of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: openAPIObject{
+			"type":       "object",
+			"required":   []string{"file"},
+			"properties": openAPIObject{"file": openAPIObject{"type": "string", "format": "binary"}},
+		}, Status: http.StatusCreated, Response: domain.UploadAttachmentResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusRequestEntityTooLarge}},
+	{Method: http.MethodGet, Path: "/task/:task_id/attachments/:attachment_id", Tag: "attachments", Summary: "Download an attachment, Range requests are supported",
+		Params: []interface{}{domain.AttachmentUriParameter{}}, Status: http.StatusOK, Response: openAPIObject{"type": "string", "format": "binary"},
+		ContentType: "application/octet-stream", Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/task/:task_id/attachments/:attachment_id", Tag: "attachments", Summary: "Delete an attachment",
+		Params: []interface{}{domain.AttachmentUriParameter{}}, Status: http.StatusOK,
+		Errors: []int{http.StatusNotFound}},
+
+	{Method: http.MethodPost, Path: "/task/:task_id/checklist", Tag: "checklist", Summary: "Add a checklist item to a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.AddChecklistItemRequest{}, Status: http.StatusCreated, Response: domain.ChecklistResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPut, Path: "/task/:task_id/checklist/order", Tag: "checklist", Summary: "Reorder the checklist of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.ReorderChecklistRequest{}, Status: http.StatusOK, Response: domain.ChecklistResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/task/:task_id/checklist/:item_id/toggle", Tag: "checklist", Summary: "Check or uncheck a checklist item",
+		Params: []interface{}{domain.ChecklistItemUriParameter{}}, Status: http.StatusOK, Response: domain.ChecklistResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/task/:task_id/checklist/:item_id", Tag: "checklist", Summary: "Remove a checklist item",
+		Params: []interface{}{domain.ChecklistItemUriParameter{}}, Status: http.StatusOK, Response: domain.ChecklistResponse{},
+		Errors: []int{http.StatusNotFound}},
+
+	{Method: http.MethodGet, Path: "/task/:task_id/history", Tag: "history", Summary: "List the changes of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.ListHistoryResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/undo/:token", Tag: "history", Summary: "Undo an update or delete",
+		Params: []interface{}{domain.UndoUriParameter{}}, Status: http.StatusOK, Response: domain.UndoResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusGone}},
+
+	{Method: http.MethodPost, Path: "/task/:task_id/timer/start", Tag: "time tracking", Summary: "Start the timer of the caller on a task",
+		Params: []interface{}{domain.TimeEntryUriParameter{}}, Status: http.StatusCreated, Response: domain.TimeEntryResponse{},
+		Errors: []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/task/:task_id/timer/stop", Tag: "time tracking", Summary: "Stop the timer of the caller on a task",
+		Params: []interface{}{domain.TimeEntryUriParameter{}}, Status: http.StatusOK, Response: domain.TimeEntryResponse{},
+		Errors: []int{http.StatusUnauthorized, http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/task/:task_id/time-entries", Tag: "time tracking", Summary: "List the time entries of a task",
+		Params: []interface{}{domain.TimeEntryUriParameter{}}, Status: http.StatusOK, Response: domain.ListTimeEntryResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/task/:task_id/time-entries", Tag: "time tracking", Summary: "Log time of the caller on a task",
+		Params: []interface{}{domain.TimeEntryUriParameter{}}, Body: domain.AddTimeEntryRequest{}, Status: http.StatusCreated, Response: domain.TimeEntryResponse{},
+		Errors: []int{http.StatusUnauthorized, http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/time-report", Tag: "time tracking", Summary: "Sum the logged time per task and user",
+		Params: []interface{}{domain.TimeReportRequest{}}, Status: http.StatusOK, Response: domain.TimeReportResponse{}},
+	{Method: http.MethodGet, Path: "/velocity-report", Tag: "time tracking", Summary: "Report the completed estimates per week",
+		Params: []interface{}{domain.VelocityRequest{}}, Status: http.StatusOK, Response: domain.VelocityResponse{}},
+
+	{Method: http.MethodGet, Path: "/templates", Tag: "templates", Summary: "List the task templates",
+		Status: http.StatusOK, Response: domain.ListTemplateResponse{}},
+	{Method: http.MethodPost, Path: "/templates", Tag: "templates", Summary: "Create a task template",
+		Body: domain.CreateTemplateRequest{}, Status: http.StatusCreated, Response: domain.TemplateResponse{}},
+	{Method: http.MethodGet, Path: "/templates/:template_id", Tag: "templates", Summary: "Get a task template",
+		Params: []interface{}{domain.TemplateUriParameter{}}, Status: http.StatusOK, Response: domain.TemplateResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/templates/:template_id", Tag: "templates", Summary: "Delete a task template",
+		Params: []interface{}{domain.TemplateUriParameter{}}, Status: http.StatusOK,
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/templates/:template_id/instantiate", Tag: "templates", Summary: "Create a task from a template",
+		Params: []interface{}{domain.TemplateUriParameter{}}, Body: domain.InstantiateTemplateRequest{}, Status: http.StatusCreated, Response: domain.InstantiateTemplateResponse{},
+		Errors: []int{http.StatusNotFound}},
+
+	{Method: http.MethodGet, Path: "/task/:task_id/reminders", Tag: "reminders", Summary: "List the reminders of a task",
+		Params: []interface{}{domain.TaskUriParameter{}}, Status: http.StatusOK, Response: domain.ListReminderResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/task/:task_id/reminders", Tag: "reminders", Summary: "Remind of a task before it is due",
+		Params: []interface{}{domain.TaskUriParameter{}}, Body: domain.CreateReminderRequest{}, Status: http.StatusCreated, Response: domain.ReminderResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodDelete, Path: "/task/:task_id/reminders/:reminder_id", Tag: "reminders", Summary: "Delete a reminder",
+		Params: []interface{}{domain.ReminderUriParameter{}}, Status: http.StatusOK,
+		Errors: []int{http.StatusNotFound}},
+
+	{Method: http.MethodGet, Path: "/webhooks", Tag: "webhooks", Summary: "List the webhook subscriptions",
+		Status: http.StatusOK, Response: domain.ListWebhookResponse{}},
+	{Method: http.MethodPost, Path: "/webhooks", Tag: "webhooks", Summary: "Subscribe a URL to task events",
+		Body: domain.CreateWebhookRequest{}, Status: http.StatusCreated, Response: domain.WebhookResponse{}},
+	{Method: http.MethodGet, Path: "/webhooks/:webhook_id", Tag: "webhooks", Summary: "Get a webhook subscription",
+		Params: []interface{}{domain.WebhookUriParameter{}}, Status: http.StatusOK, Response: domain.WebhookResponse{},
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/webhooks/:webhook_id", Tag: "webhooks", Summary: "Delete a webhook subscription",
+		Params: []interface{}{domain.WebhookUriParameter{}}, Status: http.StatusOK,
+		Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/webhook-dead-letters", Tag: "webhooks", Summary: "List the deliveries that ran out of attempts",
+		Status: http.StatusOK, Response: domain.ListWebhookDeliveryResponse{}},
+	{Method: http.MethodPost, Path: "/webhook-dead-letters/:delivery_id/retry", Tag: "webhooks", Summary: "Queue a dead delivery again",
+		Params: []interface{}{domain.WebhookDeliveryUriParameter{}}, Status: http.StatusOK, Response: domain.WebhookDeliveryResponse{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict}},
+
+	{Method: http.MethodGet, Path: "/openapi.json", Tag: "docs", Summary: "This document",
+		Status: http.StatusOK, Response: openAPIObject{"type": "object"}},
+	{Method: http.MethodGet, Path: "/docs", Tag: "docs", Summary: "Browse this document",
+		Status: http.StatusOK, Response: openAPIObject{"type": "string"}, ContentType: "text/html"},
+	{Method: http.MethodGet, Path: "/docs/:asset", Tag: "docs", Summary: "A Swagger UI file of the docs page",
+		Params: []interface{}{docsAssetUriParameter{}}, Status: http.StatusOK, Response: openAPIObject{"type": "string"},
+		ContentType: "*/*", Errors: []int{http.StatusNotFound}},
+}
+
+type OpenAPIHandler struct {
+	document []byte
+}
+
+// NewOpenAPIHandler serves the OpenAPI 3 document of apiRoutes at /openapi.json and a
+// page browsing it at /docs.
+func NewOpenAPIHandler(e *gin.Engine) error {
+	document, err := json.Marshal(newOpenAPIDocument(apiRoutes))
+	if err != nil {
+		return err
+	}
+	h := &OpenAPIHandler{document: document}
+	e.GET("/openapi.json", h.Document)
+	e.GET("/docs", h.Docs)
+	e.GET("/docs/:asset", h.Asset)
+	return nil
+}
+
+func (h *OpenAPIHandler) Document(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", h.document)
+}
+
+func (h *OpenAPIHandler) Docs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", openAPIDocsPage)
+}
+
+func (h *OpenAPIHandler) Asset(ctx *gin.Context) {
+	var uri docsAssetUriParameter
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	contentType, ok := swaggerUIContentTypes[uri.Name]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, domain.ErrDataNotFound)
+		return
+	}
+	data, err := swaggerUI.ReadFile("swagger-ui/" + uri.Name)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, domain.ErrSystemError)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// openAPIPath turns the gin parameters of path into OpenAPI ones, /task/:task_id becomes
+// /task/{task_id}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func newOpenAPIDocument(routes []apiRoute) openAPIObject {
+	s := &openAPISchemas{schemas: openAPIObject{}}
+	errorRef := s.ref(reflect.TypeOf(domain.ErrSystemError).Elem())
+	paths := openAPIObject{}
+	for _, route := range routes {
+		path := openAPIPath(route.Path)
+		item, ok := paths[path].(openAPIObject)
+		if !ok {
+			item = openAPIObject{}
+			paths[path] = item
+		}
+
+		operation := openAPIObject{
+			"tags":        []string{route.Tag},
+			"summary":     route.Summary,
+			"operationId": strings.ToLower(route.Method) + strings.NewReplacer("/", "_", ":", "", "-", "_", ".", "_").Replace(route.Path),
+		}
+		var parameters []openAPIObject
+		for _, params := range route.Params {
+			if p, ok := params.(openAPIObject); ok {
+				parameters = append(parameters, p)
+				continue
+			}
+			parameters = append(parameters, s.parameters(reflect.TypeOf(params))...)
+		}
+		if parameters != nil {
+			operation["parameters"] = parameters
+		}
+		if route.Body != nil {
+			contentType := "application/json"
+			if _, ok := route.Body.(openAPIObject); ok {
+				contentType = "multipart/form-data"
+			}
+			operation["requestBody"] = openAPIObject{
+				"required": true,
+				"content":  openAPIObject{contentType: openAPIObject{"schema": s.schemaOf(route.Body)}},
+			}
+		}
+
+		response := openAPIObject{"description": http.StatusText(route.Status)}
+		if route.Response != nil {
+			contentType := route.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			response["content"] = openAPIObject{contentType: openAPIObject{"schema": s.schemaOf(route.Response)}}
+		}
+		responses := openAPIObject{strconv.Itoa(route.Status): response}
+		errors := route.Errors
+		if route.Params != nil || route.Body != nil {
+			errors = append([]int{http.StatusBadRequest}, errors...)
+		}
+		for _, status := range append(errors, http.StatusInternalServerError) {
+			responses[strconv.Itoa(status)] = openAPIObject{
+				"description": http.StatusText(status),
+				"content":     openAPIObject{"application/json": openAPIObject{"schema": errorRef}},
+			}
+		}
+		operation["responses"] = responses
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return openAPIObject{
+		"openapi": "3.0.3",
+		"info": openAPIObject{
+			"title":   "oa-gogolook task API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": openAPIObject{
+			"schemas": s.schemas,
+			"securitySchemes": openAPIObject{
+				"userId": openAPIObject{"type": "apiKey", "in": "header", "name": domain.UserIDHeader},
+			},
+		},
+		// The user ID is optional, only the routes acting for the caller answer 401 without it.
+		"security": []openAPIObject{{}, {"userId": []string{}}},
+	}
+}
+
+// openAPISchemas reflects Go types into the component schemas, fields are named after
+// their json tag and constrained by their binding tag.
+type openAPISchemas struct {
+	schemas openAPIObject
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// openAPIExtraProperties are the properties a type adds in its MarshalJSON.
+var openAPIExtraProperties = map[reflect.Type]openAPIObject{
+	reflect.TypeOf(domain.Task{}): {
+		"progress": openAPIObject{"type": "integer", "minimum": 0, "maximum": 100, "description": "Percentage of checked checklist items, missing without a checklist."},
+	},
+}
+
+func (s *openAPISchemas) schemaOf(v interface{}) openAPIObject {
+	if o, ok := v.(openAPIObject); ok {
+		return o
+	}
+	return s.schema(reflect.TypeOf(v))
+}
+
+// ref adds the struct t to the components and refers to it.
+func (s *openAPISchemas) ref(t reflect.Type) openAPIObject {
+	name := t.Name()
+	if name == "errorResponse" {
+		name = "ErrorResponse"
+	}
+	rtn := openAPIObject{"$ref": "#/components/schemas/" + name}
+	if _, ok := s.schemas[name]; ok {
+		return rtn
+	}
+	// Set before the fields are reflected so a recursive type ends up in a $ref.
+	s.schemas[name] = openAPIObject{}
+	schema := openAPIObject{"type": "object"}
+	properties := openAPIObject{}
+	var required []string
+	s.properties(t, properties, &required)
+	for name, property := range openAPIExtraProperties[t] {
+		properties[name] = property
+	}
+	schema["properties"] = properties
+	if required != nil {
+		schema["required"] = required
+	}
+	s.schemas[name] = schema
+	return rtn
+}
+
+func (s *openAPISchemas) properties(t reflect.Type, properties openAPIObject, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			s.properties(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property := s.schema(field.Type)
+		if constrained := constrain(property, field.Type, field.Tag.Get("binding")); constrained != nil {
+			property = constrained
+		}
+		properties[name] = property
+		if hasRule(field.Tag.Get("binding"), "required") && !strings.Contains(tag, ",omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// parameters reflects the uri and form fields of a struct bound by a handler.
+func (s *openAPISchemas) parameters(t reflect.Type) []openAPIObject {
+	var rtn []openAPIObject
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		binding := field.Tag.Get("binding")
+		parameter := openAPIObject{}
+		if name := field.Tag.Get("uri"); name != "" {
+			parameter["name"] = name
+			parameter["in"] = "path"
+			parameter["required"] = true
+		} else if name := field.Tag.Get("form"); name != "" {
+			parameter["name"] = name
+			parameter["in"] = "query"
+			if hasRule(binding, "required") {
+				parameter["required"] = true
+			}
+		} else {
+			continue
+		}
+		schema := s.schema(field.Type)
+		if field.Type == timeType && field.Tag.Get("time_format") == "2006-01-02" {
+			schema = openAPIObject{"type": "string", "format": "date"}
+		}
+		if constrained := constrain(schema, field.Type, binding); constrained != nil {
+			schema = constrained
+		}
+		parameter["schema"] = schema
+		rtn = append(rtn, parameter)
+	}
+	return rtn
+}
+
+func (s *openAPISchemas) schema(t reflect.Type) openAPIObject {
+	if t == timeType {
+		return openAPIObject{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := s.schema(t.Elem())
+		if _, ok := schema["$ref"]; ok {
+			return openAPIObject{"allOf": []openAPIObject{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.Struct:
+		return s.ref(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return openAPIObject{"type": "string", "format": "byte"}
+		}
+		return openAPIObject{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return openAPIObject{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Interface:
+		if t == reflect.TypeOf((*error)(nil)).Elem() {
+			return s.ref(reflect.TypeOf(domain.ErrSystemError).Elem())
+		}
+		return openAPIObject{}
+	case reflect.Bool:
+		return openAPIObject{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return openAPIObject{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		if t.PkgPath() == "time" {
+			return openAPIObject{"type": "integer", "format": "int64", "description": "Nanoseconds."}
+		}
+		return openAPIObject{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return openAPIObject{"type": "number"}
+	default:
+		return openAPIObject{"type": "string"}
+	}
+}
+
+// constrain returns a copy of schema narrowed by the min, max and oneof rules of binding,
+// or nil when there are none. Rules after dive apply to the items.
+func constrain(schema openAPIObject, t reflect.Type, binding string) openAPIObject {
+	rules := strings.Split(binding, ",")
+	var items []string
+	for i, rule := range rules {
+		if rule == "dive" {
+			rules, items = rules[:i], rules[i+1:]
+			break
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var rtn openAPIObject
+	set := func(key string, value interface{}) {
+		if rtn == nil {
+			rtn = openAPIObject{}
+			for k, v := range schema {
+				rtn[k] = v
+			}
+		}
+		rtn[key] = value
+	}
+	for _, rule := range rules {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "min", "max":
+			n, err := strconv.Atoi(kv[1])
+			if err != nil {
+				continue
+			}
+			switch t.Kind() {
+			case reflect.String:
+				set(kv[0]+"Length", n)
+			case reflect.Slice, reflect.Array, reflect.Map:
+				set(kv[0]+"Items", n)
+			default:
+				set(map[string]string{"min": "minimum", "max": "maximum"}[kv[0]], n)
+			}
+		case "oneof":
+			set("enum", strings.Fields(kv[1]))
+		}
+	}
+	if items != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		if itemSchema, ok := schema["items"].(openAPIObject); ok {
+			if constrained := constrain(itemSchema, t.Elem(), strings.Join(items, ",")); constrained != nil {
+				set("items", constrained)
+			}
+		}
+	}
+	return rtn
+}
+
+// hasRule tells whether binding has rule for the field itself, not its items.
+func hasRule(binding string, rule string) bool {
+	for _, r := range strings.Split(binding, ",") {
+		if r == "dive" {
+			return false
+		}
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+type openAPIDocument struct {
+	Paths map[string]map[string]struct {
+		Responses map[string]json.RawMessage `json:"responses"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func getOpenAPIDocument(t *testing.T, server TestServer) (openAPIDocument, string) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var got openAPIDocument
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	return got, recorder.Body.String()
+}
+
+func TestOpenAPIHandler_Document(t *testing.T) {
+	server := newTestServer(t)
+	document, body := getOpenAPIDocument(t, server)
+
+	for _, ref := range regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(body, -1) {
+		require.Contains(t, document.Components.Schemas, ref[1])
+	}
+	require.Equal(t, []string{"name"}, document.Components.Schemas["CreateTaskRequest"].Required)
+	require.ElementsMatch(t, []string{"id", "status", "name"}, document.Components.Schemas["UpdateTaskRequest"].Required)
+	require.JSONEq(t, `{"$ref":"#/components/schemas/Task"}`, string(document.Components.Schemas["UpdateTaskResponse"].Properties["result"]))
+	require.JSONEq(t, `{"type":"array","maxItems":20,"items":{"type":"string","maxLength":50}}`, string(document.Components.Schemas["CreateTaskRequest"].Properties["tags"]))
+	require.Contains(t, document.Components.Schemas["Task"].Properties, "progress")
+	require.Contains(t, document.Components.Schemas["ErrorResponse"].Properties, "errorCode")
+	require.JSONEq(t, `{
+		"description": "Not Found",
+		"content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+	}`, string(document.Paths["/task/{task_id}"]["get"].Responses["404"]))
+}
+
+func TestOpenAPIHandler_Docs(t *testing.T) {
+	server := newTestServer(t)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/docs", nil)
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, recorder.Body.String(), `url: "/openapi.json"`)
+	require.NotContains(t, recorder.Body.String(), "https://")
+
+	for _, tt := range []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{path: "/docs/swagger-ui.css", status: http.StatusOK, contentType: "text/css"},
+		{path: "/docs/swagger-ui-bundle.js", status: http.StatusOK, contentType: "application/javascript"},
+		{path: "/docs/LICENSE", status: http.StatusNotFound, contentType: "application/json"},
+	} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, tt.path, nil)
+		require.NoError(t, err)
+		server.Router.ServeHTTP(recorder, request)
+		require.Equal(t, tt.status, recorder.Code, tt.path)
+		require.Contains(t, recorder.Header().Get("Content-Type"), tt.contentType, tt.path)
+	}
+}
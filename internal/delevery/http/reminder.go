@@ -0,0 +1,83 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type ReminderHandler struct {
+	reminderUsecase domain.ReminderUseCase
+}
+
+func NewReminderHandler(e *gin.Engine, reminderUsecase domain.ReminderUseCase) {
+	h := &ReminderHandler{
+		reminderUsecase: reminderUsecase,
+	}
+	e.GET("/task/:task_id/reminders", h.List)
+	e.POST("/task/:task_id/reminders", h.Create)
+	e.DELETE("/task/:task_id/reminders/:reminder_id", h.Delete)
+}
+
+func (h *ReminderHandler) List(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.reminderUsecase.List(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *ReminderHandler) Create(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.CreateReminderRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.reminderUsecase.Create(ctx, para.ID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *ReminderHandler) Delete(ctx *gin.Context) {
+	var para domain.ReminderUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	err := h.reminderUsecase.Delete(ctx, para.TaskID, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (h *ReminderHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	case domain.ErrInvalidPayload:
+		ctx.JSON(http.StatusBadRequest, err)
+	case domain.ErrTaskNoDueDate:
+		ctx.JSON(http.StatusConflict, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,126 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestReminderHandler_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		body          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "At",
+			taskID: 1,
+			body:   `{"at":"2022-11-01T10:00:00Z"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:   "BeforeDue",
+			taskID: 2,
+			body:   `{"beforeDueMinutes":30}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:   "NoDueDate",
+			taskID: 1,
+			body:   `{"beforeDueMinutes":30}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "Both",
+			taskID: 2,
+			body:   `{"at":"2022-11-01T10:00:00Z","beforeDueMinutes":30}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NegativeOffset",
+			taskID: 2,
+			body:   `{"beforeDueMinutes":-5}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "TaskNotFound",
+			taskID: 5,
+			body:   `{"at":"2022-11-01T10:00:00Z"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			due := time.Date(2022, 11, 2, 10, 0, 0, 0, time.UTC)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2", DueAt: &due})
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/task/%d/reminders", tt.taskID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(tt.body)))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReminderHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  "/task/1/reminders/1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OtherTask",
+			url:  "/task/2/reminders/1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/task/1/reminders", bytes.NewReader([]byte(`{"at":"2022-11-01T10:00:00Z"}`)))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusCreated, recorder.Code)
+
+			recorder = httptest.NewRecorder()
+			request, err = http.NewRequest(http.MethodDelete, tt.url, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+	"log"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxSocketMessageSize caps a client message, a larger one closes the connection.
+	maxSocketMessageSize = 64 << 10
+	// socketWriteWait is how long a single write may block.
+	socketWriteWait = 10 * time.Second
+)
+
+type TaskSocketHandler struct {
+	taskUsecse   domain.TaskUseCase
+	broker       domain.TaskEventBroker
+	upgrader     websocket.Upgrader
+	pingInterval time.Duration
+	sendBuffer   int
+	connections  int64
+}
+
+// NewTaskSocketHandler serves the task collaboration WebSocket. The server pings every
+// pingInterval and drops a connection that does not answer within two intervals, a client
+// that lets more than sendBuffer messages pile up is disconnected as too slow.
+func NewTaskSocketHandler(e *gin.Engine, taskUsecse domain.TaskUseCase, broker domain.TaskEventBroker, pingInterval time.Duration, sendBuffer int) {
+	h := &TaskSocketHandler{
+		taskUsecse:   taskUsecse,
+		broker:       broker,
+		pingInterval: pingInterval,
+		sendBuffer:   sendBuffer,
+	}
+	e.GET("/tasks/ws", h.Connect)
+}
+
+func (h *TaskSocketHandler) Connect(ctx *gin.Context) {
+	ws, err := h.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// The upgrader already answered the request.
+		return
+	}
+	id := fmt.Sprintf("socket-%d", atomic.AddInt64(&h.connections, 1))
+	base := domain.ContextWithOrigin(ctx.Request.Context(), id)
+	if userID, ok := currentUserID(ctx); ok {
+		base = domain.ContextWithActor(base, userID)
+	}
+	sub, cancel := h.broker.Subscribe("", nil)
+	c := &socketConn{
+		ws:           ws,
+		ctx:          base,
+		id:           id,
+		taskUsecse:   h.taskUsecse,
+		pingInterval: h.pingInterval,
+		events:       sub.Events,
+		send:         make(chan domain.SocketResponse, h.sendBuffer),
+		done:         make(chan struct{}),
+		tasks:        make(map[int64]bool),
+		closeCode:    websocket.CloseNormalClosure,
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.writeLoop()
+	}()
+	go func() {
+		defer wg.Done()
+		c.forward()
+	}()
+	c.readLoop()
+	c.close(websocket.CloseNormalClosure, "")
+	cancel()
+	wg.Wait()
+}
+
+// socketConn is one client connection. Only writeLoop writes to the socket, readLoop
+// handles the client messages one at a time and forward passes the events of the other
+// clients on.
+type socketConn struct {
+	ws           *websocket.Conn
+	ctx          context.Context
+	id           string
+	taskUsecse   domain.TaskUseCase
+	pingInterval time.Duration
+	events       <-chan domain.TaskEvent
+	send         chan domain.SocketResponse
+	done         chan struct{}
+	closeOnce    sync.Once
+	closeCode    int
+	closeText    string
+
+	mu    sync.Mutex
+	all   bool
+	tasks map[int64]bool
+}
+
+// close makes writeLoop send the close message and end the connection, only the first
+// call counts.
+func (c *socketConn) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+	})
+}
+
+func (c *socketConn) readLoop() {
+	c.ws.SetReadLimit(maxSocketMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(2 * c.pingInterval))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(2 * c.pingInterval))
+	})
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("%s: %v", c.id, err)
+			}
+			return
+		}
+		_ = c.ws.SetReadDeadline(time.Now().Add(2 * c.pingInterval))
+
+		var req domain.SocketRequest
+		var resp domain.SocketResponse
+		if err := json.Unmarshal(data, &req); err != nil {
+			resp = domain.SocketResponse{Type: domain.SocketError, Error: domain.ErrInvalidPayload}
+		} else {
+			resp = c.handle(req)
+		}
+		// Waiting here stops reading, so a client that does not read its acks is slowed down
+		// instead of piling up work.
+		select {
+		case c.send <- resp:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *socketConn) writeLoop() {
+	ticker := time.NewTicker(c.pingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = c.ws.Close()
+	}()
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			msg := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+			_ = c.ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(socketWriteWait))
+			return
+		}
+	}
+}
+
+// forward queues the subscribed events of the other clients. A client whose queue is full
+// is disconnected rather than slowing down the others, it reloads the tasks when it comes
+// back.
+func (c *socketConn) forward() {
+	for {
+		select {
+		case event, ok := <-c.events:
+			if !ok {
+				c.close(websocket.CloseTryAgainLater, "too slow")
+				return
+			}
+			if event.Origin == c.id || !c.wants(event.Task.ID) {
+				continue
+			}
+			select {
+			case c.send <- domain.SocketResponse{Type: domain.SocketEvent, Event: &event}:
+			default:
+				c.close(websocket.CloseTryAgainLater, "too slow")
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *socketConn) wants(taskID int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.all || c.tasks[taskID]
+}
+
+// subscribe adds the tasks to the subscription, no tasks meaning all of them. unsubscribe
+// removes the tasks, no tasks meaning all of them; single tasks can not be left out of an
+// all-tasks subscription.
+func (c *socketConn) subscribe(taskIDs []int64, on bool) domain.SocketSubscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case len(taskIDs) == 0 && on:
+		c.all = true
+	case len(taskIDs) == 0:
+		c.all = false
+		c.tasks = make(map[int64]bool)
+	default:
+		for _, id := range taskIDs {
+			if on {
+				c.tasks[id] = true
+			} else {
+				delete(c.tasks, id)
+			}
+		}
+	}
+	rtn := domain.SocketSubscription{All: c.all, TaskIDs: make([]int64, 0, len(c.tasks))}
+	for id := range c.tasks {
+		rtn.TaskIDs = append(rtn.TaskIDs, id)
+	}
+	sort.Slice(rtn.TaskIDs, func(i, j int) bool { return rtn.TaskIDs[i] < rtn.TaskIDs[j] })
+	return rtn
+}
+
+func (c *socketConn) handle(req domain.SocketRequest) domain.SocketResponse {
+	result, err := c.dispatch(req)
+	if err != nil {
+		return domain.SocketResponse{ID: req.ID, Type: domain.SocketError, Error: socketError(err)}
+	}
+	return domain.SocketResponse{ID: req.ID, Type: domain.SocketAck, Result: result}
+}
+
+// socketError passes domain errors to the client, anything else is logged and reported as
+// a system error.
+func socketError(err error) domain.ErrorResponse {
+	if e, ok := err.(domain.ErrorResponse); ok {
+		return e
+	}
+	log.Printf("socket: %v", err)
+	return domain.ErrSystemError
+}
+
+func (c *socketConn) dispatch(req domain.SocketRequest) (interface{}, error) {
+	switch req.Type {
+	case domain.SocketSubscribe:
+		return c.subscribe(req.TaskIDs, true), nil
+	case domain.SocketUnsubscribe:
+		return c.subscribe(req.TaskIDs, false), nil
+	case domain.SocketPing:
+		return nil, nil
+	case domain.SocketCreate:
+		var payload domain.CreateTaskRequest
+		if err := bindSocketPayload(req.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return c.taskUsecse.Create(c.ctx, payload)
+	case domain.SocketUpdate:
+		var payload domain.UpdateTaskRequest
+		if err := bindSocketPayload(req.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return c.taskUsecse.Update(c.ctx, payload)
+	}
+
+	if req.TaskID < 1 {
+		return nil, domain.ErrInvalidParameters
+	}
+	switch req.Type {
+	case domain.SocketDelete:
+		return c.taskUsecse.Delete(c.ctx, req.TaskID)
+	case domain.SocketAssign:
+		var payload domain.AssignTaskRequest
+		if err := bindSocketPayload(req.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return c.taskUsecse.Assign(c.ctx, req.TaskID, payload.AssigneeID)
+	case domain.SocketUnassign:
+		return c.taskUsecse.Unassign(c.ctx, req.TaskID)
+	case domain.SocketMove:
+		var payload domain.MoveTaskRequest
+		if err := bindSocketPayload(req.Payload, &payload); err != nil {
+			return nil, err
+		}
+		return c.taskUsecse.Move(c.ctx, req.TaskID, payload)
+	default:
+		return nil, domain.ErrInvalidParameters
+	}
+}
+
+// bindSocketPayload decodes and validates a mutation payload the way ShouldBindJSON does.
+func bindSocketPayload(payload json.RawMessage, obj interface{}) error {
+	if len(payload) == 0 {
+		return domain.ErrInvalidPayload
+	}
+	if err := json.Unmarshal(payload, obj); err != nil {
+		return domain.ErrInvalidPayload
+	}
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return domain.ErrInvalidPayload
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func Test_socketConn_forward(t *testing.T) {
+	events := make(chan domain.TaskEvent, 4)
+	c := &socketConn{
+		id:     "socket-1",
+		events: events,
+		send:   make(chan domain.SocketResponse, 1),
+		done:   make(chan struct{}),
+		tasks:  map[int64]bool{1: true},
+	}
+	go c.forward()
+
+	events <- domain.TaskEvent{ID: 1, Task: domain.Task{ID: 1}, Origin: "socket-1"}
+	events <- domain.TaskEvent{ID: 2, Task: domain.Task{ID: 2}, Origin: "socket-2"}
+	events <- domain.TaskEvent{ID: 3, Task: domain.Task{ID: 1}, Origin: "socket-2"}
+	events <- domain.TaskEvent{ID: 4, Task: domain.Task{ID: 1}}
+	select {
+	case <-c.done:
+	case <-time.After(time.Second):
+		t.Fatal("forward() did not drop the client with a full queue")
+	}
+	require.Equal(t, websocket.CloseTryAgainLater, c.closeCode)
+	got := <-c.send
+	require.Equal(t, int64(3), got.Event.ID)
+}
@@ -0,0 +1,221 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	httpdelivery "oa-gogolook/internal/delevery/http"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"oa-gogolook/internal/stream"
+	"oa-gogolook/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+)
+
+// socketMessage is a server message with the result and error left undecoded.
+type socketMessage struct {
+	ID     string                   `json:"id"`
+	Type   domain.SocketMessageType `json:"type"`
+	Result json.RawMessage          `json:"result"`
+	Error  *struct {
+		ErrorCode string `json:"errorCode"`
+	} `json:"error"`
+	Event *domain.TaskEvent `json:"event"`
+}
+
+func dialSocket(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/tasks/ws", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func sendSocket(t *testing.T, conn *websocket.Conn, msg string) {
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func readSocket(t *testing.T, conn *websocket.Conn) socketMessage {
+	var rtn socketMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(&rtn))
+	return rtn
+}
+
+// requireNoSocketEvent pings the server, the pong being the next message proves nothing
+// was queued before it.
+func requireNoSocketEvent(t *testing.T, conn *websocket.Conn) {
+	sendSocket(t, conn, `{"id":"ping","type":"ping"}`)
+	got := readSocket(t, conn)
+	require.Equal(t, socketMessage{ID: "ping", Type: domain.SocketAck}, socketMessage{ID: got.ID, Type: got.Type})
+}
+
+func TestTaskSocketHandler_Collaboration(t *testing.T) {
+	server := newTestServer(t)
+	ts := httptest.NewServer(server.Router)
+	t.Cleanup(ts.Close)
+	alice := dialSocket(t, ts.URL)
+	bob := dialSocket(t, ts.URL)
+
+	sendSocket(t, alice, `{"id":"a1","type":"subscribe"}`)
+	got := readSocket(t, alice)
+	require.Equal(t, domain.SocketAck, got.Type)
+	require.JSONEq(t, `{"all":true,"taskIds":[]}`, string(got.Result))
+	sendSocket(t, bob, `{"id":"b1","type":"subscribe","taskIds":[1]}`)
+	got = readSocket(t, bob)
+	require.JSONEq(t, `{"all":false,"taskIds":[1]}`, string(got.Result))
+
+	// Alice gets the ack of her create, Bob the event.
+	sendSocket(t, alice, `{"id":"a2","type":"create","payload":{"name":"TaskName1"}}`)
+	got = readSocket(t, alice)
+	require.Equal(t, "a2", got.ID)
+	require.Equal(t, domain.SocketAck, got.Type)
+	var created domain.CreateTaskResponse
+	require.NoError(t, json.Unmarshal(got.Result, &created))
+	require.Equal(t, int64(1), created.Result.ID)
+	got = readSocket(t, bob)
+	require.Equal(t, domain.SocketEvent, got.Type)
+	require.Equal(t, domain.TaskEventCreated, got.Event.Type)
+	require.Equal(t, "TaskName1", got.Event.Task.Name)
+	requireNoSocketEvent(t, alice)
+
+	sendSocket(t, bob, `{"id":"b2","type":"update","payload":{"id":1,"name":"TaskName1","status":1}}`)
+	require.Equal(t, domain.SocketAck, readSocket(t, bob).Type)
+	got = readSocket(t, alice)
+	require.Equal(t, domain.TaskEventUpdated, got.Event.Type)
+	require.Equal(t, domain.StatusComplete, got.Event.Task.Status)
+
+	// Bob did not subscribe to the second task.
+	sendSocket(t, alice, `{"id":"a3","type":"create","payload":{"name":"TaskName2"}}`)
+	require.Equal(t, domain.SocketAck, readSocket(t, alice).Type)
+	requireNoSocketEvent(t, bob)
+
+	// Changes made over HTTP reach every subscriber.
+	_, err := server.U.Delete(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskEventDeleted, readSocket(t, alice).Event.Type)
+	require.Equal(t, domain.TaskEventDeleted, readSocket(t, bob).Event.Type)
+
+	sendSocket(t, bob, `{"id":"b3","type":"unsubscribe"}`)
+	got = readSocket(t, bob)
+	require.JSONEq(t, `{"all":false,"taskIds":[]}`, string(got.Result))
+}
+
+func TestTaskSocketHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		wantCode string
+	}{
+		{
+			name:     "NotJSON",
+			msg:      `subscribe`,
+			wantCode: domain.ErrInvalidPayload.ErrorCode,
+		},
+		{
+			name:     "UnknownType",
+			msg:      `{"id":"1","type":"rename","taskId":1}`,
+			wantCode: domain.ErrInvalidParameters.ErrorCode,
+		},
+		{
+			name:     "NoPayload",
+			msg:      `{"id":"1","type":"create"}`,
+			wantCode: domain.ErrInvalidPayload.ErrorCode,
+		},
+		{
+			name:     "InvalidPayload",
+			msg:      `{"id":"1","type":"update","payload":{"id":1,"name":"TaskName1","status":5}}`,
+			wantCode: domain.ErrInvalidPayload.ErrorCode,
+		},
+		{
+			name:     "NoTaskID",
+			msg:      `{"id":"1","type":"delete"}`,
+			wantCode: domain.ErrInvalidParameters.ErrorCode,
+		},
+		{
+			name:     "NotFound",
+			msg:      `{"id":"1","type":"delete","taskId":5}`,
+			wantCode: domain.ErrDataNotFound.ErrorCode,
+		},
+		{
+			name:     "NameNotMatch",
+			msg:      `{"id":"1","type":"update","payload":{"id":1,"name":"other","status":1}}`,
+			wantCode: domain.ErrTaskNameNotMatch.ErrorCode,
+		},
+	}
+	server := newTestServer(t)
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	ts := httptest.NewServer(server.Router)
+	t.Cleanup(ts.Close)
+	conn := dialSocket(t, ts.URL)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendSocket(t, conn, tt.msg)
+			got := readSocket(t, conn)
+			require.Equal(t, domain.SocketError, got.Type)
+			require.NotNil(t, got.Error)
+			require.Equal(t, tt.wantCode, got.Error.ErrorCode)
+		})
+	}
+}
+
+// brokenTaskUsecase fails every delete with an error the domain does not know.
+type brokenTaskUsecase struct {
+	domain.TaskUseCase
+}
+
+func (u brokenTaskUsecase) Delete(ctx context.Context, id int64) (domain.DeleteTaskResponse, error) {
+	return domain.DeleteTaskResponse{}, errors.New("disk full")
+}
+
+func TestTaskSocketHandler_SystemError(t *testing.T) {
+	router := gin.New()
+	httpdelivery.NewTaskSocketHandler(router, brokenTaskUsecase{}, stream.NewBroker(0), testSocketPingInterval, testSocketSendBuffer)
+	ts := httptest.NewServer(router)
+	t.Cleanup(ts.Close)
+
+	conn := dialSocket(t, ts.URL)
+	sendSocket(t, conn, `{"id":"1","type":"delete","taskId":1}`)
+	got := readSocket(t, conn)
+	require.Equal(t, domain.SocketError, got.Type)
+	require.NotNil(t, got.Error)
+	require.Equal(t, domain.ErrSystemError.ErrorCode, got.Error.ErrorCode)
+}
+
+func TestTaskSocketHandler_Heartbeat(t *testing.T) {
+	router := gin.New()
+	u := usecase.NewTaskUsecase(inmemory.NewTaskRepository(), inmemory.NewUserRepository(), inmemory.NewCommentRepository(), domain.CommentPolicyDelete, nil)
+	httpdelivery.NewTaskSocketHandler(router, u, stream.NewBroker(0), 20*time.Millisecond, testSocketSendBuffer)
+	ts := httptest.NewServer(router)
+	t.Cleanup(ts.Close)
+
+	// A reading client answers the pings and stays connected.
+	alive := dialSocket(t, ts.URL)
+	pings := make(chan struct{}, 100)
+	alive.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return alive.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	done := make(chan error)
+	go func() {
+		_, _, err := alive.ReadMessage()
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	require.GreaterOrEqual(t, len(pings), 2)
+	sendSocket(t, alive, `{"id":"ping","type":"ping"}`)
+	require.NoError(t, <-done)
+
+	// A client that does not answer is dropped after two intervals.
+	silent := dialSocket(t, ts.URL)
+	silent.SetPingHandler(func(string) error { return nil })
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, silent.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err := silent.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "ReadMessage() error = %v", err)
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type SyncHandler struct {
+	syncUsecase domain.SyncUseCase
+}
+
+func NewSyncHandler(e *gin.Engine, syncUsecase domain.SyncUseCase) {
+	h := &SyncHandler{
+		syncUsecase: syncUsecase,
+	}
+	e.GET("/sync", h.Sync)
+}
+
+func (h *SyncHandler) Sync(ctx *gin.Context) {
+	var req domain.SyncRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.syncUsecase.Sync(ctx, req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidParameters:
+			ctx.JSON(http.StatusBadRequest, err)
+		case domain.ErrSyncTokenExpired:
+			ctx.JSON(http.StatusGone, err)
+		default:
+			ctx.JSON(http.StatusInternalServerError, err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
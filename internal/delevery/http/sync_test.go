@@ -0,0 +1,85 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestSyncHandler_Sync(t *testing.T) {
+	// {epoch} in query stands for the epoch of the change feed.
+	tests := []struct {
+		name          string
+		query         string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, epoch string)
+	}{
+		{
+			name:  "FromScratch",
+			query: "",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, epoch string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got domain.SyncResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Created, 1)
+				require.Equal(t, "TaskName2", got.Created[0].Name)
+				require.Empty(t, got.Deleted)
+				require.Equal(t, epoch+".3", got.Token)
+			},
+		},
+		{
+			name:  "Delta",
+			query: "?since={epoch}.2",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, epoch string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"created":[],"updated":[],"deleted":[{"id":1}],"token":"`+epoch+`.3"}`, recorder.Body.String())
+			},
+		},
+		{
+			name:  "BadToken",
+			query: "?since=abc",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, epoch string) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "ExpiredToken",
+			query: "?since={epoch}.40",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, epoch string) {
+				require.Equal(t, http.StatusGone, recorder.Code)
+			},
+		},
+		{
+			name:  "RestartedFeed",
+			query: "?since=other.2",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, epoch string) {
+				require.Equal(t, http.StatusGone, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			_, _ = server.U.Delete(context.Background(), 1)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/sync", nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			var sync domain.SyncResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &sync))
+			epoch := strings.Split(sync.Token, ".")[0]
+
+			recorder = httptest.NewRecorder()
+			request, err = http.NewRequest(http.MethodGet, "/sync"+strings.ReplaceAll(tt.query, "{epoch}", epoch), nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder, epoch)
+		})
+	}
+}
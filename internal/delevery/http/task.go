@@ -4,6 +4,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/markdown"
+	"strconv"
 )
 
 type TaskHandler struct {
@@ -16,8 +18,27 @@ func NewTaskHandler(e *gin.Engine, taskUsecse domain.TaskUseCase) {
 	}
 	e.GET("/tasks", h.List)
 	e.POST("/task", h.Create)
+	e.GET("/task/:task_id", h.Get)
 	e.PUT("/task/:task_id", h.Update)
 	e.DELETE("/task/:task_id", h.Delete)
+	e.GET("/tasks/mine", h.ListMine)
+	e.PUT("/task/:task_id/assignee", h.Assign)
+	e.DELETE("/task/:task_id/assignee", h.Unassign)
+	e.PUT("/task/:task_id/estimate", h.SetEstimate)
+	e.DELETE("/task/:task_id/estimate", h.ClearEstimate)
+	e.POST("/task/:task_id/move", h.Move)
+	e.POST("/task/:task_id/restore", h.Restore)
+	e.POST("/task/:task_id/archive", h.Archive)
+	e.POST("/task/:task_id/unarchive", h.Unarchive)
+}
+
+// currentUserID reads the caller's user ID from the X-User-ID header.
+func currentUserID(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.GetHeader(domain.UserIDHeader), 10, 64)
+	if err != nil || id < 1 {
+		return 0, false
+	}
+	return id, true
 }
 
 func (h *TaskHandler) Create(ctx *gin.Context) {
@@ -36,21 +57,72 @@ func (h *TaskHandler) Create(ctx *gin.Context) {
 }
 
 func (h *TaskHandler) List(ctx *gin.Context) {
-	rtn, err := h.taskUsecse.List(ctx)
+	var req domain.ListTaskRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	rtn, err := h.taskUsecse.List(ctx, req)
 	if err != nil {
+		if err == domain.ErrHistoryExpired {
+			ctx.JSON(http.StatusGone, err)
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, rtn)
 }
 
+func (h *TaskHandler) Get(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.GetTaskRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	var task domain.Task
+	var err error
+	if req.AsOf.IsZero() {
+		task, err = h.taskUsecse.Get(ctx, para.ID)
+	} else {
+		task, err = h.taskUsecse.GetAsOf(ctx, para.ID, req.AsOf)
+	}
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrHistoryExpired {
+			ctx.JSON(http.StatusGone, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	rtn := domain.GetTaskResponse{Result: task}
+	if req.Render == domain.RenderHTML && task.Description != "" {
+		rtn.DescriptionHTML, err = markdown.Render(task.Description)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, domain.ErrSystemError)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
 func (h *TaskHandler) Delete(ctx *gin.Context) {
 	var req domain.DeleteTaskRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
 		return
 	}
-	err := h.taskUsecse.Delete(ctx, req.ID)
+	rtn, err := h.taskUsecse.Delete(ctx, req.ID)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			ctx.JSON(http.StatusNotFound, err)
@@ -59,7 +131,7 @@ func (h *TaskHandler) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, nil)
+	ctx.JSON(http.StatusOK, rtn)
 }
 
 func (h *TaskHandler) Update(ctx *gin.Context) {
@@ -89,3 +161,208 @@ func (h *TaskHandler) Update(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, rtn)
 }
+
+func (h *TaskHandler) ListMine(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	rtn, err := h.taskUsecse.ListByAssignee(ctx, userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) Assign(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.AssignTaskRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.taskUsecse.Assign(ctx, para.ID, req.AssigneeID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrUserNotFound {
+			ctx.JSON(http.StatusBadRequest, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) Unassign(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.taskUsecse.Unassign(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) SetEstimate(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.Estimate
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.taskUsecse.SetEstimate(ctx, para.ID, req)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) ClearEstimate(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.taskUsecse.ClearEstimate(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) Move(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	var req domain.MoveTaskRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.taskUsecse.Move(ctx, para.ID, req)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrInvalidPayload {
+			ctx.JSON(http.StatusBadRequest, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) Restore(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.taskUsecse.Restore(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrTaskNotDeleted {
+			ctx.JSON(http.StatusConflict, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) Archive(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.taskUsecse.Archive(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrTaskArchived {
+			ctx.JSON(http.StatusConflict, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TaskHandler) Unarchive(ctx *gin.Context) {
+	var para domain.TaskUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.taskUsecse.Unarchive(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		if err == domain.ErrTaskNotArchived {
+			ctx.JSON(http.StatusConflict, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
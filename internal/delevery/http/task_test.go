@@ -1,4 +1,4 @@
-package http
+package http_test
 
 import (
 	"bytes"
@@ -9,8 +9,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"oa-gogolook/internal/domain"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTaskHandler_Create(t *testing.T) {
@@ -18,6 +21,7 @@ func TestTaskHandler_Create(t *testing.T) {
 		ID:     1,
 		Status: 0,
 		Name:   "TaskName1",
+		Rank:   "V",
 	}}
 	tests := []struct {
 		name          string
@@ -37,6 +41,8 @@ func TestTaskHandler_Create(t *testing.T) {
 				var task domain.CreateTaskResponse
 				err = json.Unmarshal(data, &task)
 				require.NoError(t, err)
+				require.False(t, task.Result.CreatedAt.IsZero())
+				task.Result.CreatedAt = time.Time{}
 				require.Equal(t, expectTask, task)
 			},
 		},
@@ -283,3 +289,611 @@ func TestTaskHandler_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskHandler_Assign(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		query         domain.AssignTaskRequest
+		buildStubs    func(s TestServer)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			taskID: 1,
+			query:  domain.AssignTaskRequest{AssigneeID: 1},
+			buildStubs: func(s TestServer) {
+				_, _ = s.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+				_, _ = s.User.Create(context.Background(), domain.CreateUserRequest{Name: "UserName1"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.AssignTaskResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.NotNil(t, rtn.Result.AssigneeID)
+				require.Equal(t, int64(1), *rtn.Result.AssigneeID)
+			},
+		},
+		{
+			name:   "AssigneeNotFound",
+			taskID: 1,
+			query:  domain.AssignTaskRequest{AssigneeID: 1},
+			buildStubs: func(s TestServer) {
+				_, _ = s.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "TaskNotFound",
+			taskID: 1,
+			query:  domain.AssignTaskRequest{AssigneeID: 1},
+			buildStubs: func(s TestServer) {
+				_, _ = s.User.Create(context.Background(), domain.CreateUserRequest{Name: "UserName1"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidPayload",
+			taskID: 1,
+			query:  domain.AssignTaskRequest{AssigneeID: 0},
+			buildStubs: func(s TestServer) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			tt.buildStubs(server)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/task/%d/assignee", tt.taskID)
+			data, err := json.Marshal(tt.query)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTaskHandler_Unassign(t *testing.T) {
+	server := newTestServer(t)
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	user, _ := server.User.Create(context.Background(), domain.CreateUserRequest{Name: "UserName1"})
+	_, _ = server.U.Assign(context.Background(), 1, user.Result.ID)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodDelete, "/task/1/assignee", nil)
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	task, err := server.U.Get(context.Background(), 1)
+	require.NoError(t, err)
+	require.Nil(t, task.AssigneeID)
+}
+
+func TestTaskHandler_ListMine(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		buildStubs    func(s TestServer)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: "1",
+			buildStubs: func(s TestServer) {
+				_, _ = s.User.Create(context.Background(), domain.CreateUserRequest{Name: "UserName1"})
+				_, _ = s.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+				_, _ = s.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+				_, _ = s.U.Assign(context.Background(), 2, 1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var tasks domain.ListTaskResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &tasks)
+				require.NoError(t, err)
+				require.Equal(t, 1, len(tasks.Result))
+				require.Equal(t, "TaskName2", tasks.Result[0].Name)
+			},
+		},
+		{
+			name:   "MissingHeader",
+			userID: "",
+			buildStubs: func(s TestServer) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "UnknownUser",
+			userID: "5",
+			buildStubs: func(s TestServer) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			tt.buildStubs(server)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/tasks/mine", nil)
+			require.NoError(t, err)
+			if tt.userID != "" {
+				request.Header.Set(domain.UserIDHeader, tt.userID)
+			}
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTaskHandler_Get(t *testing.T) {
+	description := "# Plan\n\n<script>alert(1)</script>\n\n**bold**"
+	tests := []struct {
+		name          string
+		url           string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  "/task/1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.GetTaskResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, description, rtn.Result.Description)
+				require.Empty(t, rtn.DescriptionHTML)
+			},
+		},
+		{
+			name: "RenderHTML",
+			url:  "/task/1?render=html",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.GetTaskResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Contains(t, rtn.DescriptionHTML, "<h1>Plan</h1>")
+				require.Contains(t, rtn.DescriptionHTML, "<strong>bold</strong>")
+				require.NotContains(t, rtn.DescriptionHTML, "<script")
+			},
+		},
+		{
+			name: "UnknownRender",
+			url:  "/task/1?render=pdf",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			url:  "/task/5",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1", Description: description})
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+// TestTaskHandler_DescriptionTooLong checks the max=10000 binding of the description.
+func TestTaskHandler_DescriptionTooLong(t *testing.T) {
+	tooLong := strings.Repeat("a", 10001)
+	server := newTestServer(t)
+	recorder := httptest.NewRecorder()
+	data, err := json.Marshal(domain.CreateTaskRequest{
+		Name:        "TaskName1",
+		Description: tooLong,
+	})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/task", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	recorder = httptest.NewRecorder()
+	data, err = json.Marshal(domain.UpdateTaskRequest{
+		ID:          1,
+		Status:      &domain.StatusIncomplete,
+		Name:        "TaskName1",
+		Description: &tooLong,
+	})
+	require.NoError(t, err)
+	request, err = http.NewRequest(http.MethodPut, "/task/1", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestTaskHandler_Move(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		query         domain.MoveTaskRequest
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer)
+	}{
+		{
+			name:   "OK",
+			taskID: 1,
+			query:  domain.MoveTaskRequest{AfterID: 3},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				tasks, err := s.U.List(context.Background(), domain.ListTaskRequest{})
+				require.NoError(t, err)
+				require.Equal(t, []int64{2, 3, 1}, []int64{tasks.Result[0].ID, tasks.Result[1].ID, tasks.Result[2].ID})
+			},
+		},
+		{
+			name:   "BothSet",
+			taskID: 1,
+			query:  domain.MoveTaskRequest{BeforeID: 2, AfterID: 3},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			taskID: 1,
+			query:  domain.MoveTaskRequest{BeforeID: 9},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			for _, name := range []string{"TaskName1", "TaskName2", "TaskName3"} {
+				_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: name})
+			}
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(tt.query)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/task/%d/move", tt.taskID), bytes.NewReader(data))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder, server)
+		})
+	}
+}
+
+func TestTaskHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskID        int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer)
+	}{
+		{
+			name:   "OK",
+			taskID: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				_, err := s.U.Get(context.Background(), 1)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "NotDeleted",
+			taskID: 2,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			taskID: 9,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			_, err := server.U.Delete(context.Background(), 1)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/task/%d/restore", tt.taskID), nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder, server)
+		})
+	}
+}
+
+func TestTaskHandler_ListIncludeDeleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantIDs []int64
+	}{
+		{
+			name:    "Default",
+			query:   "",
+			wantIDs: []int64{2},
+		},
+		{
+			name:    "IncludeDeleted",
+			query:   "?includeDeleted=true",
+			wantIDs: []int64{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			_, err := server.U.Delete(context.Background(), 1)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/tasks"+tt.query, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			var got domain.ListTaskResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			ids := make([]int64, 0, len(got.Result))
+			for _, task := range got.Result {
+				ids = append(ids, task.ID)
+			}
+			require.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestTaskHandler_Archive(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer)
+	}{
+		{
+			name: "Archive",
+			path: "/task/2/archive",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				tasks, err := s.U.List(context.Background(), domain.ListTaskRequest{})
+				require.NoError(t, err)
+				require.Len(t, tasks.Result, 0)
+			},
+		},
+		{
+			name: "AlreadyArchived",
+			path: "/task/1/archive",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "Unarchive",
+			path: "/task/1/unarchive",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				tasks, err := s.U.List(context.Background(), domain.ListTaskRequest{})
+				require.NoError(t, err)
+				require.Len(t, tasks.Result, 2)
+			},
+		},
+		{
+			name: "NotArchived",
+			path: "/task/2/unarchive",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			path: "/task/9/archive",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			_, err := server.U.Archive(context.Background(), 1)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, tt.path, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder, server)
+		})
+	}
+}
+
+func TestTaskHandler_AsOf(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "GetPast",
+			path: "/task/1?asOf=%s",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got domain.GetTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, domain.StatusIncomplete, got.Result.Status)
+			},
+		},
+		{
+			name: "GetNow",
+			path: "/task/1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got domain.GetTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, domain.StatusComplete, got.Result.Status)
+			},
+		},
+		{
+			name: "ListPast",
+			path: "/tasks?asOf=%s",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got domain.ListTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Result, 2)
+			},
+		},
+		{
+			name: "ListNow",
+			path: "/tasks",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var got domain.ListTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Len(t, got.Result, 1)
+			},
+		},
+		{
+			name: "BeforeCreate",
+			path: "/task/1?asOf=2000-01-01T00:00:00Z",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BadTimestamp",
+			path: "/tasks?asOf=yesterday",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			asOf := time.Now().UTC()
+			time.Sleep(time.Millisecond)
+			status := domain.StatusComplete
+			_, err := server.U.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "TaskName1"})
+			require.NoError(t, err)
+			_, err = server.U.Delete(context.Background(), 2)
+			require.NoError(t, err)
+
+			path := tt.path
+			if strings.Contains(path, "%s") {
+				path = fmt.Sprintf(path, url.QueryEscape(asOf.Format(time.RFC3339Nano)))
+			}
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, path, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTaskHandler_Estimate(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		taskID        int64
+		body          interface{}
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Set",
+			method: http.MethodPut,
+			taskID: 1,
+			body:   domain.Estimate{Value: 5, Unit: domain.EstimateUnitPoints},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.EstimateTaskResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, &domain.Estimate{Value: 5, Unit: domain.EstimateUnitPoints}, rtn.Result.Estimate)
+			},
+		},
+		{
+			name:   "Clear",
+			method: http.MethodDelete,
+			taskID: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.EstimateTaskResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Nil(t, rtn.Result.Estimate)
+			},
+		},
+		{
+			name:   "UnknownUnit",
+			method: http.MethodPut,
+			taskID: 1,
+			body:   domain.Estimate{Value: 5, Unit: "days"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "ZeroValue",
+			method: http.MethodPut,
+			taskID: 1,
+			body:   domain.Estimate{Unit: domain.EstimateUnitHours},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "TaskNotFound",
+			method: http.MethodPut,
+			taskID: 5,
+			body:   domain.Estimate{Value: 5, Unit: domain.EstimateUnitPoints},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{
+				Name:     "TaskName1",
+				Estimate: &domain.Estimate{Value: 1, Unit: domain.EstimateUnitHours},
+			})
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(tt.method, fmt.Sprintf("/task/%d/estimate", tt.taskID), bytes.NewReader(data))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+type TaskEventHandler struct {
+	broker    domain.TaskEventBroker
+	heartbeat time.Duration
+}
+
+// NewTaskEventHandler streams the task changes as Server-Sent Events, an idle stream gets
+// a comment line every heartbeat so proxies keep the connection open.
+func NewTaskEventHandler(e *gin.Engine, broker domain.TaskEventBroker, heartbeat time.Duration) {
+	h := &TaskEventHandler{
+		broker:    broker,
+		heartbeat: heartbeat,
+	}
+	e.GET("/tasks/events", h.Stream)
+}
+
+func (h *TaskEventHandler) Stream(ctx *gin.Context) {
+	var epoch string
+	var lastEventID *int64
+	if header := ctx.GetHeader(domain.LastEventIDHeader); header != "" {
+		var id int64
+		var err error
+		epoch, id, err = domain.ParseTaskEventID(header)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+			return
+		}
+		lastEventID = &id
+	}
+
+	sub, cancel := h.broker.Subscribe(epoch, lastEventID)
+	defer cancel()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Header("Content-Type", sse.ContentType)
+	ctx.Status(http.StatusOK)
+	ctx.Writer.WriteHeaderNow()
+	if sub.Missed {
+		ctx.Render(-1, sse.Event{
+			Id:    domain.FormatTaskEventID(sub.Epoch, sub.LastID),
+			Event: domain.TaskEventReset,
+			Data:  "",
+		})
+	}
+	for _, event := range sub.Replay {
+		h.render(ctx, event)
+	}
+	ctx.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return false
+			}
+			h.render(ctx, event)
+			return true
+		case <-ticker.C:
+			_, err := io.WriteString(w, ":\n\n")
+			return err == nil
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (h *TaskEventHandler) render(ctx *gin.Context, event domain.TaskEvent) {
+	ctx.Render(-1, sse.Event{
+		Id:    domain.FormatTaskEventID(event.Epoch, event.ID),
+		Event: string(event.Type),
+		Data:  event,
+	})
+}
@@ -0,0 +1,122 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"strings"
+	"testing"
+)
+
+// sseEvent is one event read from a stream, comments are skipped.
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	var rtn sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if rtn.Event != "" {
+				return rtn
+			}
+		case strings.HasPrefix(line, "id:"):
+			rtn.ID = line[len("id:"):]
+		case strings.HasPrefix(line, "event:"):
+			rtn.Event = line[len("event:"):]
+		case strings.HasPrefix(line, "data:"):
+			rtn.Data = line[len("data:"):]
+		}
+	}
+}
+
+func openTaskEvents(t *testing.T, url string, lastEventID string) *bufio.Reader {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/tasks/events", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		request.Header.Set(domain.LastEventIDHeader, lastEventID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = response.Body.Close() })
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	return bufio.NewReader(response.Body)
+}
+
+func TestTaskEventHandler_Stream(t *testing.T) {
+	server := newTestServer(t)
+	ts := httptest.NewServer(server.Router)
+	t.Cleanup(ts.Close)
+
+	live := openTaskEvents(t, ts.URL, "")
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+	status := domain.StatusComplete
+	_, _ = server.U.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Name: "TaskName1", Status: &status})
+	_, _ = server.U.Delete(context.Background(), 1)
+
+	got := readSSEEvent(t, live)
+	var event domain.TaskEvent
+	require.NoError(t, json.Unmarshal([]byte(got.Data), &event))
+	require.NotEmpty(t, event.Epoch)
+	require.Equal(t, "TaskName1", event.Task.Name)
+	id := func(n int64) string { return domain.FormatTaskEventID(event.Epoch, n) }
+	require.Equal(t, sseEvent{ID: id(1), Event: "task.created"}, sseEvent{ID: got.ID, Event: got.Event})
+	require.Equal(t, "task.updated", readSSEEvent(t, live).Event)
+	require.Equal(t, "task.deleted", readSSEEvent(t, live).Event)
+	// The replay buffer of the test server holds 3 events, the first one leaves it now.
+	_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []sseEvent
+	}{
+		{
+			name:        "Resume",
+			lastEventID: id(1),
+			want:        []sseEvent{{ID: id(2), Event: "task.updated"}, {ID: id(3), Event: "task.deleted"}, {ID: id(4), Event: "task.created"}},
+		},
+		{
+			name:        "Evicted",
+			lastEventID: id(0),
+			want:        []sseEvent{{ID: id(4), Event: domain.TaskEventReset}},
+		},
+		{
+			name:        "BeforeRestart",
+			lastEventID: id(40),
+			want:        []sseEvent{{ID: id(4), Event: domain.TaskEventReset}},
+		},
+		{
+			name:        "OtherEpoch",
+			lastEventID: domain.FormatTaskEventID("other", 2),
+			want:        []sseEvent{{ID: id(4), Event: domain.TaskEventReset}},
+		},
+		{
+			name:        "WithoutEpoch",
+			lastEventID: "2",
+			want:        []sseEvent{{ID: id(4), Event: domain.TaskEventReset}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := openTaskEvents(t, ts.URL, tt.lastEventID)
+			for _, want := range tt.want {
+				got := readSSEEvent(t, reader)
+				require.Equal(t, want, sseEvent{ID: got.ID, Event: got.Event})
+			}
+		})
+	}
+}
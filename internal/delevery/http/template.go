@@ -0,0 +1,107 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type TemplateHandler struct {
+	templateUsecase domain.TemplateUseCase
+}
+
+func NewTemplateHandler(e *gin.Engine, templateUsecase domain.TemplateUseCase) {
+	h := &TemplateHandler{
+		templateUsecase: templateUsecase,
+	}
+	e.GET("/templates", h.List)
+	e.POST("/templates", h.Create)
+	e.GET("/templates/:template_id", h.Get)
+	e.DELETE("/templates/:template_id", h.Delete)
+	e.POST("/templates/:template_id/instantiate", h.Instantiate)
+}
+
+func (h *TemplateHandler) List(ctx *gin.Context) {
+	rtn, err := h.templateUsecase.List(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TemplateHandler) Create(ctx *gin.Context) {
+	var req domain.CreateTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.templateUsecase.Create(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *TemplateHandler) Get(ctx *gin.Context) {
+	var para domain.TemplateUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.templateUsecase.Get(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TemplateHandler) Delete(ctx *gin.Context) {
+	var para domain.TemplateUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	err := h.templateUsecase.Delete(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// Instantiate accepts an empty body, the due dates then count from now.
+func (h *TemplateHandler) Instantiate(ctx *gin.Context) {
+	var para domain.TemplateUriParameter
+	var req domain.InstantiateTemplateRequest
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.templateUsecase.Instantiate(ctx, para.ID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *TemplateHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,131 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func TestTemplateHandler_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: `{"name":"onboarding","tasks":[{"name":"laptop","dueInDays":2,"tags":["it"],"subtasks":["order"]}]}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var rtn domain.TemplateResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), rtn.Result.ID)
+				require.Equal(t, []string{"order"}, rtn.Result.Tasks[0].Subtasks)
+			},
+		},
+		{
+			name: "NoTasks",
+			body: `{"name":"onboarding","tasks":[]}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TaskWithoutName",
+			body: `{"name":"onboarding","tasks":[{"dueInDays":2}]}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmptySubtask",
+			body: `{"name":"onboarding","tasks":[{"name":"laptop","subtasks":[""]}]}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/templates", bytes.NewReader([]byte(tt.body)))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTemplateHandler_Instantiate(t *testing.T) {
+	tests := []struct {
+		name          string
+		templateID    int64
+		body          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			templateID: 1,
+			body:       `{"start":"2022-11-01T00:00:00Z"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var rtn domain.InstantiateTemplateResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, 2, len(rtn.Result))
+				require.Equal(t, "2022-11-03T00:00:00Z", rtn.Result[0].DueAt.Format("2006-01-02T15:04:05Z07:00"))
+				require.Equal(t, []string{"it"}, rtn.Result[0].Tags)
+				require.Equal(t, "order", rtn.Result[0].Checklist[0].Text)
+			},
+		},
+		{
+			name:       "EmptyBody",
+			templateID: 1,
+			body:       "",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			templateID: 5,
+			body:       "",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "BadStart",
+			templateID: 1,
+			body:       `{"start":"tomorrow"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			recorder := httptest.NewRecorder()
+			body := `{"name":"onboarding","tasks":[{"name":"laptop","dueInDays":2,"tags":["it"],"subtasks":["order"]},{"name":"badge"}]}`
+			request, err := http.NewRequest(http.MethodPost, "/templates", bytes.NewReader([]byte(body)))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusCreated, recorder.Code)
+
+			recorder = httptest.NewRecorder()
+			url := fmt.Sprintf("/templates/%d/instantiate", tt.templateID)
+			request, err = http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(tt.body)))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type TimeTrackingHandler struct {
+	timeTrackingUsecase domain.TimeTrackingUseCase
+}
+
+func NewTimeTrackingHandler(e *gin.Engine, timeTrackingUsecase domain.TimeTrackingUseCase) {
+	h := &TimeTrackingHandler{
+		timeTrackingUsecase: timeTrackingUsecase,
+	}
+	e.POST("/task/:task_id/timer/start", h.StartTimer)
+	e.POST("/task/:task_id/timer/stop", h.StopTimer)
+	e.GET("/task/:task_id/time-entries", h.List)
+	e.POST("/task/:task_id/time-entries", h.AddEntry)
+	e.GET("/time-report", h.Report)
+}
+
+func (h *TimeTrackingHandler) StartTimer(ctx *gin.Context) {
+	var para domain.TimeEntryUriParameter
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.timeTrackingUsecase.StartTimer(ctx, para.TaskID, userID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *TimeTrackingHandler) StopTimer(ctx *gin.Context) {
+	var para domain.TimeEntryUriParameter
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.timeTrackingUsecase.StopTimer(ctx, para.TaskID, userID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TimeTrackingHandler) AddEntry(ctx *gin.Context) {
+	var para domain.TimeEntryUriParameter
+	var req domain.AddTimeEntryRequest
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+		return
+	}
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.timeTrackingUsecase.AddEntry(ctx, para.TaskID, userID, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *TimeTrackingHandler) List(ctx *gin.Context) {
+	var para domain.TimeEntryUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.timeTrackingUsecase.List(ctx, para.TaskID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TimeTrackingHandler) Report(ctx *gin.Context) {
+	var req domain.TimeReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.timeTrackingUsecase.Report(ctx, req)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *TimeTrackingHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	case domain.ErrUserNotFound:
+		ctx.JSON(http.StatusUnauthorized, domain.ErrUnauthorized)
+	case domain.ErrTimerRunning, domain.ErrTimerNotRunning:
+		ctx.JSON(http.StatusConflict, err)
+	case domain.ErrInvalidPayload:
+		ctx.JSON(http.StatusBadRequest, err)
+	case domain.ErrInvalidParameters:
+		ctx.JSON(http.StatusBadRequest, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
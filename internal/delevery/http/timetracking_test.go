@@ -0,0 +1,166 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestTimeTrackingHandler_Timer(t *testing.T) {
+	tests := []struct {
+		name          string
+		paths         []string
+		userID        string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Start",
+			paths:  []string{"/task/1/timer/start"},
+			userID: "1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var rtn domain.TimeEntryResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), rtn.Result.TaskID)
+				require.Nil(t, rtn.Result.End)
+			},
+		},
+		{
+			name:   "StartTwice",
+			paths:  []string{"/task/1/timer/start", "/task/2/timer/start"},
+			userID: "1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "Stop",
+			paths:  []string{"/task/1/timer/start", "/task/1/timer/stop"},
+			userID: "1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.TimeEntryResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.NotNil(t, rtn.Result.End)
+			},
+		},
+		{
+			name:   "StopNotRunning",
+			paths:  []string{"/task/1/timer/stop"},
+			userID: "1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "TaskNotFound",
+			paths:  []string{"/task/5/timer/start"},
+			userID: "1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Unauthorized",
+			paths:  []string{"/task/1/timer/start"},
+			userID: "",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			buildCommentStubs(server)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			var recorder *httptest.ResponseRecorder
+			for _, path := range tt.paths {
+				recorder = httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodPost, path, nil)
+				require.NoError(t, err)
+				if tt.userID != "" {
+					request.Header.Set(domain.UserIDHeader, tt.userID)
+				}
+				server.Router.ServeHTTP(recorder, request)
+			}
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTimeTrackingHandler_Report(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?from=2022-11-01&to=2022-11-01",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.TimeReportResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, int64(3600), rtn.Total)
+				require.Equal(t, 1, len(rtn.Tasks))
+				require.Equal(t, []domain.DayTotal{{Date: "2022-11-01", Seconds: 3600}}, rtn.ByDay)
+			},
+		},
+		{
+			name:  "OtherUser",
+			query: "?from=2022-11-01&to=2022-11-01&userId=2",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.TimeReportResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, int64(0), rtn.Total)
+			},
+		},
+		{
+			name:  "MissingRange",
+			query: "?from=2022-11-01",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "BadDate",
+			query: "?from=2022-11-01&to=11/02/2022",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			buildCommentStubs(server)
+			start := time.Date(2022, 11, 1, 9, 0, 0, 0, time.UTC)
+			data, err := json.Marshal(domain.AddTimeEntryRequest{Start: start, End: start.Add(time.Hour)})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/task/1/time-entries", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(domain.UserIDHeader, "1")
+			recorder := httptest.NewRecorder()
+			server.Router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusCreated, recorder.Code)
+
+			recorder = httptest.NewRecorder()
+			request, err = http.NewRequest(http.MethodGet, "/time-report"+tt.query, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
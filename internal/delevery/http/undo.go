@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type UndoHandler struct {
+	undoUsecase domain.UndoUseCase
+}
+
+func NewUndoHandler(e *gin.Engine, undoUsecase domain.UndoUseCase) {
+	h := &UndoHandler{
+		undoUsecase: undoUsecase,
+	}
+	e.POST("/undo/:token", h.Undo)
+}
+
+func (h *UndoHandler) Undo(ctx *gin.Context) {
+	var para domain.UndoUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.undoUsecase.Undo(ctx, para.Token)
+	if err != nil {
+		switch err {
+		case domain.ErrUndoNotFound:
+			ctx.JSON(http.StatusNotFound, err)
+		case domain.ErrUndoExpired:
+			ctx.JSON(http.StatusGone, err)
+		case domain.ErrUndoConflict:
+			ctx.JSON(http.StatusConflict, err)
+		default:
+			ctx.JSON(http.StatusInternalServerError, err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
@@ -0,0 +1,96 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func TestUndoHandler_Undo(t *testing.T) {
+	tests := []struct {
+		name          string
+		operate       func(t *testing.T, s TestServer) string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer)
+	}{
+		{
+			name: "Update",
+			operate: func(t *testing.T, s TestServer) string {
+				status := domain.StatusComplete
+				data, err := json.Marshal(domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "TaskName1"})
+				require.NoError(t, err)
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodPut, "/task/1", bytes.NewReader(data))
+				require.NoError(t, err)
+				s.Router.ServeHTTP(recorder, request)
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.UpdateTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rtn))
+				return rtn.UndoToken
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				task, err := s.U.Get(context.Background(), 1)
+				require.NoError(t, err)
+				require.Equal(t, domain.StatusIncomplete, task.Status)
+			},
+		},
+		{
+			name: "Delete",
+			operate: func(t *testing.T, s TestServer) string {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodDelete, "/task/1", nil)
+				require.NoError(t, err)
+				s.Router.ServeHTTP(recorder, request)
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.DeleteTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rtn))
+				return rtn.UndoToken
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				_, err := s.U.Get(context.Background(), 1)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Conflict",
+			operate: func(t *testing.T, s TestServer) string {
+				rtn, err := s.U.Delete(context.Background(), 1)
+				require.NoError(t, err)
+				_, err = s.U.Restore(context.Background(), 1)
+				require.NoError(t, err)
+				return rtn.UndoToken
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			operate: func(t *testing.T, s TestServer) string {
+				return "unknown"
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, s TestServer) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName1"})
+			token := tt.operate(t, server)
+			require.NotEmpty(t, token)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/undo/"+token, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder, server)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type UserHandler struct {
+	userUsecase domain.UserUseCase
+}
+
+func NewUserHandler(e *gin.Engine, userUsecase domain.UserUseCase) {
+	h := &UserHandler{
+		userUsecase: userUsecase,
+	}
+	e.GET("/users", h.List)
+	e.POST("/user", h.Create)
+	e.GET("/user/:user_id", h.Get)
+}
+
+func (h *UserHandler) Create(ctx *gin.Context) {
+	var req domain.CreateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.userUsecase.Create(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *UserHandler) List(ctx *gin.Context) {
+	rtn, err := h.userUsecase.List(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *UserHandler) Get(ctx *gin.Context) {
+	var para domain.GetUserUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+	rtn, err := h.userUsecase.Get(ctx, para.ID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			ctx.JSON(http.StatusNotFound, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
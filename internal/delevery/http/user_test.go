@@ -0,0 +1,114 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func TestUserHandler_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         domain.CreateUserRequest
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: domain.CreateUserRequest{
+				Name: "UserName1",
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+				var user domain.CreateUserResponse
+				err = json.Unmarshal(data, &user)
+				require.NoError(t, err)
+				require.Equal(t, domain.User{ID: 1, Name: "UserName1"}, user.Result)
+			},
+		},
+		{
+			name: "EmptyName",
+			query: domain.CreateUserRequest{
+				Name: "",
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			recorder := httptest.NewRecorder()
+			data, err := json.Marshal(tt.query)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/user", bytes.NewReader(data))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUserHandler_Get(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		buildStubs    func(s *domain.UserUseCase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: 1,
+			buildStubs: func(s *domain.UserUseCase) {
+				u := *s
+				_, _ = u.Create(context.Background(), domain.CreateUserRequest{Name: "UserName1"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var user domain.User
+				err := json.Unmarshal(recorder.Body.Bytes(), &user)
+				require.NoError(t, err)
+				require.Equal(t, "UserName1", user.Name)
+			},
+		},
+		{
+			name:   "NotFound",
+			userID: 1,
+			buildStubs: func(s *domain.UserUseCase) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "BadParam",
+			userID: 0,
+			buildStubs: func(s *domain.UserUseCase) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			tt.buildStubs(&server.User)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/user/%d", tt.userID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
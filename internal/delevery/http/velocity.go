@@ -0,0 +1,33 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type VelocityHandler struct {
+	velocityUsecase domain.VelocityUseCase
+}
+
+func NewVelocityHandler(e *gin.Engine, velocityUsecase domain.VelocityUseCase) {
+	h := &VelocityHandler{
+		velocityUsecase: velocityUsecase,
+	}
+	e.GET("/velocity-report", h.Report)
+}
+
+func (h *VelocityHandler) Report(ctx *gin.Context) {
+	var req domain.VelocityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.velocityUsecase.Velocity(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
@@ -0,0 +1,71 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+)
+
+func TestVelocityHandler_Report(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?weeks=4&window=2",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rtn domain.VelocityResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rtn)
+				require.NoError(t, err)
+				require.Equal(t, domain.EstimateUnitPoints, rtn.Unit)
+				require.Equal(t, 4, len(rtn.Weeks))
+				current := rtn.Weeks[len(rtn.Weeks)-1]
+				require.Equal(t, 3.0, current.Completed)
+				require.Equal(t, 2, current.Tasks)
+				require.Equal(t, 1, current.Unestimated)
+			},
+		},
+		{
+			name:  "BadUnit",
+			query: "?unit=days",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "TooManyWeeks",
+			query: "?weeks=53",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{
+				Name:     "TaskName1",
+				Estimate: &domain.Estimate{Value: 3, Unit: domain.EstimateUnitPoints},
+			})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName2"})
+			_, _ = server.U.Create(context.Background(), domain.CreateTaskRequest{Name: "TaskName3"})
+			status := domain.StatusComplete
+			for id, name := range map[int64]string{1: "TaskName1", 2: "TaskName2"} {
+				_, err := server.U.Update(context.Background(), domain.UpdateTaskRequest{ID: id, Status: &status, Name: name})
+				require.NoError(t, err)
+			}
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/velocity-report"+tt.query, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
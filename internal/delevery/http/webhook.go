@@ -0,0 +1,112 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oa-gogolook/internal/domain"
+)
+
+type WebhookHandler struct {
+	webhookUsecase domain.WebhookUseCase
+}
+
+func NewWebhookHandler(e *gin.Engine, webhookUsecase domain.WebhookUseCase) {
+	h := &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+	e.GET("/webhooks", h.List)
+	e.POST("/webhooks", h.Create)
+	e.GET("/webhooks/:webhook_id", h.Get)
+	e.DELETE("/webhooks/:webhook_id", h.Delete)
+	e.GET("/webhook-dead-letters", h.ListDeadLetters)
+	e.POST("/webhook-dead-letters/:delivery_id/retry", h.Retry)
+}
+
+func (h *WebhookHandler) List(ctx *gin.Context) {
+	rtn, err := h.webhookUsecase.List(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *WebhookHandler) Create(ctx *gin.Context) {
+	var req domain.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidPayload)
+		return
+	}
+
+	rtn, err := h.webhookUsecase.Create(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rtn)
+}
+
+func (h *WebhookHandler) Get(ctx *gin.Context) {
+	var para domain.WebhookUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.webhookUsecase.Get(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *WebhookHandler) Delete(ctx *gin.Context) {
+	var para domain.WebhookUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	err := h.webhookUsecase.Delete(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (h *WebhookHandler) ListDeadLetters(ctx *gin.Context) {
+	rtn, err := h.webhookUsecase.ListDeadLetters(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *WebhookHandler) Retry(ctx *gin.Context) {
+	var para domain.WebhookDeliveryUriParameter
+	if err := ctx.ShouldBindUri(&para); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrInvalidParameters)
+		return
+	}
+
+	rtn, err := h.webhookUsecase.Retry(ctx, para.ID)
+	if err != nil {
+		h.handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rtn)
+}
+
+func (h *WebhookHandler) handleError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ctx.JSON(http.StatusNotFound, err)
+	case domain.ErrDeliveryNotDead:
+		ctx.JSON(http.StatusConflict, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,133 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/webhook"
+	"testing"
+	"time"
+)
+
+func TestWebhookHandler_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: `{"url":"http://example.com/hook","events":["task.created","task.deleted"],"secret":"0123456789abcdef"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "0123456789abcdef")
+			},
+		},
+		{
+			name: "BadURL",
+			body: `{"url":"example","events":["task.created"],"secret":"0123456789abcdef"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotHTTP",
+			body: `{"url":"file:///etc/passwd","events":["task.created"],"secret":"0123456789abcdef"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownEvent",
+			body: `{"url":"http://example.com/hook","events":["task.renamed"],"secret":"0123456789abcdef"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoEvents",
+			body: `{"url":"http://example.com/hook","events":[],"secret":"0123456789abcdef"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ShortSecret",
+			body: `{"url":"http://example.com/hook","events":["task.created"],"secret":"short"}`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte(tt.body)))
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestWebhookHandler_Delivery(t *testing.T) {
+	var got []*http.Request
+	var bodies [][]byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r)
+		bodies = append(bodies, body)
+	}))
+	defer receiver.Close()
+	server := newTestServer(t)
+	secret := "0123456789abcdef"
+
+	recorder := httptest.NewRecorder()
+	body := fmt.Sprintf(`{"url":%q,"events":["task.created"],"secret":%q}`, receiver.URL, secret)
+	request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/task", bytes.NewReader([]byte(`{"name":"TaskName1"}`)))
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	n, err := server.Webhook.DeliverDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, got, 1)
+	require.Equal(t, string(domain.WebhookEventTaskCreated), got[0].Header.Get(domain.WebhookEventHeader))
+	require.True(t, webhook.Verify(secret, got[0].Header.Get(domain.WebhookTimestampHeader), bodies[0], got[0].Header.Get(domain.WebhookSignatureHeader), time.Now()))
+	var payload domain.WebhookPayload
+	require.NoError(t, json.Unmarshal(bodies[0], &payload))
+	require.Equal(t, "TaskName1", payload.Task.Name)
+
+	tests := []struct {
+		name     string
+		id       int64
+		wantCode int
+	}{
+		{name: "NotDead", id: 1, wantCode: http.StatusConflict},
+		{name: "NotFound", id: 2, wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/webhook-dead-letters/%d/retry", tt.id)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			server.Router.ServeHTTP(recorder, request)
+			require.Equal(t, tt.wantCode, recorder.Code)
+		})
+	}
+}
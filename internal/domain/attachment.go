@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Attachment struct {
+	ID          int64     `json:"id"`
+	TaskID      int64     `json:"taskId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+	BlobKey     string    `json:"-"`
+}
+
+type AttachmentUriParameter struct {
+	TaskID int64 `uri:"task_id" binding:"required,min=1"`
+	ID     int64 `uri:"attachment_id" binding:"required,min=1"`
+}
+
+type UploadAttachmentRequest struct {
+	FileName    string
+	ContentType string
+	Content     io.Reader
+}
+
+type UploadAttachmentResponse struct {
+	Result Attachment `json:"result"`
+}
+
+type ListAttachmentResponse struct {
+	Result []Attachment `json:"result"`
+}
+
+type AttachmentUseCase interface {
+	List(ctx context.Context, taskID int64) (ListAttachmentResponse, error)
+	Upload(ctx context.Context, taskID int64, req UploadAttachmentRequest) (UploadAttachmentResponse, error)
+	Open(ctx context.Context, taskID int64, id int64) (Attachment, io.ReadSeekCloser, error)
+	Delete(ctx context.Context, taskID int64, id int64) error
+}
+
+type AttachmentRepository interface {
+	List(ctx context.Context, taskID int64) ([]Attachment, error)
+	Create(ctx context.Context, attachment Attachment) (Attachment, error)
+	Get(ctx context.Context, id int64) (Attachment, error)
+	Delete(ctx context.Context, id int64) error
+	// DeleteByTask removes every attachment of the task.
+	DeleteByTask(ctx context.Context, taskID int64) error
+}
+
+// BlobStore keeps the attachment content. The local filesystem implementation lives in
+// repository/localfs, any other storage (e.g. S3) only needs to implement this interface.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every blob whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type ChecklistItem struct {
+	ID      int64  `json:"id"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+type ChecklistItemUriParameter struct {
+	TaskID int64 `uri:"task_id" binding:"required,min=1"`
+	ID     int64 `uri:"item_id" binding:"required,min=1"`
+}
+
+type AddChecklistItemRequest struct {
+	Text string `json:"text" binding:"required,max=500"`
+}
+
+type ReorderChecklistRequest struct {
+	ItemIDs []int64 `json:"itemIds" binding:"required"`
+}
+
+// ChecklistResponse carries the task after a checklist change, Before is the task as it was
+// before the change.
+type ChecklistResponse struct {
+	Result Task `json:"result"`
+	Before Task `json:"-"`
+}
+
+type ChecklistUseCase interface {
+	AddItem(ctx context.Context, taskID int64, req AddChecklistItemRequest) (ChecklistResponse, error)
+	Reorder(ctx context.Context, taskID int64, req ReorderChecklistRequest) (ChecklistResponse, error)
+	ToggleItem(ctx context.Context, taskID int64, id int64) (ChecklistResponse, error)
+	RemoveItem(ctx context.Context, taskID int64, id int64) (ChecklistResponse, error)
+}
+
+// Progress returns the percentage of checked checklist items, or nil if the task has no checklist.
+func (t Task) Progress() *int {
+	if len(t.Checklist) == 0 {
+		return nil
+	}
+	checked := 0
+	for _, item := range t.Checklist {
+		if item.Checked {
+			checked++
+		}
+	}
+	progress := checked * 100 / len(t.Checklist)
+	return &progress
+}
+
+// MarshalJSON adds the computed checklist progress to the task representation.
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+	return json.Marshal(struct {
+		task
+		Progress *int `json:"progress,omitempty"`
+	}{
+		task:     task(t),
+		Progress: t.Progress(),
+	})
+}
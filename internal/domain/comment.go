@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type CommentPolicy string
+
+// CommentPolicyRetain keeps the comments of a deleted task, CommentPolicyDelete removes them with the task.
+var CommentPolicyRetain CommentPolicy = "retain"
+var CommentPolicyDelete CommentPolicy = "delete"
+
+const DefaultCommentPageSize = 20
+
+type Comment struct {
+	ID        int64     `json:"id"`
+	TaskID    int64     `json:"taskId"`
+	AuthorID  int64     `json:"authorId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CommentUriParameter struct {
+	TaskID int64 `uri:"task_id" binding:"required,min=1"`
+	ID     int64 `uri:"comment_id" binding:"required,min=1"`
+}
+
+type ListCommentRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+type ListCommentResponse struct {
+	Result   []Comment `json:"result"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"pageSize"`
+	Total    int       `json:"total"`
+}
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=4000"`
+}
+
+type CreateCommentResponse struct {
+	Result Comment `json:"result"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=4000"`
+}
+
+type UpdateCommentResponse struct {
+	Result Comment `json:"result"`
+}
+
+type CommentUseCase interface {
+	List(ctx context.Context, taskID int64, req ListCommentRequest) (ListCommentResponse, error)
+	Create(ctx context.Context, taskID int64, authorID int64, req CreateCommentRequest) (CreateCommentResponse, error)
+	Update(ctx context.Context, taskID int64, id int64, authorID int64, req UpdateCommentRequest) (UpdateCommentResponse, error)
+	Delete(ctx context.Context, taskID int64, id int64, authorID int64) error
+}
+
+type CommentRepository interface {
+	List(ctx context.Context, taskID int64, offset int, limit int) ([]Comment, int, error)
+	Create(ctx context.Context, taskID int64, authorID int64, body string) (Comment, error)
+	Update(ctx context.Context, id int64, body string) (Comment, error)
+	Delete(ctx context.Context, id int64) error
+	Get(ctx context.Context, id int64) (Comment, error)
+	DeleteByTask(ctx context.Context, taskID int64) error
+}
@@ -1,17 +1,83 @@
 package domain
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
+const (
+	TaskRepositoryInMemory    = "inmemory"
+	TaskRepositoryEventSource = "eventsource"
+)
+
 type AppConfig struct {
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	ServerAddress      string        `mapstructure:"SERVER_ADDRESS"`
+	GrpcAddress        string        `mapstructure:"GRPC_ADDRESS"`
+	CommentPolicy      CommentPolicy `mapstructure:"COMMENT_POLICY"`
+	AttachmentDir      string        `mapstructure:"ATTACHMENT_DIR"`
+	MaxAttachmentSize  int64         `mapstructure:"MAX_ATTACHMENT_SIZE"`
+	TrashRetention     time.Duration `mapstructure:"TRASH_RETENTION"`
+	PurgeInterval      time.Duration `mapstructure:"PURGE_INTERVAL"`
+	AutoArchiveAfter   time.Duration `mapstructure:"AUTO_ARCHIVE_AFTER"`
+	ArchiveInterval    time.Duration `mapstructure:"ARCHIVE_INTERVAL"`
+	TaskRepository     string        `mapstructure:"TASK_REPOSITORY"`
+	EventStoreDir      string        `mapstructure:"EVENT_STORE_DIR"`
+	SnapshotEvery      int           `mapstructure:"SNAPSHOT_EVERY"`
+	VersionRetention   time.Duration `mapstructure:"VERSION_RETENTION"`
+	UndoWindow         time.Duration `mapstructure:"UNDO_WINDOW"`
+	ReminderDir        string        `mapstructure:"REMINDER_DIR"`
+	ReminderInterval   time.Duration `mapstructure:"REMINDER_INTERVAL"`
+	ReminderWebhook    string        `mapstructure:"REMINDER_WEBHOOK_URL"`
+	ReminderAttempts   int           `mapstructure:"REMINDER_MAX_ATTEMPTS"`
+	WebhookInterval    time.Duration `mapstructure:"WEBHOOK_INTERVAL"`
+	WebhookMaxAttempts int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoff     time.Duration `mapstructure:"WEBHOOK_BACKOFF"`
+	TaskEventBuffer    int           `mapstructure:"TASK_EVENT_BUFFER"`
+	TaskEventHeartbeat time.Duration `mapstructure:"TASK_EVENT_HEARTBEAT"`
+	SocketPingInterval time.Duration `mapstructure:"WEBSOCKET_PING_INTERVAL"`
+	SocketSendBuffer   int           `mapstructure:"WEBSOCKET_SEND_BUFFER"`
+	OutboxInterval     time.Duration `mapstructure:"OUTBOX_INTERVAL"`
+	OutboxBatchSize    int           `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxPublishURL   string        `mapstructure:"OUTBOX_PUBLISH_URL"`
+	GraphQLMaxDepth    int           `mapstructure:"GRAPHQL_MAX_DEPTH"`
+	GraphQLComplexity  int           `mapstructure:"GRAPHQL_MAX_COMPLEXITY"`
 }
 
 func LoadConfig(path string, configName string) (config AppConfig, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName(configName)
 	viper.SetConfigType("env")
+	viper.SetDefault("GRPC_ADDRESS", "0.0.0.0:9090")
+	viper.SetDefault("COMMENT_POLICY", string(CommentPolicyRetain))
+	viper.SetDefault("ATTACHMENT_DIR", "data/attachments")
+	viper.SetDefault("MAX_ATTACHMENT_SIZE", 10<<20)
+	viper.SetDefault("TRASH_RETENTION", "720h")
+	viper.SetDefault("PURGE_INTERVAL", "1h")
+	viper.SetDefault("AUTO_ARCHIVE_AFTER", "168h")
+	viper.SetDefault("ARCHIVE_INTERVAL", "1h")
+	viper.SetDefault("TASK_REPOSITORY", TaskRepositoryInMemory)
+	viper.SetDefault("EVENT_STORE_DIR", "data/events")
+	viper.SetDefault("SNAPSHOT_EVERY", 100)
+	viper.SetDefault("VERSION_RETENTION", "720h")
+	viper.SetDefault("UNDO_WINDOW", "1m")
+	viper.SetDefault("REMINDER_DIR", "data/reminders")
+	viper.SetDefault("REMINDER_INTERVAL", "30s")
+	viper.SetDefault("REMINDER_WEBHOOK_URL", "")
+	viper.SetDefault("REMINDER_MAX_ATTEMPTS", 10)
+	viper.SetDefault("WEBHOOK_INTERVAL", "5s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_BACKOFF", "30s")
+	viper.SetDefault("TASK_EVENT_BUFFER", 256)
+	viper.SetDefault("TASK_EVENT_HEARTBEAT", "15s")
+	viper.SetDefault("WEBSOCKET_PING_INTERVAL", "30s")
+	viper.SetDefault("WEBSOCKET_SEND_BUFFER", 64)
+	viper.SetDefault("OUTBOX_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_PUBLISH_URL", "")
+	viper.SetDefault("GRAPHQL_MAX_DEPTH", 15)
+	viper.SetDefault("GRAPHQL_MAX_COMPLEXITY", 2000)
 
 	viper.AutomaticEnv()
 
@@ -21,5 +87,11 @@ func LoadConfig(path string, configName string) (config AppConfig, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+	if config.CommentPolicy != CommentPolicyRetain && config.CommentPolicy != CommentPolicyDelete {
+		err = fmt.Errorf("unknown comment policy %q", config.CommentPolicy)
+	}
 	return
 }
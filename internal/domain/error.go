@@ -14,10 +14,31 @@ var (
 	ErrDataNotFound      = NewErrorResponse(fmt.Sprintf("ERR_%s_0007", serviceCode), "data not found")
 	ErrWrongID           = NewErrorResponse(fmt.Sprintf("ERR_%s_0008", serviceCode), "wrong task ID")
 	ErrTaskNameNotMatch  = NewErrorResponse(fmt.Sprintf("ERR_%s_0009", serviceCode), "task name not match")
+	ErrUserNotFound      = NewErrorResponse(fmt.Sprintf("ERR_%s_0010", serviceCode), "user not found")
+	ErrNotCommentAuthor  = NewErrorResponse(fmt.Sprintf("ERR_%s_0011", serviceCode), "comment author not match")
+	ErrAttachmentTooBig  = NewErrorResponse(fmt.Sprintf("ERR_%s_0012", serviceCode), "attachment too large")
+	ErrChecklistNotFound = NewErrorResponse(fmt.Sprintf("ERR_%s_0013", serviceCode), "checklist item not found")
+	ErrChecklistOrder    = NewErrorResponse(fmt.Sprintf("ERR_%s_0014", serviceCode), "checklist order does not match items")
+	ErrTaskNotDeleted    = NewErrorResponse(fmt.Sprintf("ERR_%s_0015", serviceCode), "task is not in trash")
+	ErrTaskArchived      = NewErrorResponse(fmt.Sprintf("ERR_%s_0016", serviceCode), "task is already archived")
+	ErrTaskNotArchived   = NewErrorResponse(fmt.Sprintf("ERR_%s_0017", serviceCode), "task is not archived")
+	ErrUndoNotFound      = NewErrorResponse(fmt.Sprintf("ERR_%s_0018", serviceCode), "undo token not found")
+	ErrUndoExpired       = NewErrorResponse(fmt.Sprintf("ERR_%s_0019", serviceCode), "undo token expired")
+	ErrUndoConflict      = NewErrorResponse(fmt.Sprintf("ERR_%s_0020", serviceCode), "task changed since the operation")
+	ErrTimerRunning      = NewErrorResponse(fmt.Sprintf("ERR_%s_0021", serviceCode), "a timer is already running")
+	ErrTimerNotRunning   = NewErrorResponse(fmt.Sprintf("ERR_%s_0022", serviceCode), "no running timer on this task")
+	ErrTaskNoDueDate     = NewErrorResponse(fmt.Sprintf("ERR_%s_0023", serviceCode), "task has no due date")
+	ErrDeliveryNotDead   = NewErrorResponse(fmt.Sprintf("ERR_%s_0024", serviceCode), "delivery is not in the dead-letter list")
+	ErrSyncTokenExpired  = NewErrorResponse(fmt.Sprintf("ERR_%s_0025", serviceCode), "sync token is no longer valid")
+	ErrQueryTooDeep      = NewErrorResponse(fmt.Sprintf("ERR_%s_0026", serviceCode), "query is nested too deeply")
+	ErrQueryTooComplex   = NewErrorResponse(fmt.Sprintf("ERR_%s_0027", serviceCode), "query is too complex")
+	ErrHistoryExpired    = NewErrorResponse(fmt.Sprintf("ERR_%s_0028", serviceCode), "task history before asOf is no longer kept")
 )
 
 type ErrorResponse interface {
 	Error() string
+	// Code is the ERR_TASK code of the error.
+	Code() string
 }
 
 type errorResponse struct {
@@ -29,6 +50,10 @@ func (e *errorResponse) Error() string {
 	return e.Message
 }
 
+func (e *errorResponse) Code() string {
+	return e.ErrorCode
+}
+
 func NewErrorResponse(errorCode string, message string) *errorResponse {
 	return &errorResponse{
 		ErrorCode: errorCode,
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+)
+
+// DomainEvent is a change of a task published by the task use case once the repository
+// accepted it. The concrete events are TaskCreated, TaskUpdated, TaskDeleted and
+// TaskPurged.
+type DomainEvent interface {
+	TaskID() int64
+}
+
+// TaskCreated is published for a new task and for a task restored from the trash.
+type TaskCreated struct {
+	Task     Task
+	Restored bool
+}
+
+func (e TaskCreated) TaskID() int64 {
+	return e.Task.ID
+}
+
+// TaskUpdated is published for every change of a live task, Before is the task right
+// before the change.
+type TaskUpdated struct {
+	Before Task
+	Task   Task
+}
+
+func (e TaskUpdated) TaskID() int64 {
+	return e.Task.ID
+}
+
+// TaskDeleted is published when a task is moved to the trash, Task is the task right before.
+type TaskDeleted struct {
+	Task Task
+}
+
+func (e TaskDeleted) TaskID() int64 {
+	return e.Task.ID
+}
+
+// TaskPurged is published when a task is removed from the trash for good, the data
+// hanging off the task can go with it.
+type TaskPurged struct {
+	ID int64
+}
+
+func (e TaskPurged) TaskID() int64 {
+	return e.ID
+}
+
+// EventHandler reacts to a domain event, a returned error is logged by the bus.
+type EventHandler func(ctx context.Context, event DomainEvent) error
+
+// EventBus delivers the events of a task to every subscriber in the order they were
+// published. A panicking or failing subscriber neither stops the other subscribers nor
+// fails the publisher.
+type EventBus interface {
+	Publish(ctx context.Context, event DomainEvent)
+	// Subscribe runs handler in the publishing goroutine, Publish returns after it.
+	Subscribe(name string, handler EventHandler) (unsubscribe func())
+	// SubscribeAsync runs handler in the background with the values of the publishing
+	// context but not its cancellation. Unsubscribing waits for the queued events.
+	SubscribeAsync(name string, handler EventHandler) (unsubscribe func())
+}
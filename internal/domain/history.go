@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type HistoryAction string
+
+const (
+	HistoryActionCreate  HistoryAction = "create"
+	HistoryActionUpdate  HistoryAction = "update"
+	HistoryActionDelete  HistoryAction = "delete"
+	HistoryActionRestore HistoryAction = "restore"
+)
+
+// ActorContextKey is the context key of the ID of the user performing a request. It is a
+// plain string so gin.Context, which only looks up string keys, can carry it as well.
+const ActorContextKey = "actorId"
+
+// ContextWithActor returns a copy of ctx that carries the acting user's ID.
+func ContextWithActor(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, ActorContextKey, userID)
+}
+
+// ActorFromContext returns the acting user's ID, ok is false for anonymous requests.
+func ActorFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(ActorContextKey).(int64)
+	return id, ok
+}
+
+// FieldChange is the change of a single task field, From and To hold the JSON values.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// HistoryEntry is an immutable record of a change made to a task.
+type HistoryEntry struct {
+	ID        int64         `json:"id"`
+	TaskID    int64         `json:"taskId"`
+	Action    HistoryAction `json:"action"`
+	ActorID   *int64        `json:"actorId,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+type ListHistoryResponse struct {
+	Result []HistoryEntry `json:"result"`
+}
+
+type HistoryUseCase interface {
+	List(ctx context.Context, taskID int64) (ListHistoryResponse, error)
+}
+
+type HistoryRepository interface {
+	// Append stores the entry and returns it with its ID and Timestamp set.
+	Append(ctx context.Context, entry HistoryEntry) (HistoryEntry, error)
+	List(ctx context.Context, taskID int64) ([]HistoryEntry, error)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// OutboxMessageIDHeader carries the ID of a message published over HTTP.
+const OutboxMessageIDHeader = "X-Message-ID"
+
+// OutboxMessage is a task change recorded by the task repository in the same write as the
+// change itself, so it survives whatever happens to the process afterwards. A message may
+// be published more than once; ID stays the same every time, so consumers drop the
+// duplicates by it. Before is the task right before the change, nil for a new task.
+type OutboxMessage struct {
+	ID         string        `json:"id"`
+	Seq        int64         `json:"seq"`
+	Type       TaskEventType `json:"type"`
+	OccurredAt time.Time     `json:"occurredAt"`
+	Task       Task          `json:"task"`
+	Before     *Task         `json:"before,omitempty"`
+}
+
+// NewOutboxMessage describes the change of a task from before to after, a nil task meaning
+// it did not exist. ok is false for a change outside the trash-aware life of a task, such
+// as reranking a deleted task or purging one, and for a change of the rank alone.
+func NewOutboxMessage(seq int64, at time.Time, before *Task, after *Task) (OutboxMessage, bool) {
+	if after == nil {
+		return OutboxMessage{}, false
+	}
+	wasLive := before != nil && before.DeletedAt == nil
+	isLive := after.DeletedAt == nil
+	var eventType TaskEventType
+	switch {
+	case wasLive && isLive && rankOnly(*before, *after):
+		return OutboxMessage{}, false
+	case wasLive && isLive:
+		eventType = TaskEventUpdated
+	case isLive:
+		eventType = TaskEventCreated
+	case wasLive:
+		eventType = TaskEventDeleted
+	default:
+		return OutboxMessage{}, false
+	}
+	rtn := OutboxMessage{
+		ID:         fmt.Sprintf("%d-%d", seq, after.ID),
+		Seq:        seq,
+		Type:       eventType,
+		OccurredAt: at,
+		Task:       *after,
+	}
+	if before != nil {
+		b := *before
+		rtn.Before = &b
+	}
+	return rtn, true
+}
+
+// rankOnly reports whether before and after differ in their rank alone.
+func rankOnly(before Task, after Task) bool {
+	before.Rank = after.Rank
+	return reflect.DeepEqual(before, after)
+}
+
+// OutboxRepository hands out the recorded messages for publishing.
+type OutboxRepository interface {
+	// PendingOutbox returns up to limit undelivered messages, oldest first. It returns more
+	// rather than split the messages sharing a Seq.
+	PendingOutbox(ctx context.Context, limit int) ([]OutboxMessage, error)
+	// AckOutbox marks every message up to and including seq as delivered.
+	AckOutbox(ctx context.Context, seq int64) error
+}
+
+// Publisher delivers outbox messages outside the service.
+type Publisher interface {
+	Publish(ctx context.Context, message OutboxMessage) error
+}
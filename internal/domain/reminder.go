@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Reminder fires at At, or BeforeDueMinutes before the due date of its task. A task gets
+// its due date when it is created. FailedAt is set once the reminder ran out of attempts,
+// it is not retried afterwards. TaskCreatedAt tells the task apart from a later one with
+// the same ID, the in-memory repository hands out the IDs again after a restart.
+type Reminder struct {
+	ID               int64      `json:"id"`
+	TaskID           int64      `json:"taskId"`
+	TaskCreatedAt    time.Time  `json:"taskCreatedAt"`
+	At               *time.Time `json:"at,omitempty"`
+	BeforeDueMinutes *int       `json:"beforeDueMinutes,omitempty"`
+	FiredAt          *time.Time `json:"firedAt,omitempty"`
+	FailedAt         *time.Time `json:"failedAt,omitempty"`
+	Attempts         int        `json:"attempts,omitempty"`
+	LastError        string     `json:"lastError,omitempty"`
+}
+
+// FireTime returns when the reminder is due for the task, ok is false if it depends on a
+// due date the task does not have.
+func (r Reminder) FireTime(task Task) (at time.Time, ok bool) {
+	if r.At != nil {
+		return *r.At, true
+	}
+	if r.BeforeDueMinutes == nil || task.DueAt == nil {
+		return time.Time{}, false
+	}
+	return task.DueAt.Add(-time.Duration(*r.BeforeDueMinutes) * time.Minute), true
+}
+
+type ReminderUriParameter struct {
+	TaskID int64 `uri:"task_id" binding:"required,min=1"`
+	ID     int64 `uri:"reminder_id" binding:"required,min=1"`
+}
+
+// CreateReminderRequest sets exactly one of At and BeforeDueMinutes.
+type CreateReminderRequest struct {
+	At               *time.Time `json:"at"`
+	BeforeDueMinutes *int       `json:"beforeDueMinutes" binding:"omitempty,min=0,max=525600"`
+}
+
+type ReminderResponse struct {
+	Result Reminder `json:"result"`
+}
+
+type ListReminderResponse struct {
+	Result []Reminder `json:"result"`
+}
+
+// Notification is what a Notifier delivers when a reminder fires.
+type Notification struct {
+	Reminder Reminder  `json:"reminder"`
+	Task     Task      `json:"task"`
+	FireAt   time.Time `json:"fireAt"`
+}
+
+// Notifier delivers fired reminders. A reminder whose delivery fails is retried on the
+// next runs of the scheduler until it runs out of attempts.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+type ReminderUseCase interface {
+	Create(ctx context.Context, taskID int64, req CreateReminderRequest) (ReminderResponse, error)
+	List(ctx context.Context, taskID int64) (ListReminderResponse, error)
+	Delete(ctx context.Context, taskID int64, id int64) error
+	// FireDue notifies every pending reminder that is due and returns how many were delivered.
+	FireDue(ctx context.Context) (int, error)
+}
+
+type ReminderRepository interface {
+	Create(ctx context.Context, reminder Reminder) (Reminder, error)
+	Get(ctx context.Context, id int64) (Reminder, error)
+	ListByTask(ctx context.Context, taskID int64) ([]Reminder, error)
+	// ListPending returns the reminders that have neither fired nor failed yet.
+	ListPending(ctx context.Context) ([]Reminder, error)
+	// UpdateMany stores all the reminders at once, the ones deleted meanwhile are skipped.
+	UpdateMany(ctx context.Context, reminders []Reminder) error
+	Delete(ctx context.Context, id int64) error
+	// DeleteByTask removes every reminder of the task.
+	DeleteByTask(ctx context.Context, taskID int64) error
+}
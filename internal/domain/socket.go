@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"encoding/json"
+)
+
+type SocketMessageType string
+
+// Messages sent by a WebSocket client.
+const (
+	SocketSubscribe   SocketMessageType = "subscribe"
+	SocketUnsubscribe SocketMessageType = "unsubscribe"
+	SocketPing        SocketMessageType = "ping"
+	SocketCreate      SocketMessageType = "create"
+	SocketUpdate      SocketMessageType = "update"
+	SocketDelete      SocketMessageType = "delete"
+	SocketAssign      SocketMessageType = "assign"
+	SocketUnassign    SocketMessageType = "unassign"
+	SocketMove        SocketMessageType = "move"
+)
+
+// Messages sent by the server.
+const (
+	SocketAck   SocketMessageType = "ack"
+	SocketError SocketMessageType = "error"
+	SocketEvent SocketMessageType = "event"
+)
+
+// SocketRequest is a message of a WebSocket client. ID is chosen by the client and echoed
+// in the ack or error answering the message. Subscribe and unsubscribe take TaskIDs, an
+// empty list meaning every task, the mutations take TaskID and the Payload of the matching
+// HTTP request.
+type SocketRequest struct {
+	ID      string            `json:"id"`
+	Type    SocketMessageType `json:"type"`
+	TaskIDs []int64           `json:"taskIds,omitempty"`
+	TaskID  int64             `json:"taskId,omitempty"`
+	Payload json.RawMessage   `json:"payload,omitempty"`
+}
+
+// SocketResponse is a message of the server, either the ack or error of a request or the
+// event of a change made by another client.
+type SocketResponse struct {
+	ID     string            `json:"id,omitempty"`
+	Type   SocketMessageType `json:"type"`
+	Result interface{}       `json:"result,omitempty"`
+	Error  ErrorResponse     `json:"error,omitempty"`
+	Event  *TaskEvent        `json:"event,omitempty"`
+}
+
+// SocketSubscription is the ack result of subscribe and unsubscribe.
+type SocketSubscription struct {
+	All     bool    `json:"all"`
+	TaskIDs []int64 `json:"taskIds"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+)
+
+// TaskChanges lists the tasks changed after a sequence number of the change feed. A task
+// that did not exist or sat in the trash at that point is Created, the other ones are
+// Updated, and Deleted holds the IDs of the tasks trashed or purged since. Tasks created
+// and deleted in between are left out. Seq is the sequence number of the last change.
+// Epoch names the change feed, its sequence numbers start over when it does.
+type TaskChanges struct {
+	Created []Task
+	Updated []Task
+	Deleted []int64
+	Seq     int64
+	Epoch   string
+}
+
+type SyncRequest struct {
+	Since string `form:"since"`
+}
+
+// TaskTombstone stands for a task the client has to drop.
+type TaskTombstone struct {
+	ID int64 `json:"id"`
+}
+
+// SyncResponse holds the changes since the token of the request, an empty token asking
+// for every task. Token is passed as since by the next sync.
+type SyncResponse struct {
+	Created []Task          `json:"created"`
+	Updated []Task          `json:"updated"`
+	Deleted []TaskTombstone `json:"deleted"`
+	Token   string          `json:"token"`
+}
+
+type SyncUseCase interface {
+	Sync(ctx context.Context, req SyncRequest) (SyncResponse, error)
+}
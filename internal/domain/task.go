@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type Status int64
@@ -9,14 +10,45 @@ type Status int64
 var StatusIncomplete Status = 0
 var StatusComplete Status = 1
 
+var RenderHTML = "html"
+
+// EstimateUnit is the unit an estimate is expressed in.
+type EstimateUnit string
+
+const (
+	EstimateUnitPoints EstimateUnit = "points"
+	EstimateUnitHours  EstimateUnit = "hours"
+)
+
+// Estimate is the expected effort of a task, in story points or in hours.
+type Estimate struct {
+	Value float64      `json:"value" binding:"gt=0,max=10000"`
+	Unit  EstimateUnit `json:"unit" binding:"required,oneof=points hours"`
+}
+
 type Task struct {
-	ID     int64  `json:"id"`
-	Status Status `json:"status"`
-	Name   string `json:"name"`
+	ID          int64           `json:"id"`
+	Status      Status          `json:"status"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	AssigneeID  *int64          `json:"assigneeId,omitempty"`
+	Estimate    *Estimate       `json:"estimate,omitempty"`
+	DueAt       *time.Time      `json:"dueAt,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Checklist   []ChecklistItem `json:"checklist,omitempty"`
+	Rank        string          `json:"rank"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+	ArchivedAt  *time.Time      `json:"archivedAt,omitempty"`
+	DeletedAt   *time.Time      `json:"deletedAt,omitempty"`
 }
 
 type CreateTaskRequest struct {
-	Name string `json:"name" binding:"required" `
+	Name        string     `json:"name" binding:"required" `
+	Description string     `json:"description" binding:"max=10000"`
+	Estimate    *Estimate  `json:"estimate"`
+	DueAt       *time.Time `json:"dueAt"`
+	Tags        []string   `json:"tags" binding:"max=20,dive,required,max=50"`
 }
 
 type CreateTaskResponse struct {
@@ -28,13 +60,51 @@ type UpdateTaskUriParameter struct {
 }
 
 type UpdateTaskRequest struct {
-	ID     int64   `json:"id" binding:"required"`
-	Status *Status `json:"status" binding:"required,min=0,max=1"`
-	Name   string  `json:"name" binding:"required"`
+	ID          int64   `json:"id" binding:"required"`
+	Status      *Status `json:"status" binding:"required,min=0,max=1"`
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description" binding:"omitempty,max=10000"`
 }
 
 type UpdateTaskResponse struct {
-	Result Task `json:"result"`
+	Result    Task   `json:"result"`
+	UndoToken string `json:"undoToken,omitempty"`
+	// Before is the task right before the update.
+	Before Task `json:"-"`
+}
+
+type GetTaskRequest struct {
+	Render string    `form:"render" binding:"omitempty,oneof=html"`
+	AsOf   time.Time `form:"asOf" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type GetTaskResponse struct {
+	Result          Task   `json:"result"`
+	DescriptionHTML string `json:"descriptionHtml,omitempty"`
+}
+
+// ListTaskRequest lists the tasks as they are now, or as they were at AsOf when it is set.
+type ListTaskRequest struct {
+	IncludeDeleted  bool      `form:"includeDeleted"`
+	IncludeArchived bool      `form:"includeArchived"`
+	AsOf            time.Time `form:"asOf" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// TaskFilter narrows the tasks returned by TaskRepository.List, tasks in the trash and
+// archived tasks are left out unless IncludeDeleted or IncludeArchived is set.
+type TaskFilter struct {
+	IncludeDeleted  bool
+	IncludeArchived bool
+}
+
+func (f TaskFilter) Match(task Task) bool {
+	if task.DeletedAt != nil && !f.IncludeDeleted {
+		return false
+	}
+	if task.ArchivedAt != nil && !f.IncludeArchived {
+		return false
+	}
+	return true
 }
 
 type ListTaskResponse struct {
@@ -45,18 +115,110 @@ type DeleteTaskRequest struct {
 	ID int64 `uri:"task_id" binding:"required,min=1"`
 }
 
+type DeleteTaskResponse struct {
+	UndoToken string `json:"undoToken,omitempty"`
+	// Before is the task right before it was moved to the trash.
+	Before Task `json:"-"`
+}
+
+type TaskUriParameter struct {
+	ID int64 `uri:"task_id" binding:"required,min=1"`
+}
+
+type AssignTaskRequest struct {
+	AssigneeID int64 `json:"assigneeId" binding:"required,min=1"`
+}
+
+type AssignTaskResponse struct {
+	Result Task `json:"result"`
+}
+
+type EstimateTaskResponse struct {
+	Result Task `json:"result"`
+}
+
+// MoveTaskRequest places a task right before or right after another task, exactly one of
+// BeforeID and AfterID must be set.
+type MoveTaskRequest struct {
+	BeforeID int64 `json:"beforeId" binding:"omitempty,min=1"`
+	AfterID  int64 `json:"afterId" binding:"omitempty,min=1"`
+}
+
+type MoveTaskResponse struct {
+	Result Task `json:"result"`
+}
+
+type RestoreTaskResponse struct {
+	Result Task `json:"result"`
+}
+
+type ArchiveTaskResponse struct {
+	Result Task `json:"result"`
+}
+
 type TaskUseCase interface {
-	List(ctx context.Context) (ListTaskResponse, error)
+	List(ctx context.Context, req ListTaskRequest) (ListTaskResponse, error)
 	Create(ctx context.Context, req CreateTaskRequest) (CreateTaskResponse, error)
+	// CreateMany creates all the tasks, ranked in the given order, or none of them.
+	CreateMany(ctx context.Context, tasks []Task) ([]Task, error)
 	Update(ctx context.Context, req UpdateTaskRequest) (UpdateTaskResponse, error)
-	Delete(ctx context.Context, id int64) error
+	// Revert puts the status, completion time and description of the task back to those of
+	// to while the task is still in the expected state, ErrUndoConflict is returned
+	// otherwise. Ranks are not compared.
+	Revert(ctx context.Context, expected Task, to Task) (UpdateTaskResponse, error)
+	Delete(ctx context.Context, id int64) (DeleteTaskResponse, error)
 	Get(ctx context.Context, id int64) (Task, error)
+	// GetAsOf returns the task as it was at the given time.
+	GetAsOf(ctx context.Context, id int64, at time.Time) (Task, error)
+	Assign(ctx context.Context, id int64, assigneeID int64) (AssignTaskResponse, error)
+	Unassign(ctx context.Context, id int64) (AssignTaskResponse, error)
+	ListByAssignee(ctx context.Context, assigneeID int64) (ListTaskResponse, error)
+	SetEstimate(ctx context.Context, id int64, estimate Estimate) (EstimateTaskResponse, error)
+	ClearEstimate(ctx context.Context, id int64) (EstimateTaskResponse, error)
+	Move(ctx context.Context, id int64, req MoveTaskRequest) (MoveTaskResponse, error)
+	Restore(ctx context.Context, id int64) (RestoreTaskResponse, error)
+	// Purge permanently removes the tasks moved to the trash before the given time.
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+	Archive(ctx context.Context, id int64) (ArchiveTaskResponse, error)
+	Unarchive(ctx context.Context, id int64) (ArchiveTaskResponse, error)
+	// AutoArchive archives the tasks completed before the given time.
+	AutoArchive(ctx context.Context, completedBefore time.Time) (int, error)
+	AddChecklistItem(ctx context.Context, id int64, text string) (ChecklistResponse, error)
+	ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (ChecklistResponse, error)
+	ToggleChecklistItem(ctx context.Context, id int64, itemID int64) (ChecklistResponse, error)
+	RemoveChecklistItem(ctx context.Context, id int64, itemID int64) (ChecklistResponse, error)
 }
 
 type TaskRepository interface {
-	List(ctx context.Context) ([]Task, error)
-	Create(ctx context.Context, name string) (Task, error)
-	Update(ctx context.Context, id int64, status Status) (Task, error)
+	List(ctx context.Context, filter TaskFilter) ([]Task, error)
+	// ListAsOf and GetAsOf read the tasks as they were at the given time.
+	ListAsOf(ctx context.Context, filter TaskFilter, at time.Time) ([]Task, error)
+	GetAsOf(ctx context.Context, id int64, at time.Time) (Task, error)
+	Create(ctx context.Context, task Task) (Task, error)
+	// CreateMany creates all the tasks, ranked in the given order after the existing ones,
+	// or none of them.
+	CreateMany(ctx context.Context, tasks []Task) ([]Task, error)
+	Update(ctx context.Context, id int64, status Status, description string) (Task, error)
+	// Revert sets the status, completion time and description of the task to those of to.
+	Revert(ctx context.Context, id int64, to Task) (Task, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (Task, error)
+	Purge(ctx context.Context, deletedBefore time.Time) ([]int64, error)
+	Archive(ctx context.Context, id int64) (Task, error)
+	Unarchive(ctx context.Context, id int64) (Task, error)
+	ArchiveCompleted(ctx context.Context, completedBefore time.Time) ([]int64, error)
 	Get(ctx context.Context, id int64) (Task, error)
+	Assign(ctx context.Context, id int64, assigneeID *int64) (Task, error)
+	ListByAssignee(ctx context.Context, assigneeID int64) ([]Task, error)
+	// Estimate sets the estimate of the task, a nil estimate clears it.
+	Estimate(ctx context.Context, id int64, estimate *Estimate) (Task, error)
+	Move(ctx context.Context, id int64, beforeID int64, afterID int64) (Task, error)
+	AddChecklistItem(ctx context.Context, id int64, text string) (Task, error)
+	ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (Task, error)
+	ToggleChecklistItem(ctx context.Context, id int64, itemID int64) (Task, error)
+	RemoveChecklistItem(ctx context.Context, id int64, itemID int64) (Task, error)
+	// Changes reads the change feed after the sequence number since.
+	Changes(ctx context.Context, since int64) (TaskChanges, error)
+	// Every change of a task records an outbox message in the same write.
+	OutboxRepository
 }
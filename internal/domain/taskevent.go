@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TaskEventType string
+
+const (
+	TaskEventCreated TaskEventType = "task.created"
+	TaskEventUpdated TaskEventType = "task.updated"
+	TaskEventDeleted TaskEventType = "task.deleted"
+)
+
+// TaskEventReset is sent to a stream subscriber whose Last-Event-ID is no longer in the
+// replay buffer or was handed out before a restart, the client has to reload the tasks.
+const TaskEventReset = "reset"
+
+// LastEventIDHeader is sent back by an EventSource when it reconnects.
+const LastEventIDHeader = "Last-Event-ID"
+
+// OriginContextKey is the context key of the connection a change comes from, so the change
+// is not echoed back to the client that made it.
+const OriginContextKey = "originId"
+
+// ContextWithOrigin returns a copy of ctx that carries the connection ID.
+func ContextWithOrigin(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, OriginContextKey, origin)
+}
+
+// OriginFromContext returns the connection ID, it is empty for plain requests.
+func OriginFromContext(ctx context.Context) string {
+	origin, _ := ctx.Value(OriginContextKey).(string)
+	return origin
+}
+
+// TaskEvent is one change of a task. The IDs increase by one from 1 within an epoch, a
+// new epoch starts every time the process does.
+type TaskEvent struct {
+	ID         int64         `json:"id"`
+	Epoch      string        `json:"epoch"`
+	Type       TaskEventType `json:"type"`
+	OccurredAt time.Time     `json:"occurredAt"`
+	Task       Task          `json:"task"`
+	Origin     string        `json:"-"`
+}
+
+// TaskEventSubscription holds the buffered events after the requested one and the channel
+// of the later events. Missed is set instead of Replay when some events after the
+// requested one already left the buffer or belongs to another epoch, Epoch and LastID
+// are then the position to resume from once the client reloaded. Events is closed when
+// the subscriber falls too far behind.
+type TaskEventSubscription struct {
+	Replay []TaskEvent
+	Missed bool
+	Epoch  string
+	LastID int64
+	Events <-chan TaskEvent
+}
+
+type TaskEventBroker interface {
+	// Publish records the origin of ctx with the event.
+	Publish(ctx context.Context, eventType TaskEventType, task Task)
+	// Subscribe replays the events of epoch after lastEventID, a nil lastEventID only gets
+	// the new events. The returned function ends the subscription.
+	Subscribe(epoch string, lastEventID *int64) (TaskEventSubscription, func())
+}
+
+// FormatTaskEventID is the ID a stream client sends back to resume, the event ID prefixed
+// with its epoch.
+func FormatTaskEventID(epoch string, id int64) string {
+	return epoch + "-" + strconv.FormatInt(id, 10)
+}
+
+// ParseTaskEventID splits an ID made by FormatTaskEventID. An ID without an epoch was
+// handed out by an older version and gets an empty epoch.
+func ParseTaskEventID(s string) (string, int64, error) {
+	var epoch string
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		epoch, s = s[:i], s[i+1:]
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	if id < 0 {
+		return "", 0, errors.New("negative event ID")
+	}
+	return epoch, id, nil
+}
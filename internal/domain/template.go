@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TemplateTask describes a task created by a template. DueInDays sets the due date that
+// many days after the instantiation start, the task has no due date when it is nil.
+// Subtasks become the checklist of the task.
+type TemplateTask struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description" binding:"max=10000"`
+	DueInDays   *int     `json:"dueInDays" binding:"omitempty,min=0,max=3650"`
+	Tags        []string `json:"tags" binding:"max=20,dive,required,max=50"`
+	Subtasks    []string `json:"subtasks" binding:"max=100,dive,required,max=500"`
+}
+
+// Template is a reusable bundle of tasks, like an onboarding or a release checklist.
+type Template struct {
+	ID    int64          `json:"id"`
+	Name  string         `json:"name"`
+	Tasks []TemplateTask `json:"tasks"`
+}
+
+type TemplateUriParameter struct {
+	ID int64 `uri:"template_id" binding:"required,min=1"`
+}
+
+type CreateTemplateRequest struct {
+	Name  string         `json:"name" binding:"required"`
+	Tasks []TemplateTask `json:"tasks" binding:"required,min=1,max=100,dive"`
+}
+
+type TemplateResponse struct {
+	Result Template `json:"result"`
+}
+
+type ListTemplateResponse struct {
+	Result []Template `json:"result"`
+}
+
+// InstantiateTemplateRequest sets the time the due dates count from, now when it is zero.
+type InstantiateTemplateRequest struct {
+	Start time.Time `json:"start"`
+}
+
+type InstantiateTemplateResponse struct {
+	Result []Task `json:"result"`
+}
+
+type TemplateUseCase interface {
+	Create(ctx context.Context, req CreateTemplateRequest) (TemplateResponse, error)
+	List(ctx context.Context) (ListTemplateResponse, error)
+	Get(ctx context.Context, id int64) (TemplateResponse, error)
+	Delete(ctx context.Context, id int64) error
+	Instantiate(ctx context.Context, id int64, req InstantiateTemplateRequest) (InstantiateTemplateResponse, error)
+}
+
+type TemplateRepository interface {
+	Create(ctx context.Context, template Template) (Template, error)
+	List(ctx context.Context) ([]Template, error)
+	Get(ctx context.Context, id int64) (Template, error)
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DateFormat is the layout of the calendar days used by time tracking, days are UTC.
+const DateFormat = "2006-01-02"
+
+// TimeEntry is a span of time a user worked on a task. End is nil while its timer runs.
+type TimeEntry struct {
+	ID     int64      `json:"id"`
+	TaskID int64      `json:"taskId"`
+	UserID int64      `json:"userId"`
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end,omitempty"`
+	Note   string     `json:"note,omitempty"`
+}
+
+type TimeEntryUriParameter struct {
+	TaskID int64 `uri:"task_id" binding:"required,min=1"`
+}
+
+type AddTimeEntryRequest struct {
+	Start time.Time `json:"start" binding:"required"`
+	End   time.Time `json:"end" binding:"required"`
+	Note  string    `json:"note" binding:"max=500"`
+}
+
+type TimeEntryResponse struct {
+	Result TimeEntry `json:"result"`
+}
+
+// DayTotal is the tracked time of one UTC day in seconds.
+type DayTotal struct {
+	Date    string `json:"date"`
+	Seconds int64  `json:"seconds"`
+}
+
+type ListTimeEntryResponse struct {
+	Result []TimeEntry `json:"result"`
+	Total  int64       `json:"total"`
+	ByDay  []DayTotal  `json:"byDay"`
+}
+
+// TimeReportRequest selects the tracked time from the start of From to the end of To.
+type TimeReportRequest struct {
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To     time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	UserID int64     `form:"userId" binding:"omitempty,min=1"`
+}
+
+type TaskTimeTotal struct {
+	TaskID int64      `json:"taskId"`
+	Total  int64      `json:"total"`
+	ByDay  []DayTotal `json:"byDay"`
+}
+
+type TimeReportResponse struct {
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Total int64           `json:"total"`
+	Tasks []TaskTimeTotal `json:"tasks"`
+	ByDay []DayTotal      `json:"byDay"`
+}
+
+type TimeTrackingUseCase interface {
+	StartTimer(ctx context.Context, taskID int64, userID int64) (TimeEntryResponse, error)
+	StopTimer(ctx context.Context, taskID int64, userID int64) (TimeEntryResponse, error)
+	AddEntry(ctx context.Context, taskID int64, userID int64, req AddTimeEntryRequest) (TimeEntryResponse, error)
+	List(ctx context.Context, taskID int64) (ListTimeEntryResponse, error)
+	Report(ctx context.Context, req TimeReportRequest) (TimeReportResponse, error)
+}
+
+type TimeEntryRepository interface {
+	Create(ctx context.Context, entry TimeEntry) (TimeEntry, error)
+	// StartTimer creates a running entry, it fails with ErrTimerRunning while the user
+	// has another running entry.
+	StartTimer(ctx context.Context, entry TimeEntry) (TimeEntry, error)
+	// StopTimer ends the user's running entry on the task, it fails with
+	// ErrTimerNotRunning if there is none.
+	StopTimer(ctx context.Context, taskID int64, userID int64, end time.Time) (TimeEntry, error)
+	ListByTask(ctx context.Context, taskID int64) ([]TimeEntry, error)
+	// ListBetween returns the entries overlapping [from, to), optionally of a single user.
+	ListBetween(ctx context.Context, from time.Time, to time.Time, userID int64) ([]TimeEntry, error)
+}
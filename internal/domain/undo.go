@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UndoEntry remembers how to reverse an update or a delete. Before is the task prior to
+// the operation and After the task right after it.
+type UndoEntry struct {
+	Token     string
+	TaskID    int64
+	Action    HistoryAction
+	ActorID   *int64
+	Before    Task
+	After     Task
+	ExpiresAt time.Time
+}
+
+type UndoUriParameter struct {
+	Token string `uri:"token" binding:"required"`
+}
+
+type UndoResponse struct {
+	Result Task `json:"result"`
+}
+
+type UndoUseCase interface {
+	Undo(ctx context.Context, token string) (UndoResponse, error)
+}
+
+type UndoRepository interface {
+	// Save stores the entry. An entry with an actor replaces the previous entry of that
+	// actor, so only the last operation of a client can be undone.
+	Save(ctx context.Context, entry UndoEntry) error
+	Get(ctx context.Context, token string) (UndoEntry, error)
+	Delete(ctx context.Context, token string) error
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+)
+
+const UserIDHeader = "X-User-ID"
+
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CreateUserResponse struct {
+	Result User `json:"result"`
+}
+
+type ListUserResponse struct {
+	Result []User `json:"result"`
+}
+
+type GetUserUriParameter struct {
+	ID int64 `uri:"user_id" binding:"required,min=1"`
+}
+
+type UserUseCase interface {
+	List(ctx context.Context) (ListUserResponse, error)
+	Create(ctx context.Context, req CreateUserRequest) (CreateUserResponse, error)
+	Get(ctx context.Context, id int64) (User, error)
+}
+
+type UserRepository interface {
+	List(ctx context.Context) ([]User, error)
+	Create(ctx context.Context, name string) (User, error)
+	Get(ctx context.Context, id int64) (User, error)
+}
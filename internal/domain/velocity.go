@@ -0,0 +1,41 @@
+package domain
+
+import "context"
+
+const (
+	DefaultVelocityWeeks  = 8
+	DefaultVelocityWindow = 3
+)
+
+// VelocityRequest selects the number of weeks reported, the number of weeks averaged by
+// the rolling velocity and the estimate unit summed, points unless set.
+type VelocityRequest struct {
+	Weeks  int          `form:"weeks" binding:"omitempty,min=1,max=52"`
+	Window int          `form:"window" binding:"omitempty,min=1,max=12"`
+	Unit   EstimateUnit `form:"unit" binding:"omitempty,oneof=points hours"`
+}
+
+// WeekVelocity sums the estimates of the tasks completed during a week starting on Monday
+// UTC. Completed tasks estimated in another unit or not estimated at all are only counted
+// in Unestimated. Rolling is the mean of Completed over the week and the ones before it in
+// the window.
+type WeekVelocity struct {
+	WeekStart   string  `json:"weekStart"`
+	Completed   float64 `json:"completed"`
+	Tasks       int     `json:"tasks"`
+	Unestimated int     `json:"unestimated"`
+	Rolling     float64 `json:"rolling"`
+}
+
+// VelocityResponse lists the weeks oldest first, the last one is the current week.
+// Velocity is the rolling velocity of the last finished week.
+type VelocityResponse struct {
+	Unit     EstimateUnit   `json:"unit"`
+	Window   int            `json:"window"`
+	Velocity float64        `json:"velocity"`
+	Weeks    []WeekVelocity `json:"weeks"`
+}
+
+type VelocityUseCase interface {
+	Velocity(ctx context.Context, req VelocityRequest) (VelocityResponse, error)
+}
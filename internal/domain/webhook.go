@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventTaskCreated   WebhookEvent = "task.created"
+	WebhookEventTaskCompleted WebhookEvent = "task.completed"
+	WebhookEventTaskDeleted   WebhookEvent = "task.deleted"
+)
+
+// Header names of a webhook delivery. The timestamp is the Unix time in seconds the
+// delivery was sent at. The signature is "sha256=" followed by the hex HMAC-SHA256 of the
+// timestamp, a dot and the request body, keyed with the subscription secret, so a receiver
+// can turn down replayed deliveries by their timestamp.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// MaxWebhookBackoff caps the wait between two attempts of a delivery.
+const MaxWebhookBackoff = time.Hour
+
+// WebhookSubscription receives the listed events. The secret is write-only, it is never
+// returned by the API.
+type WebhookSubscription struct {
+	ID        int64          `json:"id"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Secret    string         `json:"-"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// Wants reports whether the subscription filters in the event.
+func (s WebhookSubscription) Wants(event WebhookEvent) bool {
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the body posted to the subscribers, the delivery ID travels in the
+// WebhookDeliveryHeader so receivers can drop repeated deliveries.
+type WebhookPayload struct {
+	Event      WebhookEvent `json:"event"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	Task       Task         `json:"task"`
+}
+
+// WebhookDelivery is one event queued for one subscription. A delivery that failed
+// MaxAttempts times is moved to the dead-letter list with DeadAt set.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscriptionId"`
+	Event          WebhookEvent    `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastError      string          `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	DeadAt         *time.Time      `json:"deadAt,omitempty"`
+}
+
+type WebhookUriParameter struct {
+	ID int64 `uri:"webhook_id" binding:"required,min=1"`
+}
+
+type WebhookDeliveryUriParameter struct {
+	ID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string         `json:"url" binding:"required,url,startswith=http://|startswith=https://"`
+	Events []WebhookEvent `json:"events" binding:"required,min=1,dive,oneof=task.created task.completed task.deleted"`
+	Secret string         `json:"secret" binding:"required,min=16,max=256"`
+}
+
+type WebhookResponse struct {
+	Result WebhookSubscription `json:"result"`
+}
+
+type ListWebhookResponse struct {
+	Result []WebhookSubscription `json:"result"`
+}
+
+type ListWebhookDeliveryResponse struct {
+	Result []WebhookDelivery `json:"result"`
+}
+
+type WebhookDeliveryResponse struct {
+	Result WebhookDelivery `json:"result"`
+}
+
+type WebhookUseCase interface {
+	Create(ctx context.Context, req CreateWebhookRequest) (WebhookResponse, error)
+	List(ctx context.Context) (ListWebhookResponse, error)
+	Get(ctx context.Context, id int64) (WebhookResponse, error)
+	Delete(ctx context.Context, id int64) error
+	// Publish queues a delivery of the event for every subscription that wants it.
+	Publish(ctx context.Context, event WebhookEvent, task Task) error
+	// DeliverDue attempts the queued deliveries that are due and returns how many succeeded.
+	DeliverDue(ctx context.Context) (int, error)
+	ListDeadLetters(ctx context.Context) (ListWebhookDeliveryResponse, error)
+	// Retry puts a dead letter back in the queue with a fresh attempt count.
+	Retry(ctx context.Context, deliveryID int64) (WebhookDeliveryResponse, error)
+}
+
+// WebhookSender posts a delivery to a subscription, any answer but a 2xx is an error.
+type WebhookSender interface {
+	Send(ctx context.Context, subscription WebhookSubscription, delivery WebhookDelivery) error
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription WebhookSubscription) (WebhookSubscription, error)
+	List(ctx context.Context) ([]WebhookSubscription, error)
+	Get(ctx context.Context, id int64) (WebhookSubscription, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type WebhookDeliveryRepository interface {
+	Enqueue(ctx context.Context, deliveries []WebhookDelivery) ([]WebhookDelivery, error)
+	// Due returns the pending deliveries whose next attempt is not after now, oldest first.
+	Due(ctx context.Context, now time.Time) ([]WebhookDelivery, error)
+	Get(ctx context.Context, id int64) (WebhookDelivery, error)
+	// Update stores the delivery, only the most recently finished deliveries are kept once
+	// they are delivered or dead.
+	Update(ctx context.Context, delivery WebhookDelivery) (WebhookDelivery, error)
+	ListDead(ctx context.Context) ([]WebhookDelivery, error)
+}
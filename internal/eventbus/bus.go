@@ -0,0 +1,169 @@
+// Package eventbus implements domain.EventBus in process.
+package eventbus
+
+import (
+	"context"
+	"log"
+	"oa-gogolook/internal/domain"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	// AsyncWorkers is the number of goroutines of an asynchronous subscriber. The events of
+	// a task always go to the same worker, so they are handled one after the other.
+	AsyncWorkers = 4
+	// AsyncQueue is how many events a worker queues before Publish waits for it.
+	AsyncQueue = 256
+)
+
+type subscriber struct {
+	id      int64
+	name    string
+	handler domain.EventHandler
+	// queues is nil for a synchronous subscriber.
+	queues []chan queuedEvent
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event domain.DomainEvent
+}
+
+type bus struct {
+	mu          sync.Mutex
+	subscribers []*subscriber
+	nextID      int64
+}
+
+func NewBus() *bus {
+	return &bus{}
+}
+
+func (b *bus) Publish(ctx context.Context, event domain.DomainEvent) {
+	// Handlers may publish in turn, so they are not called under the lock.
+	b.mu.Lock()
+	subscribers := make([]*subscriber, len(b.subscribers))
+	copy(subscribers, b.subscribers)
+	b.mu.Unlock()
+
+	for _, s := range subscribers {
+		if s.queues == nil {
+			s.handle(ctx, event)
+			continue
+		}
+		s.enqueue(queuedEvent{ctx: detached{ctx}, event: event})
+	}
+}
+
+func (b *bus) Subscribe(name string, handler domain.EventHandler) func() {
+	return b.add(&subscriber{name: name, handler: handler})
+}
+
+func (b *bus) SubscribeAsync(name string, handler domain.EventHandler) func() {
+	s := &subscriber{name: name, handler: handler, queues: make([]chan queuedEvent, AsyncWorkers)}
+	for i := range s.queues {
+		queue := make(chan queuedEvent, AsyncQueue)
+		s.queues[i] = queue
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for e := range queue {
+				s.handle(e.ctx, e.event)
+			}
+		}()
+	}
+	return b.add(s)
+}
+
+func (b *bus) add(s *subscriber) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	s.id = b.nextID
+	b.subscribers = append(b.subscribers, s)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.remove(s.id)
+			s.close()
+		})
+	}
+}
+
+func (b *bus) remove(id int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.subscribers {
+		if s.id == id {
+			b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// enqueue hands the event to the worker of its task, events published after the
+// subscriber closed are dropped.
+func (s *subscriber) enqueue(e queuedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	shard := e.event.TaskID() % int64(len(s.queues))
+	if shard < 0 {
+		shard = -shard
+	}
+	s.queues[shard] <- e
+}
+
+// close stops an asynchronous subscriber once its queued events are handled.
+func (s *subscriber) close() {
+	if s.queues == nil {
+		return
+	}
+	s.mu.Lock()
+	s.closed = true
+	for _, queue := range s.queues {
+		close(queue)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *subscriber) handle(ctx context.Context, event domain.DomainEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("event subscriber %s panicked on %T of task %d: %v\n%s", s.name, event, event.TaskID(), r, debug.Stack())
+		}
+	}()
+	if err := s.handler(ctx, event); err != nil {
+		log.Printf("event subscriber %s failed on %T of task %d: %v", s.name, event, event.TaskID(), err)
+	}
+}
+
+// detached keeps the values of a context but not its deadline or cancellation, the
+// request that published an event may be over before an asynchronous subscriber runs.
+type detached struct {
+	parent context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,101 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func created(id int64, n int) domain.DomainEvent {
+	return domain.TaskCreated{Task: domain.Task{ID: id, Name: strconv.Itoa(n)}}
+}
+
+func Test_bus_Subscribe(t *testing.T) {
+	b := NewBus()
+	got := make([]string, 0)
+	b.Subscribe("panics", func(ctx context.Context, event domain.DomainEvent) error {
+		panic("boom")
+	})
+	b.Subscribe("fails", func(ctx context.Context, event domain.DomainEvent) error {
+		return errors.New("failed")
+	})
+	unsubscribe := b.Subscribe("records", func(ctx context.Context, event domain.DomainEvent) error {
+		got = append(got, event.(domain.TaskCreated).Task.Name)
+		return nil
+	})
+
+	b.Publish(context.Background(), created(1, 1))
+	b.Publish(context.Background(), created(2, 2))
+	unsubscribe()
+	unsubscribe()
+	b.Publish(context.Background(), created(1, 3))
+
+	if want := []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sync subscriber got %v, want %v", got, want)
+	}
+}
+
+func Test_bus_SubscribeAsync(t *testing.T) {
+	b := NewBus()
+	var mu sync.Mutex
+	got := make(map[int64][]int)
+	b.SubscribeAsync("panics", func(ctx context.Context, event domain.DomainEvent) error {
+		panic("boom")
+	})
+	unsubscribe := b.SubscribeAsync("records", func(ctx context.Context, event domain.DomainEvent) error {
+		n, _ := strconv.Atoi(event.(domain.TaskCreated).Task.Name)
+		if n%7 == 0 {
+			panic("boom")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		got[event.TaskID()] = append(got[event.TaskID()], n)
+		return nil
+	})
+
+	want := make(map[int64][]int)
+	var wg sync.WaitGroup
+	for id := int64(1); id <= 10; id++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			for n := 1; n <= 200; n++ {
+				b.Publish(context.Background(), created(id, n))
+			}
+		}(id)
+		for n := 1; n <= 200; n++ {
+			if n%7 != 0 {
+				want[id] = append(want[id], n)
+			}
+		}
+	}
+	wg.Wait()
+	unsubscribe()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("async subscriber got the events of a task out of order or lost some")
+	}
+}
+
+func Test_bus_SubscribeAsync_Context(t *testing.T) {
+	b := NewBus()
+	type key string
+	got := make(chan context.Context, 1)
+	unsubscribe := b.SubscribeAsync("context", func(ctx context.Context, event domain.DomainEvent) error {
+		got <- ctx
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key("origin"), "socket-1"))
+	cancel()
+	b.Publish(ctx, created(1, 1))
+	unsubscribe()
+
+	handled := <-got
+	if handled.Err() != nil || handled.Value(key("origin")) != "socket-1" {
+		t.Errorf("async context Err() = %v, Value() = %v, want nil, socket-1", handled.Err(), handled.Value(key("origin")))
+	}
+}
@@ -0,0 +1,25 @@
+// Package job runs the periodic background work of the service.
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every calls fn once per interval until ctx is done. Errors are logged and do not stop the
+// loop, a run that is still busy when the next tick arrives delays it instead of overlapping.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("job %s failed: %v", name, err)
+			}
+		}
+	}
+}
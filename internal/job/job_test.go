@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, "test", time.Millisecond, func(ctx context.Context) error {
+			runs <- struct{}{}
+			return errors.New("keep going")
+		})
+		close(done)
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatalf("Every() ran %d times, want 3", i)
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-runs:
+		<-done
+	case <-time.After(time.Second):
+		t.Fatal("Every() did not stop after the context was cancelled")
+	}
+}
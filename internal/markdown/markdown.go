@@ -0,0 +1,49 @@
+package markdown
+
+import (
+	"bytes"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// renderer keeps goldmark's safe defaults: raw HTML (script, iframe, ...) in the source is
+// omitted and links using dangerous schemes such as javascript: are rendered without target.
+var renderer = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(
+		parser.WithASTTransformers(util.Prioritized(autoLinkSanitizer{}, 1000)),
+	),
+)
+
+// autoLinkSanitizer turns autolinks with a dangerous URL (e.g. <javascript:...>) into plain
+// text, goldmark only filters the destination of regular links.
+type autoLinkSanitizer struct{}
+
+func (autoLinkSanitizer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var unsafe []*ast.AutoLink
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.AutoLink); ok && entering && html.IsDangerousURL(link.URL(source)) {
+			unsafe = append(unsafe, link)
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, link := range unsafe {
+		label := ast.NewString(link.Label(source))
+		link.Parent().ReplaceChild(link.Parent(), link, label)
+	}
+}
+
+// Render converts Markdown into sanitized HTML.
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
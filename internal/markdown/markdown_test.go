@@ -0,0 +1,59 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "Markdown",
+			source:   "# Title\n\nSome **bold** text and a [link](https://example.com).",
+			contains: []string{"<h1>Title</h1>", "<strong>bold</strong>", `<a href="https://example.com">link</a>`},
+		},
+		{
+			name:        "Script",
+			source:      "hello <script>alert(1)</script>\n\n<script>alert(2)</script>",
+			notContains: []string{"<script"},
+		},
+		{
+			name:        "Iframe",
+			source:      "<iframe src=\"https://evil.example\"></iframe>",
+			notContains: []string{"<iframe"},
+		},
+		{
+			name:        "JavascriptLink",
+			source:      "[click](javascript:alert(1)) <javascript:alert(2)>",
+			notContains: []string{"href=\"javascript:"},
+		},
+		{
+			name:        "EventHandlerAttribute",
+			source:      "<img src=x onerror=alert(1)>",
+			notContains: []string{"onerror"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("Render() = %q, want it to contain %q", got, s)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(got, s) {
+					t.Errorf("Render() = %q, must not contain %q", got, s)
+				}
+			}
+		})
+	}
+}
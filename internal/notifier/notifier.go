@@ -0,0 +1,61 @@
+// Package notifier delivers fired reminders and outbox messages outside the service.
+package notifier
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/webhook"
+	"time"
+)
+
+type logNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier writes reminders and outbox messages to logger, log.Default() when nil.
+func NewLogNotifier(logger *log.Logger) *logNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &logNotifier{
+		logger: logger,
+	}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.logger.Printf("reminder %d: task %d %q is due at %s",
+		notification.Reminder.ID, notification.Task.ID, notification.Task.Name, notification.FireAt.Format(time.RFC3339))
+	return nil
+}
+
+func (n *logNotifier) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	n.logger.Printf("outbox message %s: task %d %s at %s",
+		message.ID, message.Task.ID, message.Type, message.OccurredAt.Format(time.RFC3339))
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	poster webhook.Poster
+}
+
+// NewWebhookNotifier posts reminders and outbox messages as JSON to url with a
+// webhook.Poster, an outbox message with its ID in the domain.OutboxMessageIDHeader header.
+func NewWebhookNotifier(url string, client *http.Client) *webhookNotifier {
+	return &webhookNotifier{
+		url:    url,
+		poster: webhook.NewPoster(client),
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	return n.poster.PostJSON(ctx, n.url, notification, nil)
+}
+
+func (n *webhookNotifier) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	header := http.Header{}
+	header.Set(domain.OutboxMessageIDHeader, message.ID)
+	return n.poster.PostJSON(ctx, n.url, message, header)
+}
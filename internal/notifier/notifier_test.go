@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testNotification() domain.Notification {
+	return domain.Notification{
+		Reminder: domain.Reminder{ID: 3, TaskID: 1},
+		Task:     domain.Task{ID: 1, Name: "ship"},
+		FireAt:   time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func testMessage() domain.OutboxMessage {
+	return domain.OutboxMessage{
+		ID:         "4-1",
+		Seq:        4,
+		Type:       domain.TaskEventUpdated,
+		OccurredAt: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC),
+		Task:       domain.Task{ID: 1, Name: "ship"},
+	}
+}
+
+func Test_logNotifier_Notify(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewLogNotifier(log.New(&buf, "", 0))
+	if err := n.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	want := "reminder 3: task 1 \"ship\" is due at 2022-11-01T10:00:00Z\n"
+	if buf.String() != want {
+		t.Errorf("Notify() logged %q, want %q", buf.String(), want)
+	}
+}
+
+func Test_webhookNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "OK",
+			status:  http.StatusNoContent,
+			wantErr: false,
+		},
+		{
+			name:    "ServerError",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.Notification
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+					t.Errorf("webhook got %s %s", r.Method, r.Header.Get("Content-Type"))
+				}
+				_ = json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookNotifier(server.URL, server.Client()).Notify(context.Background(), testNotification())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Reminder.ID != 3 || got.Task.Name != "ship" {
+				t.Errorf("webhook got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_logNotifier_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewLogNotifier(log.New(&buf, "", 0))
+	if err := n.Publish(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	want := "outbox message 4-1: task 1 task.updated at 2022-11-01T10:00:00Z\n"
+	if buf.String() != want {
+		t.Errorf("Publish() logged %q, want %q", buf.String(), want)
+	}
+}
+
+func Test_webhookNotifier_Publish(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "OK",
+			status:  http.StatusAccepted,
+			wantErr: false,
+		},
+		{
+			name:    "ServerError",
+			status:  http.StatusServiceUnavailable,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.OutboxMessage
+			var gotID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotID = r.Header.Get(domain.OutboxMessageIDHeader)
+				_ = json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookNotifier(server.URL, server.Client()).Publish(context.Background(), testMessage())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotID != "4-1" || got.ID != "4-1" || got.Task.Name != "ship" {
+				t.Errorf("endpoint got %q, %+v", gotID, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package eventsource implements domain.TaskRepository on top of an append-only event
+// stream. The current state is a projection of the stream, snapshots of the projection
+// shorten the replay at startup, and the state at any past moment can be rebuilt.
+package eventsource
+
+import (
+	"encoding/json"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+type EventType string
+
+const (
+	TaskCreated            EventType = "TaskCreated"
+	TaskStatusChanged      EventType = "TaskStatusChanged"
+	TaskDescriptionChanged EventType = "TaskDescriptionChanged"
+	TaskAssigned           EventType = "TaskAssigned"
+	TaskEstimated          EventType = "TaskEstimated"
+	TasksRanked            EventType = "TasksRanked"
+	TaskDeleted            EventType = "TaskDeleted"
+	TaskRestored           EventType = "TaskRestored"
+	TaskPurged             EventType = "TaskPurged"
+	TaskArchived           EventType = "TaskArchived"
+	TaskUnarchived         EventType = "TaskUnarchived"
+	ChecklistItemAdded     EventType = "ChecklistItemAdded"
+	ChecklistReordered     EventType = "ChecklistReordered"
+	ChecklistItemToggled   EventType = "ChecklistItemToggled"
+	ChecklistItemRemoved   EventType = "ChecklistItemRemoved"
+	// OutboxRecorded carries the outbox messages of the events committed along with it,
+	// OutboxDelivered moves the delivered mark. Neither changes a task.
+	OutboxRecorded  EventType = "OutboxRecorded"
+	OutboxDelivered EventType = "OutboxDelivered"
+)
+
+// Event is a single immutable fact of the stream. Seq orders the events of a stream
+// without gaps, Data holds the JSON payload belonging to Type.
+type Event struct {
+	Seq    int64           `json:"seq"`
+	Type   EventType       `json:"type"`
+	TaskID int64           `json:"taskId"`
+	At     time.Time       `json:"at"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+type taskCreatedData struct {
+	Task domain.Task `json:"task"`
+}
+
+type statusChangedData struct {
+	Status domain.Status `json:"status"`
+	// CompletedAt, when set, is the completion time a reverted task gets back instead of the
+	// time of the event.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+type descriptionChangedData struct {
+	Description string `json:"description"`
+}
+
+type assignedData struct {
+	AssigneeID *int64 `json:"assigneeId"`
+}
+
+type estimatedData struct {
+	Estimate *domain.Estimate `json:"estimate"`
+}
+
+// ranksData carries the new ranks of the tasks touched by a move or a respread.
+type ranksData struct {
+	Ranks map[int64]string `json:"ranks"`
+}
+
+type checklistItemData struct {
+	Item domain.ChecklistItem `json:"item"`
+}
+
+type checklistItemIDData struct {
+	ItemID int64 `json:"itemId"`
+}
+
+type checklistOrderData struct {
+	ItemIDs []int64 `json:"itemIds"`
+}
+
+type outboxRecordedData struct {
+	Messages []domain.OutboxMessage `json:"messages"`
+}
+
+type outboxDeliveredData struct {
+	Seq int64 `json:"seq"`
+}
+
+func newEvent(eventType EventType, taskID int64, at time.Time, data interface{}) (Event, error) {
+	e := Event{
+		Type:   eventType,
+		TaskID: taskID,
+		At:     at,
+	}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return Event{}, err
+		}
+		e.Data = raw
+	}
+	return e, nil
+}
@@ -0,0 +1,307 @@
+package eventsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// projection is the state built by applying events in order. Checklist slices are replaced
+// instead of edited in place, so tasks handed out earlier never observe later events.
+type projection struct {
+	seq    int64
+	at     time.Time
+	lastID int64
+	tasks  map[int64]*domain.Task
+	// epoch is the time of the first event, it changes when the event store starts over.
+	epoch string
+	// checklistIDs is the highest checklist item ID a task ever had, so the ID of a removed
+	// item is never handed out again.
+	checklistIDs map[int64]int64
+	// outboxSeq is the sequence number of the last OutboxRecorded event, outboxDelivered
+	// the one up to which its messages are delivered.
+	outboxSeq       int64
+	outboxDelivered int64
+}
+
+func newProjection() *projection {
+	return &projection{
+		tasks:        map[int64]*domain.Task{},
+		checklistIDs: map[int64]int64{},
+	}
+}
+
+func projectionFromSnapshot(s Snapshot) *projection {
+	p := newProjection()
+	p.seq = s.Seq
+	p.at = s.At
+	p.lastID = s.LastID
+	p.epoch = s.Epoch
+	p.outboxSeq = s.OutboxSeq
+	p.outboxDelivered = s.OutboxDelivered
+	for _, task := range s.Tasks {
+		task := task
+		p.tasks[task.ID] = &task
+		p.seeChecklist(task.ID, task.Checklist)
+	}
+	for id, itemID := range s.ChecklistIDs {
+		if itemID > p.checklistIDs[id] {
+			p.checklistIDs[id] = itemID
+		}
+	}
+	return p
+}
+
+func (p *projection) snapshot() Snapshot {
+	checklistIDs := make(map[int64]int64, len(p.checklistIDs))
+	for id, itemID := range p.checklistIDs {
+		checklistIDs[id] = itemID
+	}
+	return Snapshot{
+		Seq:    p.seq,
+		At:     p.at,
+		LastID: p.lastID,
+		Epoch:  p.epoch,
+		Tasks:  p.list(domain.TaskFilter{IncludeDeleted: true, IncludeArchived: true}),
+
+		ChecklistIDs: checklistIDs,
+
+		OutboxSeq:       p.outboxSeq,
+		OutboxDelivered: p.outboxDelivered,
+	}
+}
+
+// fork returns a scratch copy of the projection holding copies of the given tasks only,
+// events touching just those tasks can be applied to it without changing p.
+func (p *projection) fork(ids map[int64]bool) *projection {
+	scratch := newProjection()
+	scratch.seq = p.seq
+	scratch.at = p.at
+	scratch.lastID = p.lastID
+	scratch.epoch = p.epoch
+	scratch.outboxSeq = p.outboxSeq
+	scratch.outboxDelivered = p.outboxDelivered
+	for id := range ids {
+		if task, ok := p.tasks[id]; ok {
+			c := *task
+			scratch.tasks[id] = &c
+		}
+		if itemID, ok := p.checklistIDs[id]; ok {
+			scratch.checklistIDs[id] = itemID
+		}
+	}
+	return scratch
+}
+
+// adopt takes over the state of a scratch copy made by fork for the same tasks.
+func (p *projection) adopt(scratch *projection, ids map[int64]bool) {
+	p.seq = scratch.seq
+	p.at = scratch.at
+	p.lastID = scratch.lastID
+	p.epoch = scratch.epoch
+	p.outboxSeq = scratch.outboxSeq
+	p.outboxDelivered = scratch.outboxDelivered
+	for id := range ids {
+		if task, ok := scratch.tasks[id]; ok {
+			p.tasks[id] = task
+		} else {
+			delete(p.tasks, id)
+		}
+		if itemID, ok := scratch.checklistIDs[id]; ok {
+			p.checklistIDs[id] = itemID
+		} else {
+			delete(p.checklistIDs, id)
+		}
+	}
+}
+
+// seeChecklist raises the highest checklist item ID of the task to the items given.
+func (p *projection) seeChecklist(id int64, items []domain.ChecklistItem) {
+	for _, item := range items {
+		if item.ID > p.checklistIDs[id] {
+			p.checklistIDs[id] = item.ID
+		}
+	}
+}
+
+// live returns the task unless it does not exist or sits in the trash.
+func (p *projection) live(id int64) (*domain.Task, bool) {
+	task, ok := p.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return nil, false
+	}
+	return task, true
+}
+
+func (p *projection) list(filter domain.TaskFilter) []domain.Task {
+	tasks := make([]domain.Task, 0, len(p.tasks))
+	for _, task := range p.tasks {
+		if filter.Match(*task) {
+			tasks = append(tasks, *task)
+		}
+	}
+	sortTasks(tasks)
+	return tasks
+}
+
+func (p *projection) apply(e Event) error {
+	if e.Seq != p.seq+1 {
+		return fmt.Errorf("event %d applied after %d", e.Seq, p.seq)
+	}
+	p.at = e.At
+	if e.Seq == 1 {
+		p.epoch = strconv.FormatInt(e.At.UnixNano(), 36)
+	}
+	if e.Type == TaskCreated {
+		var data taskCreatedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		task := data.Task
+		task.CreatedAt = e.At
+		p.tasks[task.ID] = &task
+		p.seeChecklist(task.ID, task.Checklist)
+		if task.ID > p.lastID {
+			p.lastID = task.ID
+		}
+		p.seq = e.Seq
+		return nil
+	}
+	if e.Type == OutboxRecorded || e.Type == OutboxDelivered {
+		if e.Type == OutboxRecorded {
+			p.outboxSeq = e.Seq
+		} else {
+			var data outboxDeliveredData
+			if err := json.Unmarshal(e.Data, &data); err != nil {
+				return err
+			}
+			p.outboxDelivered = data.Seq
+		}
+		p.seq = e.Seq
+		return nil
+	}
+	if e.Type == TasksRanked {
+		var data ranksData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		for id, r := range data.Ranks {
+			if task, ok := p.tasks[id]; ok {
+				task.Rank = r
+			}
+		}
+		p.seq = e.Seq
+		return nil
+	}
+
+	task, ok := p.tasks[e.TaskID]
+	if !ok {
+		return fmt.Errorf("event %d refers to unknown task %d", e.Seq, e.TaskID)
+	}
+	at := e.At
+	switch e.Type {
+	case TaskStatusChanged:
+		var data statusChangedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		switch {
+		case data.Status == domain.StatusComplete && data.CompletedAt != nil:
+			completedAt := *data.CompletedAt
+			task.CompletedAt = &completedAt
+		case data.Status == domain.StatusComplete && task.Status != domain.StatusComplete:
+			task.CompletedAt = &at
+		case data.Status != domain.StatusComplete:
+			task.CompletedAt = nil
+		}
+		task.Status = data.Status
+	case TaskDescriptionChanged:
+		var data descriptionChangedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		task.Description = data.Description
+	case TaskAssigned:
+		var data assignedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		task.AssigneeID = data.AssigneeID
+	case TaskEstimated:
+		var data estimatedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		task.Estimate = data.Estimate
+	case TaskDeleted:
+		task.DeletedAt = &at
+	case TaskRestored:
+		task.DeletedAt = nil
+	case TaskPurged:
+		delete(p.tasks, e.TaskID)
+		delete(p.checklistIDs, e.TaskID)
+	case TaskArchived:
+		task.ArchivedAt = &at
+	case TaskUnarchived:
+		task.ArchivedAt = nil
+	case ChecklistItemAdded:
+		var data checklistItemData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		checklist := make([]domain.ChecklistItem, 0, len(task.Checklist)+1)
+		checklist = append(checklist, task.Checklist...)
+		task.Checklist = append(checklist, data.Item)
+		p.seeChecklist(task.ID, []domain.ChecklistItem{data.Item})
+	case ChecklistReordered:
+		var data checklistOrderData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		items := make(map[int64]domain.ChecklistItem, len(task.Checklist))
+		for _, item := range task.Checklist {
+			items[item.ID] = item
+		}
+		checklist := make([]domain.ChecklistItem, 0, len(data.ItemIDs))
+		for _, itemID := range data.ItemIDs {
+			checklist = append(checklist, items[itemID])
+		}
+		task.Checklist = checklist
+	case ChecklistItemToggled, ChecklistItemRemoved:
+		var data checklistItemIDData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		checklist := make([]domain.ChecklistItem, 0, len(task.Checklist))
+		for _, item := range task.Checklist {
+			if item.ID == data.ItemID {
+				if e.Type == ChecklistItemRemoved {
+					continue
+				}
+				item.Checked = !item.Checked
+			}
+			checklist = append(checklist, item)
+		}
+		task.Checklist = checklist
+	default:
+		return fmt.Errorf("event %d has unknown type %q", e.Seq, e.Type)
+	}
+	p.seq = e.Seq
+	return nil
+}
+
+func rankLess(a domain.Task, b domain.Task) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.ID < b.ID
+}
+
+func sortTasks(tasks []domain.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return rankLess(tasks[i], tasks[j])
+	})
+}
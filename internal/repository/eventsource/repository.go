@@ -0,0 +1,775 @@
+package eventsource
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/rank"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultSnapshotEvery is the number of events between two snapshots.
+const DefaultSnapshotEvery = 100
+
+// taskRepository validates every command against the projection, appends the resulting
+// events to the event store and only then applies them to the projection.
+type taskRepository struct {
+	mu            sync.Mutex
+	events        EventStore
+	snapshots     SnapshotStore
+	snapshotEvery int
+	sinceSnapshot int
+	projection    *projection
+	now           func() time.Time
+}
+
+// NewTaskRepository restores the state from the latest snapshot and the events appended
+// after it. A snapshotEvery of zero or less turns snapshotting off.
+func NewTaskRepository(ctx context.Context, events EventStore, snapshots SnapshotStore, snapshotEvery int) (*taskRepository, error) {
+	r := &taskRepository{
+		events:        events,
+		snapshots:     snapshots,
+		snapshotEvery: snapshotEvery,
+		now:           time.Now,
+	}
+	p, err := r.replay(ctx, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	r.projection = p
+	return r, nil
+}
+
+// replay builds a projection from the snapshot and the stored events. A non-zero until
+// leaves out the events recorded after it.
+func (r *taskRepository) replay(ctx context.Context, until time.Time) (*projection, error) {
+	p := newProjection()
+	snapshot, ok, err := r.snapshots.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok && (until.IsZero() || !snapshot.At.After(until)) {
+		p = projectionFromSnapshot(snapshot)
+	}
+	events, err := r.events.Load(ctx, p.seq)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if !until.IsZero() && e.At.After(until) {
+			break
+		}
+		if err := p.apply(e); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Rebuild returns every task as it was at the given moment, including deleted and archived
+// ones, in rank order.
+func (r *taskRepository) Rebuild(ctx context.Context, at time.Time) ([]domain.Task, error) {
+	p, err := r.replay(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	return p.list(domain.TaskFilter{IncludeDeleted: true, IncludeArchived: true}), nil
+}
+
+func (r *taskRepository) ListAsOf(ctx context.Context, filter domain.TaskFilter, at time.Time) ([]domain.Task, error) {
+	p, err := r.replay(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	r.forgetPurged(p)
+	return p.list(filter), nil
+}
+
+func (r *taskRepository) GetAsOf(ctx context.Context, id int64, at time.Time) (domain.Task, error) {
+	p, err := r.replay(ctx, at)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	r.forgetPurged(p)
+	task, ok := p.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	return *task, nil
+}
+
+// forgetPurged removes the tasks purged since from a replayed projection, a purged task can
+// not be read as of any time.
+func (r *taskRepository) forgetPurged(p *projection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := range p.tasks {
+		if _, ok := r.projection.tasks[id]; !ok {
+			delete(p.tasks, id)
+		}
+	}
+}
+
+// event builds an event for the current command. The caller must hold r.mu.
+func (r *taskRepository) event(eventType EventType, taskID int64, data interface{}) (Event, error) {
+	return newEvent(eventType, taskID, r.now(), data)
+}
+
+// commit stores the events together with the outbox messages of the tasks they change and
+// applies them to the projection. The events are applied to a scratch copy of the tasks they
+// touch first, nothing is stored when one of them does not apply. The caller must hold r.mu.
+func (r *taskRepository) commit(ctx context.Context, events ...Event) error {
+	for i := range events {
+		events[i].Seq = r.projection.seq + int64(i) + 1
+	}
+	touched, err := touchedTasks(events)
+	if err != nil {
+		return err
+	}
+	scratch := r.projection.fork(touched)
+	for _, e := range events {
+		if err := scratch.apply(e); err != nil {
+			return err
+		}
+	}
+	outbox, err := r.outboxEvent(scratch, touched)
+	if err != nil {
+		return err
+	}
+	if outbox != nil {
+		if err := scratch.apply(*outbox); err != nil {
+			return err
+		}
+		events = append(events, *outbox)
+	}
+	if err := r.events.Append(ctx, events...); err != nil {
+		return err
+	}
+	r.projection.adopt(scratch, touched)
+	r.sinceSnapshot += len(events)
+	if r.snapshotEvery > 0 && r.sinceSnapshot >= r.snapshotEvery {
+		// The events are stored already, a failed snapshot only slows down the next start.
+		if err := r.snapshots.Save(ctx, r.projection.snapshot()); err != nil {
+			log.Printf("can not save task snapshot: %v", err)
+		} else {
+			r.sinceSnapshot = 0
+		}
+	}
+	return nil
+}
+
+// touchedTasks returns the IDs of the tasks the events change.
+func touchedTasks(events []Event) (map[int64]bool, error) {
+	touched := make(map[int64]bool)
+	for _, e := range events {
+		switch e.Type {
+		case OutboxRecorded, OutboxDelivered:
+			continue
+		case TasksRanked:
+			var data ranksData
+			if err := json.Unmarshal(e.Data, &data); err != nil {
+				return nil, err
+			}
+			for id := range data.Ranks {
+				touched[id] = true
+			}
+		default:
+			touched[e.TaskID] = true
+		}
+	}
+	return touched, nil
+}
+
+// outboxEvent records a message for every touched task the scratch projection changed, it
+// is nil when none did. The caller must hold r.mu.
+func (r *taskRepository) outboxEvent(scratch *projection, touched map[int64]bool) (*Event, error) {
+	ids := make([]int64, 0, len(touched))
+	for id := range touched {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	seq := scratch.seq + 1
+	messages := make([]domain.OutboxMessage, 0, len(ids))
+	for _, id := range ids {
+		if message, ok := domain.NewOutboxMessage(seq, scratch.at, r.projection.tasks[id], scratch.tasks[id]); ok {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	e, err := newEvent(OutboxRecorded, 0, scratch.at, outboxRecordedData{Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+	e.Seq = seq
+	return &e, nil
+}
+
+// PendingOutbox reads the messages recorded after the delivered mark. All messages of an
+// OutboxRecorded event are returned together.
+func (r *taskRepository) PendingOutbox(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rtn := make([]domain.OutboxMessage, 0)
+	if r.projection.outboxSeq <= r.projection.outboxDelivered {
+		return rtn, nil
+	}
+	events, err := r.events.Load(ctx, r.projection.outboxDelivered)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.Seq > r.projection.outboxSeq || len(rtn) >= limit {
+			break
+		}
+		if e.Type != OutboxRecorded {
+			continue
+		}
+		var data outboxRecordedData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return nil, err
+		}
+		rtn = append(rtn, data.Messages...)
+	}
+	return rtn, nil
+}
+
+// AckOutbox appends an OutboxDelivered event, so the mark survives a restart.
+func (r *taskRepository) AckOutbox(ctx context.Context, seq int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if seq > r.projection.outboxSeq {
+		seq = r.projection.outboxSeq
+	}
+	if seq <= r.projection.outboxDelivered {
+		return nil
+	}
+	e, err := r.event(OutboxDelivered, 0, outboxDeliveredData{Seq: seq})
+	if err != nil {
+		return err
+	}
+	return r.commit(ctx, e)
+}
+
+// commitTask commits a single event and returns the resulting task. The caller must hold r.mu.
+func (r *taskRepository) commitTask(ctx context.Context, eventType EventType, id int64, data interface{}) (domain.Task, error) {
+	e, err := r.event(eventType, id, data)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if err := r.commit(ctx, e); err != nil {
+		return domain.Task{}, err
+	}
+	return *r.projection.tasks[id], nil
+}
+
+// respreadEvent gives the tasks in ranks and created, and as few of their neighbours as
+// needed, short ranks again while keeping their order. ranks overrides the current ranks
+// and created lists the tasks created by the same commit. Only live tasks are ranked, and
+// the event only carries the ranks that change. The caller must hold r.mu.
+func (r *taskRepository) respreadEvent(ranks map[int64]string, created ...domain.Task) (Event, error) {
+	ordered := make([]domain.Task, 0, len(r.projection.tasks)+len(created))
+	for _, task := range r.projection.tasks {
+		if task.DeletedAt != nil {
+			continue
+		}
+		t := *task
+		if newRank, ok := ranks[t.ID]; ok {
+			t.Rank = newRank
+		}
+		ordered = append(ordered, t)
+	}
+	ordered = append(ordered, created...)
+	sortTasks(ordered)
+	wanted := make(map[int64]bool, len(ranks)+len(created))
+	for id := range ranks {
+		wanted[id] = true
+	}
+	for _, task := range created {
+		wanted[task.ID] = true
+	}
+	lo, hi := len(ordered), 0
+	current := make([]string, len(ordered))
+	for i, task := range ordered {
+		current[i] = task.Rank
+		if wanted[task.ID] {
+			if i < lo {
+				lo = i
+			}
+			hi = i + 1
+		}
+	}
+	start, spread := rank.Respread(current, lo, hi)
+	changed := make(map[int64]string, len(spread))
+	for i, newRank := range spread {
+		task := ordered[start+i]
+		if task.Rank != newRank || wanted[task.ID] {
+			changed[task.ID] = newRank
+		}
+	}
+	return r.event(TasksRanked, 0, ranksData{Ranks: changed})
+}
+
+func (r *taskRepository) List(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.projection.list(filter), nil
+}
+
+func (r *taskRepository) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task.ID = r.projection.lastID + 1
+	task.Status = domain.StatusIncomplete
+	if task.Rank == "" {
+		last := ""
+		for _, existing := range r.projection.tasks {
+			if existing.Rank > last {
+				last = existing.Rank
+			}
+		}
+		task.Rank = rank.After(last)
+	}
+	created, err := r.event(TaskCreated, task.ID, taskCreatedData{Task: task})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	events := []Event{created}
+	if len(task.Rank) > rank.MaxLength {
+		respread, err := r.respreadEvent(nil, task)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		events = append(events, respread)
+	}
+	if err := r.commit(ctx, events...); err != nil {
+		return domain.Task{}, err
+	}
+	return *r.projection.tasks[task.ID], nil
+}
+
+// CreateMany appends the creation of every task in a single batch, so either all of them
+// are stored or none.
+func (r *taskRepository) CreateMany(ctx context.Context, tasks []domain.Task) ([]domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := ""
+	for _, existing := range r.projection.tasks {
+		if existing.Rank > last {
+			last = existing.Rank
+		}
+	}
+	events := make([]Event, 0, len(tasks)+1)
+	created := make([]domain.Task, 0, len(tasks))
+	tooLong := false
+	for i, task := range tasks {
+		task.ID = r.projection.lastID + int64(i) + 1
+		task.Status = domain.StatusIncomplete
+		task.Rank = rank.After(last)
+		last = task.Rank
+		tooLong = tooLong || len(task.Rank) > rank.MaxLength
+		e, err := r.event(TaskCreated, task.ID, taskCreatedData{Task: task})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+		created = append(created, task)
+	}
+	if tooLong {
+		respread, err := r.respreadEvent(nil, created...)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, respread)
+	}
+	if err := r.commit(ctx, events...); err != nil {
+		return nil, err
+	}
+	rtn := make([]domain.Task, 0, len(created))
+	for _, task := range created {
+		rtn = append(rtn, *r.projection.tasks[task.ID])
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) Update(ctx context.Context, id int64, status domain.Status, description string) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	events := make([]Event, 0, 2)
+	if task.Status != status {
+		e, err := r.event(TaskStatusChanged, id, statusChangedData{Status: status})
+		if err != nil {
+			return domain.Task{}, err
+		}
+		events = append(events, e)
+	}
+	if task.Description != description {
+		e, err := r.event(TaskDescriptionChanged, id, descriptionChangedData{Description: description})
+		if err != nil {
+			return domain.Task{}, err
+		}
+		events = append(events, e)
+	}
+	if len(events) == 0 {
+		return *task, nil
+	}
+	if err := r.commit(ctx, events...); err != nil {
+		return domain.Task{}, err
+	}
+	return *r.projection.tasks[id], nil
+}
+
+func (r *taskRepository) Revert(ctx context.Context, id int64, to domain.Task) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	events := make([]Event, 0, 2)
+	if task.Status != to.Status || !sameTime(task.CompletedAt, to.CompletedAt) {
+		e, err := r.event(TaskStatusChanged, id, statusChangedData{Status: to.Status, CompletedAt: to.CompletedAt})
+		if err != nil {
+			return domain.Task{}, err
+		}
+		events = append(events, e)
+	}
+	if task.Description != to.Description {
+		e, err := r.event(TaskDescriptionChanged, id, descriptionChangedData{Description: to.Description})
+		if err != nil {
+			return domain.Task{}, err
+		}
+		events = append(events, e)
+	}
+	if len(events) == 0 {
+		return *task, nil
+	}
+	if err := r.commit(ctx, events...); err != nil {
+		return domain.Task{}, err
+	}
+	return *r.projection.tasks[id], nil
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.projection.live(id); !ok {
+		return domain.ErrDataNotFound
+	}
+	_, err := r.commitTask(ctx, TaskDeleted, id, nil)
+	return err
+}
+
+func (r *taskRepository) Restore(ctx context.Context, id int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.tasks[id]
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if task.DeletedAt == nil {
+		return domain.Task{}, domain.ErrTaskNotDeleted
+	}
+	return r.commitTask(ctx, TaskRestored, id, nil)
+}
+
+func (r *taskRepository) Purge(ctx context.Context, deletedBefore time.Time) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int64, 0)
+	for id, task := range r.projection.tasks {
+		if task.DeletedAt != nil && task.DeletedAt.Before(deletedBefore) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, r.commitEach(ctx, TaskPurged, ids)
+}
+
+func (r *taskRepository) Archive(ctx context.Context, id int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if task.ArchivedAt != nil {
+		return domain.Task{}, domain.ErrTaskArchived
+	}
+	return r.commitTask(ctx, TaskArchived, id, nil)
+}
+
+func (r *taskRepository) Unarchive(ctx context.Context, id int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if task.ArchivedAt == nil {
+		return domain.Task{}, domain.ErrTaskNotArchived
+	}
+	return r.commitTask(ctx, TaskUnarchived, id, nil)
+}
+
+func (r *taskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int64, 0)
+	for id, task := range r.projection.tasks {
+		if task.DeletedAt != nil || task.ArchivedAt != nil || task.Status != domain.StatusComplete {
+			continue
+		}
+		if task.CompletedAt != nil && task.CompletedAt.Before(completedBefore) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, r.commitEach(ctx, TaskArchived, ids)
+}
+
+// commitEach commits one payload-less event per task in a single batch. The caller must
+// hold r.mu.
+func (r *taskRepository) commitEach(ctx context.Context, eventType EventType, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	events := make([]Event, 0, len(ids))
+	for _, id := range ids {
+		e, err := r.event(eventType, id, nil)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+	}
+	return r.commit(ctx, events...)
+}
+
+func (r *taskRepository) Get(ctx context.Context, id int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	return *task, nil
+}
+
+func (r *taskRepository) Assign(ctx context.Context, id int64, assigneeID *int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.projection.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	return r.commitTask(ctx, TaskAssigned, id, assignedData{AssigneeID: assigneeID})
+}
+
+func (r *taskRepository) Estimate(ctx context.Context, id int64, estimate *domain.Estimate) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.projection.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	return r.commitTask(ctx, TaskEstimated, id, estimatedData{Estimate: estimate})
+}
+
+func (r *taskRepository) ListByAssignee(ctx context.Context, assigneeID int64) ([]domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tasks := make([]domain.Task, 0)
+	for _, task := range r.projection.list(domain.TaskFilter{}) {
+		if task.AssigneeID != nil && *task.AssigneeID == assigneeID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// Move ranks the task right before beforeID, or right after afterID when beforeID is 0.
+func (r *taskRepository) Move(ctx context.Context, id int64, beforeID int64, afterID int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	refID := beforeID
+	if refID == 0 {
+		refID = afterID
+	}
+	if _, ok := r.projection.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if _, ok := r.projection.live(refID); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	ordered := make([]domain.Task, 0, len(r.projection.tasks))
+	for _, task := range r.projection.tasks {
+		if task.ID != id && task.DeletedAt == nil {
+			ordered = append(ordered, *task)
+		}
+	}
+	sortTasks(ordered)
+	pos := 0
+	for pos < len(ordered) && ordered[pos].ID != refID {
+		pos++
+	}
+	if beforeID == 0 {
+		pos++
+	}
+	lower, upper := "", ""
+	if pos > 0 {
+		lower = ordered[pos-1].Rank
+	}
+	if pos < len(ordered) {
+		upper = ordered[pos].Rank
+	}
+
+	ranks := map[int64]string{id: rank.Between(lower, upper)}
+	e, err := r.event(TasksRanked, 0, ranksData{Ranks: ranks})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if len(ranks[id]) > rank.MaxLength {
+		e, err = r.respreadEvent(ranks)
+		if err != nil {
+			return domain.Task{}, err
+		}
+	}
+	if err := r.commit(ctx, e); err != nil {
+		return domain.Task{}, err
+	}
+	return *r.projection.tasks[id], nil
+}
+
+func (r *taskRepository) AddChecklistItem(ctx context.Context, id int64, text string) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.projection.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	nextID := r.projection.checklistIDs[id] + 1
+	return r.commitTask(ctx, ChecklistItemAdded, id, checklistItemData{Item: domain.ChecklistItem{ID: nextID, Text: text}})
+}
+
+func (r *taskRepository) ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if len(itemIDs) != len(task.Checklist) {
+		return domain.Task{}, domain.ErrChecklistOrder
+	}
+	remaining := make(map[int64]bool, len(task.Checklist))
+	for _, item := range task.Checklist {
+		remaining[item.ID] = true
+	}
+	for _, itemID := range itemIDs {
+		if !remaining[itemID] {
+			return domain.Task{}, domain.ErrChecklistOrder
+		}
+		delete(remaining, itemID)
+	}
+	return r.commitTask(ctx, ChecklistReordered, id, checklistOrderData{ItemIDs: itemIDs})
+}
+
+func (r *taskRepository) ToggleChecklistItem(ctx context.Context, id int64, itemID int64) (domain.Task, error) {
+	return r.checklistItemEvent(ctx, ChecklistItemToggled, id, itemID)
+}
+
+func (r *taskRepository) RemoveChecklistItem(ctx context.Context, id int64, itemID int64) (domain.Task, error) {
+	return r.checklistItemEvent(ctx, ChecklistItemRemoved, id, itemID)
+}
+
+func (r *taskRepository) checklistItemEvent(ctx context.Context, eventType EventType, id int64, itemID int64) (domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.projection.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	for _, item := range task.Checklist {
+		if item.ID == itemID {
+			return r.commitTask(ctx, eventType, id, checklistItemIDData{ItemID: itemID})
+		}
+	}
+	return domain.Task{}, domain.ErrChecklistNotFound
+}
+
+// Changes reads the events after since. A task was live at since unless its first event
+// after it, rank events aside, creates, restores or purges it; a task with rank events
+// only kept its state.
+func (r *taskRepository) Changes(ctx context.Context, since int64) (domain.TaskChanges, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rtn := domain.TaskChanges{
+		Created: make([]domain.Task, 0),
+		Updated: make([]domain.Task, 0),
+		Deleted: make([]int64, 0),
+		Seq:     r.projection.seq,
+		Epoch:   r.projection.epoch,
+	}
+	if since >= r.projection.seq {
+		return rtn, nil
+	}
+	events, err := r.events.Load(ctx, since)
+	if err != nil {
+		return domain.TaskChanges{}, err
+	}
+	touched := make(map[int64]bool)
+	absentBefore := make(map[int64]bool)
+	for _, e := range events {
+		if e.Seq > r.projection.seq {
+			break
+		}
+		if e.Type == OutboxRecorded || e.Type == OutboxDelivered {
+			continue
+		}
+		if e.Type == TasksRanked {
+			var data ranksData
+			if err := json.Unmarshal(e.Data, &data); err != nil {
+				return domain.TaskChanges{}, err
+			}
+			for id := range data.Ranks {
+				touched[id] = true
+			}
+			continue
+		}
+		touched[e.TaskID] = true
+		if _, ok := absentBefore[e.TaskID]; !ok {
+			absentBefore[e.TaskID] = e.Type == TaskCreated || e.Type == TaskRestored || e.Type == TaskPurged
+		}
+	}
+	for id := range touched {
+		task, now := r.projection.live(id)
+		absent, ok := absentBefore[id]
+		if !ok {
+			absent = !now
+		}
+		switch {
+		case now && !absent:
+			rtn.Updated = append(rtn.Updated, *task)
+		case now:
+			rtn.Created = append(rtn.Created, *task)
+		case !absent:
+			rtn.Deleted = append(rtn.Deleted, id)
+		}
+	}
+	sortTasks(rtn.Created)
+	sortTasks(rtn.Updated)
+	sort.Slice(rtn.Deleted, func(i, j int) bool { return rtn.Deleted[i] < rtn.Deleted[j] })
+	return rtn, nil
+}
@@ -0,0 +1,534 @@
+package eventsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"oa-gogolook/internal/domain"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func baseTime() time.Time {
+	return time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+}
+
+// newTestRepository returns a repository whose clock advances a minute per event.
+func newTestRepository(t *testing.T, events EventStore, snapshots SnapshotStore, snapshotEvery int) *taskRepository {
+	r, err := NewTaskRepository(context.Background(), events, snapshots, snapshotEvery)
+	if err != nil {
+		t.Fatalf("NewTaskRepository() error = %v", err)
+	}
+	tick := 0
+	r.now = func() time.Time {
+		tick++
+		return baseTime().Add(time.Duration(tick) * time.Minute)
+	}
+	return r
+}
+
+func createTasks(t *testing.T, r *taskRepository, names ...string) {
+	for _, name := range names {
+		if _, err := r.Create(context.Background(), domain.Task{Name: name}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+}
+
+func names(tasks []domain.Task) []string {
+	rtn := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		rtn = append(rtn, task.Name)
+	}
+	return rtn
+}
+
+func Test_taskRepository_Commands(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, NewMemoryEventStore(), NewMemorySnapshotStore(), 0)
+	createTasks(t, r, "task1", "task2", "task3")
+
+	got, err := r.Update(ctx, 1, domain.StatusComplete, "done")
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got.Status != domain.StatusComplete || got.Description != "done" || got.CompletedAt == nil {
+		t.Errorf("Update() got = %v", got)
+	}
+	assigneeID := int64(4)
+	if got, _ := r.Assign(ctx, 2, &assigneeID); got.AssigneeID == nil || *got.AssigneeID != 4 {
+		t.Errorf("Assign() got = %v", got)
+	}
+	if mine, _ := r.ListByAssignee(ctx, 4); !reflect.DeepEqual(names(mine), []string{"task2"}) {
+		t.Errorf("ListByAssignee() = %v", names(mine))
+	}
+	estimate := domain.Estimate{Value: 3, Unit: domain.EstimateUnitPoints}
+	if got, _ := r.Estimate(ctx, 3, &estimate); got.Estimate == nil || *got.Estimate != estimate {
+		t.Errorf("Estimate() got = %v", got)
+	}
+	if _, err := r.Move(ctx, 3, 1, 0); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if list, _ := r.List(ctx, domain.TaskFilter{}); !reflect.DeepEqual(names(list), []string{"task3", "task1", "task2"}) {
+		t.Errorf("List() after move = %v", names(list))
+	}
+	if err := r.Delete(ctx, 2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := r.Get(ctx, 2); err != domain.ErrDataNotFound {
+		t.Errorf("Get() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if err := r.Delete(ctx, 2); err != domain.ErrDataNotFound {
+		t.Errorf("Delete() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if _, err := r.Archive(ctx, 1); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if _, err := r.Archive(ctx, 1); err != domain.ErrTaskArchived {
+		t.Errorf("Archive() error = %v, want %v", err, domain.ErrTaskArchived)
+	}
+	if list, _ := r.List(ctx, domain.TaskFilter{}); !reflect.DeepEqual(names(list), []string{"task3"}) {
+		t.Errorf("List() = %v, want [task3]", names(list))
+	}
+	if _, err := r.Restore(ctx, 3); err != domain.ErrTaskNotDeleted {
+		t.Errorf("Restore() error = %v, want %v", err, domain.ErrTaskNotDeleted)
+	}
+	purged, _ := r.Purge(ctx, baseTime().Add(time.Hour))
+	if !reflect.DeepEqual(purged, []int64{2}) {
+		t.Errorf("Purge() = %v, want [2]", purged)
+	}
+	if _, err := r.Restore(ctx, 2); err != domain.ErrDataNotFound {
+		t.Errorf("Restore() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_taskRepository_Revert(t *testing.T) {
+	ctx := context.Background()
+	events := NewMemoryEventStore()
+	r := newTestRepository(t, events, NewMemorySnapshotStore(), 0)
+	createTasks(t, r, "task1")
+	completed, _ := r.Update(ctx, 1, domain.StatusComplete, "") // minute 2
+	_, _ = r.Update(ctx, 1, domain.StatusIncomplete, "changed")
+
+	got, err := r.Revert(ctx, 1, completed)
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if got.Status != domain.StatusComplete || got.Description != "" || !sameTime(got.CompletedAt, completed.CompletedAt) {
+		t.Errorf("Revert() = %v, want the completed task with its completion time %v", got, completed.CompletedAt)
+	}
+	restarted := newTestRepository(t, events, NewMemorySnapshotStore(), 0)
+	if task, _ := restarted.Get(ctx, 1); !sameTime(task.CompletedAt, completed.CompletedAt) {
+		t.Errorf("Get() after restart completedAt = %v, want %v", task.CompletedAt, completed.CompletedAt)
+	}
+}
+
+func Test_taskRepository_Checklist(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, NewMemoryEventStore(), NewMemorySnapshotStore(), 0)
+	createTasks(t, r, "task1")
+	_, _ = r.AddChecklistItem(ctx, 1, "first")
+	before, _ := r.AddChecklistItem(ctx, 1, "second")
+	if _, err := r.ReorderChecklist(ctx, 1, []int64{1, 1}); err != domain.ErrChecklistOrder {
+		t.Errorf("ReorderChecklist() error = %v, want %v", err, domain.ErrChecklistOrder)
+	}
+	_, _ = r.ReorderChecklist(ctx, 1, []int64{2, 1})
+	_, _ = r.ToggleChecklistItem(ctx, 1, 1)
+	got, err := r.RemoveChecklistItem(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("RemoveChecklistItem() error = %v", err)
+	}
+	want := []domain.ChecklistItem{{ID: 1, Text: "first", Checked: true}}
+	if !reflect.DeepEqual(got.Checklist, want) {
+		t.Errorf("Checklist = %v, want %v", got.Checklist, want)
+	}
+	if len(before.Checklist) != 2 || before.Checklist[0].Checked {
+		t.Errorf("earlier task observed later events: %v", before.Checklist)
+	}
+	if _, err := r.ToggleChecklistItem(ctx, 1, 9); err != domain.ErrChecklistNotFound {
+		t.Errorf("ToggleChecklistItem() error = %v, want %v", err, domain.ErrChecklistNotFound)
+	}
+}
+
+func Test_taskRepository_ChecklistIDs(t *testing.T) {
+	ctx := context.Background()
+	events, snapshots := NewMemoryEventStore(), NewMemorySnapshotStore()
+	r := newTestRepository(t, events, snapshots, 1)
+	createTasks(t, r, "task1")
+	_, _ = r.AddChecklistItem(ctx, 1, "first")
+	_, _ = r.AddChecklistItem(ctx, 1, "second")
+	_, _ = r.RemoveChecklistItem(ctx, 1, 2)
+
+	// The snapshot taken after every event has to carry the highest ID over a restart.
+	restarted := newTestRepository(t, events, snapshots, 1)
+	got, err := restarted.AddChecklistItem(ctx, 1, "third")
+	if err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	want := []domain.ChecklistItem{{ID: 1, Text: "first"}, {ID: 3, Text: "third"}}
+	if !reflect.DeepEqual(got.Checklist, want) {
+		t.Errorf("Checklist = %v, want %v", got.Checklist, want)
+	}
+}
+
+func Test_taskRepository_Restart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	open := func() *taskRepository {
+		events, err := NewFileEventStore(dir)
+		if err != nil {
+			t.Fatalf("NewFileEventStore() error = %v", err)
+		}
+		snapshots, err := NewFileSnapshotStore(dir)
+		if err != nil {
+			t.Fatalf("NewFileSnapshotStore() error = %v", err)
+		}
+		return newTestRepository(t, events, snapshots, 3)
+	}
+
+	r := open()
+	createTasks(t, r, "task1", "task2", "task3", "task4")
+	_, _ = r.Update(ctx, 2, domain.StatusComplete, "")
+	_ = r.Delete(ctx, 4)
+	want, _ := r.List(ctx, domain.TaskFilter{IncludeDeleted: true})
+	changes, _ := r.Changes(ctx, 0)
+	// Every commit carries an OutboxRecorded event along with the task event.
+	if snapshot, ok, _ := r.snapshots.Load(ctx); !ok || snapshot.Seq != 12 || snapshot.Epoch != changes.Epoch {
+		t.Fatalf("snapshot = %v, %v, %q, want seq 12 of epoch %q", snapshot.Seq, ok, snapshot.Epoch, changes.Epoch)
+	}
+
+	restarted := open()
+	got, _ := restarted.List(ctx, domain.TaskFilter{IncludeDeleted: true})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() after restart = %v, want %v", got, want)
+	}
+	// The sequence numbers carry on, so the sync tokens handed out before stay valid.
+	if got, _ := restarted.Changes(ctx, changes.Seq); changes.Epoch == "" || got.Epoch != changes.Epoch {
+		t.Errorf("Changes() Epoch after restart = %q, want %q", got.Epoch, changes.Epoch)
+	}
+	created, _ := restarted.Create(ctx, domain.Task{Name: "task5"})
+	if created.ID != 5 {
+		t.Errorf("Create() after restart got ID %d, want 5", created.ID)
+	}
+}
+
+func Test_fileEventStore_TornWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	open := func() *fileEventStore {
+		events, err := NewFileEventStore(dir)
+		if err != nil {
+			t.Fatalf("NewFileEventStore() error = %v", err)
+		}
+		return events
+	}
+	snapshots := NewMemorySnapshotStore()
+
+	r := newTestRepository(t, open(), snapshots, 0)
+	createTasks(t, r, "task1", "task2")
+	// A crash in the middle of a write leaves half an event behind.
+	f, err := os.OpenFile(filepath.Join(dir, "events.jsonl"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	_, _ = f.WriteString(`{"seq":5,"type":"TaskCre`)
+	_ = f.Close()
+
+	events := open()
+	restarted := newTestRepository(t, events, snapshots, 0)
+	if _, err := restarted.Create(ctx, domain.Task{Name: "task3"}); err != nil {
+		t.Fatalf("Create() after torn write error = %v", err)
+	}
+	got, _ := newTestRepository(t, open(), snapshots, 0).List(ctx, domain.TaskFilter{})
+	if len(got) != 3 {
+		t.Errorf("List() after torn write got %d tasks, want 3", len(got))
+	}
+
+	tail, err := events.Load(ctx, 4)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(tail) != 2 || tail[0].Seq != 5 || tail[0].Type != TaskCreated {
+		t.Errorf("Load(4) = %v, want the events of task3", tail)
+	}
+}
+
+func Test_taskRepository_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name          string
+		snapshotEvery int
+	}{
+		{
+			name:          "WithoutSnapshot",
+			snapshotEvery: 0,
+		},
+		{
+			name:          "WithSnapshot",
+			snapshotEvery: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(t, NewMemoryEventStore(), NewMemorySnapshotStore(), tt.snapshotEvery)
+			createTasks(t, r, "task1", "task2")                // minutes 1 and 2
+			_, _ = r.Update(ctx, 1, domain.StatusComplete, "") // minute 3
+			_ = r.Delete(ctx, 2)                               // minute 4
+
+			got, err := r.Rebuild(ctx, baseTime().Add(2*time.Minute))
+			if err != nil {
+				t.Fatalf("Rebuild() error = %v", err)
+			}
+			if len(got) != 2 || got[0].Status != domain.StatusIncomplete || got[1].DeletedAt != nil {
+				t.Errorf("Rebuild() at minute 2 = %v", got)
+			}
+			got, _ = r.Rebuild(ctx, baseTime().Add(90*time.Second))
+			if !reflect.DeepEqual(names(got), []string{"task1"}) {
+				t.Errorf("Rebuild() at minute 1.5 = %v, want [task1]", names(got))
+			}
+			got, _ = r.Rebuild(ctx, baseTime().Add(time.Hour))
+			if len(got) != 2 || got[0].Status != domain.StatusComplete || got[1].DeletedAt == nil {
+				t.Errorf("Rebuild() now = %v", got)
+			}
+			if got, _ := r.Rebuild(ctx, baseTime()); len(got) != 0 {
+				t.Errorf("Rebuild() before first event = %v, want none", got)
+			}
+		})
+	}
+}
+
+func Test_taskRepository_AsOf(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, NewMemoryEventStore(), NewMemorySnapshotStore(), 0)
+	createTasks(t, r, "task1", "task2")                // minutes 1 and 2
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "") // minute 3
+	_ = r.Delete(ctx, 2)                               // minute 4
+
+	tasks, err := r.ListAsOf(ctx, domain.TaskFilter{}, baseTime().Add(3*time.Minute))
+	if err != nil {
+		t.Fatalf("ListAsOf() error = %v", err)
+	}
+	if !reflect.DeepEqual(names(tasks), []string{"task1", "task2"}) {
+		t.Errorf("ListAsOf() = %v, want [task1 task2]", names(tasks))
+	}
+	if tasks, _ := r.ListAsOf(ctx, domain.TaskFilter{}, baseTime().Add(4*time.Minute)); !reflect.DeepEqual(names(tasks), []string{"task1"}) {
+		t.Errorf("ListAsOf() after delete = %v, want [task1]", names(tasks))
+	}
+	task, err := r.GetAsOf(ctx, 1, baseTime().Add(2*time.Minute))
+	if err != nil || task.Status != domain.StatusIncomplete {
+		t.Errorf("GetAsOf() = %v, %v, want incomplete task", task, err)
+	}
+	if _, err := r.GetAsOf(ctx, 2, baseTime().Add(4*time.Minute)); err != domain.ErrDataNotFound {
+		t.Errorf("GetAsOf() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+
+	// A purged task is gone at any time.
+	_, _ = r.Purge(ctx, baseTime().Add(time.Hour))
+	if tasks, _ := r.ListAsOf(ctx, domain.TaskFilter{}, baseTime().Add(3*time.Minute)); !reflect.DeepEqual(names(tasks), []string{"task1"}) {
+		t.Errorf("ListAsOf() after purge = %v, want [task1]", names(tasks))
+	}
+	if _, err := r.GetAsOf(ctx, 2, baseTime().Add(3*time.Minute)); err != domain.ErrDataNotFound {
+		t.Errorf("GetAsOf() after purge error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+// failingEventStore rejects every append.
+type failingEventStore struct {
+	EventStore
+}
+
+func (s failingEventStore) Append(ctx context.Context, events ...Event) error {
+	return errors.New("disk full")
+}
+
+func Test_taskRepository_CreateMany(t *testing.T) {
+	ctx := context.Background()
+	events := NewMemoryEventStore()
+	r := newTestRepository(t, events, NewMemorySnapshotStore(), 0)
+	createTasks(t, r, "task1")
+
+	got, err := r.CreateMany(ctx, []domain.Task{{Name: "task2"}, {Name: "task3", Checklist: []domain.ChecklistItem{{ID: 1, Text: "sub"}}}})
+	if err != nil {
+		t.Fatalf("CreateMany() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 || len(got[1].Checklist) != 1 {
+		t.Errorf("CreateMany() got = %v", got)
+	}
+	if list, _ := r.List(ctx, domain.TaskFilter{}); !reflect.DeepEqual(names(list), []string{"task1", "task2", "task3"}) {
+		t.Errorf("List() = %v", names(list))
+	}
+
+	r.events = failingEventStore{EventStore: events}
+	if _, err := r.CreateMany(ctx, []domain.Task{{Name: "task4"}, {Name: "task5"}}); err == nil {
+		t.Fatalf("CreateMany() error = nil, want an error")
+	}
+	if list, _ := r.List(ctx, domain.TaskFilter{}); len(list) != 3 {
+		t.Errorf("List() after failed CreateMany = %v", names(list))
+	}
+}
+
+func Test_taskRepository_commitRejected(t *testing.T) {
+	ctx := context.Background()
+	events := NewMemoryEventStore()
+	r := newTestRepository(t, events, NewMemorySnapshotStore(), 0)
+	createTasks(t, r, "task1")
+	stored, _ := events.Load(ctx, 0)
+
+	completed, _ := r.event(TaskStatusChanged, 1, statusChangedData{Status: domain.StatusComplete})
+	unknown, _ := r.event(TaskStatusChanged, 9, statusChangedData{Status: domain.StatusComplete})
+	if err := r.commit(ctx, completed, unknown); err == nil {
+		t.Fatalf("commit() error = nil, want an error")
+	}
+	if got, _ := events.Load(ctx, 0); len(got) != len(stored) {
+		t.Errorf("commit() stored %d events, want %d", len(got), len(stored))
+	}
+	if got, _ := r.Get(ctx, 1); got.Status != domain.StatusIncomplete {
+		t.Errorf("commit() changed the projection, status = %v", got.Status)
+	}
+	if _, err := r.Update(ctx, 1, domain.StatusComplete, ""); err != nil {
+		t.Errorf("Update() after rejected commit error = %v", err)
+	}
+}
+
+func Test_taskRepository_Changes(t *testing.T) {
+	r := newTestRepository(t, NewMemoryEventStore(), NewMemorySnapshotStore(), 2)
+	ctx := context.Background()
+	createTasks(t, r, "taskName1", "taskName2", "taskName3")
+	_ = r.Delete(ctx, 3)
+	first, _ := r.Changes(ctx, 0)
+
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "done")
+	_ = r.Delete(ctx, 2)
+	createTasks(t, r, "taskName4", "taskName5")
+	_ = r.Delete(ctx, 5)
+	_, _ = r.Restore(ctx, 3)
+	_, _ = r.Move(ctx, 4, 1, 0)
+	second, _ := r.Changes(ctx, first.Seq)
+
+	if got := names(first.Created); !reflect.DeepEqual(got, []string{"taskName1", "taskName2"}) {
+		t.Errorf("Changes(0) Created = %v", got)
+	}
+	if len(first.Updated) != 0 || len(first.Deleted) != 0 {
+		t.Errorf("Changes(0) = %+v, want created tasks only", first)
+	}
+	if got := names(second.Created); !reflect.DeepEqual(got, []string{"taskName4", "taskName3"}) {
+		t.Errorf("Changes() Created = %v, want the new and the restored task in rank order", got)
+	}
+	if got := names(second.Updated); !reflect.DeepEqual(got, []string{"taskName1"}) || second.Updated[0].Status != domain.StatusComplete {
+		t.Errorf("Changes() Updated = %+v", second.Updated)
+	}
+	if !reflect.DeepEqual(second.Deleted, []int64{2}) {
+		t.Errorf("Changes() Deleted = %v, want [2]", second.Deleted)
+	}
+
+	_, _ = r.Purge(ctx, baseTime().Add(24*time.Hour))
+	third, _ := r.Changes(ctx, second.Seq)
+	if len(third.Created)+len(third.Updated)+len(third.Deleted) != 0 || third.Seq <= second.Seq {
+		t.Errorf("Changes() after Purge() = %+v", third)
+	}
+	if got, _ := r.Changes(ctx, first.Seq); !reflect.DeepEqual(got.Deleted, []int64{2}) {
+		t.Errorf("Changes() Deleted after Purge() = %v, want [2]", got.Deleted)
+	}
+}
+
+func Test_taskRepository_Outbox(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	open := func() *taskRepository {
+		events, _ := NewFileEventStore(dir)
+		snapshots, _ := NewFileSnapshotStore(dir)
+		return newTestRepository(t, events, snapshots, 4)
+	}
+
+	r := open()
+	_, _ = r.CreateMany(ctx, []domain.Task{{Name: "task1"}, {Name: "task2"}})
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "done")
+	_ = r.Delete(ctx, 2)
+	_, _ = r.Restore(ctx, 2)
+	before, _ := r.Changes(ctx, 0)
+
+	got, _ := r.PendingOutbox(ctx, 10)
+	want := []string{"task.created 1", "task.created 2", "task.updated 1", "task.deleted 2", "task.created 2"}
+	if types := outboxTypes(got); !reflect.DeepEqual(types, want) {
+		t.Fatalf("PendingOutbox() = %v, want %v", types, want)
+	}
+	if got[0].Seq != got[1].Seq || got[0].ID == got[1].ID {
+		t.Errorf("PendingOutbox() CreateMany = %v, %v, want one Seq and distinct IDs", got[0], got[1])
+	}
+	if got[2].Before == nil || got[2].Before.Status != domain.StatusIncomplete || got[2].Task.Description != "done" {
+		t.Errorf("PendingOutbox() update = %+v, want the task before and after", got[2])
+	}
+	if limited, _ := r.PendingOutbox(ctx, 1); len(limited) != 2 {
+		t.Errorf("PendingOutbox(1) = %v, want the two messages of CreateMany", outboxTypes(limited))
+	}
+
+	if err := r.AckOutbox(ctx, got[2].Seq); err != nil {
+		t.Fatalf("AckOutbox() error = %v", err)
+	}
+	// The delivered mark is no task change.
+	if after, _ := r.Changes(ctx, before.Seq); len(after.Created)+len(after.Updated)+len(after.Deleted) != 0 {
+		t.Errorf("Changes() after AckOutbox() = %+v, want no changes", after)
+	}
+
+	restarted := open()
+	rest, _ := restarted.PendingOutbox(ctx, 10)
+	if !reflect.DeepEqual(rest, got[3:]) {
+		t.Errorf("PendingOutbox() after restart = %v, want %v", outboxTypes(rest), outboxTypes(got[3:]))
+	}
+	_ = restarted.AckOutbox(ctx, got[4].Seq)
+	if rest, _ := restarted.PendingOutbox(ctx, 10); len(rest) != 0 {
+		t.Errorf("PendingOutbox() after AckOutbox() = %v, want none", outboxTypes(rest))
+	}
+}
+
+// Test_taskRepository_Respread checks that moving a task between the same neighbours until
+// its rank is too long only reranks the neighbourhood, leaves tasks in the trash alone and
+// sends no outbox messages.
+func Test_taskRepository_Respread(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, NewMemoryEventStore(), NewMemorySnapshotStore(), 0)
+	for i := 1; i <= 100; i++ {
+		createTasks(t, r, fmt.Sprintf("task%d", i))
+	}
+	_ = r.Delete(ctx, 1)
+	trashed, _ := r.Get(ctx, 1)
+	far, _ := r.Get(ctx, 100)
+	pending, _ := r.PendingOutbox(ctx, 1000)
+	for i := 0; i < 200; i++ {
+		if _, err := r.Move(ctx, 3, 2, 0); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if _, err := r.Move(ctx, 2, 3, 0); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+	if got, _ := r.Get(ctx, 1); got.Rank != trashed.Rank {
+		t.Errorf("rank of the task in the trash = %q, want %q", got.Rank, trashed.Rank)
+	}
+	if got, _ := r.Get(ctx, 100); got.Rank != far.Rank {
+		t.Errorf("rank of a task far from the moves = %q, want %q", got.Rank, far.Rank)
+	}
+	if got, _ := r.PendingOutbox(ctx, 1000); len(got) != len(pending) {
+		t.Errorf("moves queued %d outbox messages, want none", len(got)-len(pending))
+	}
+	tasks, _ := r.List(ctx, domain.TaskFilter{})
+	if got := names(tasks[:3]); !reflect.DeepEqual(got, []string{"task2", "task3", "task4"}) {
+		t.Errorf("order after the moves = %v", got)
+	}
+	for _, task := range tasks {
+		if len(task.Rank) > 16 {
+			t.Errorf("rank %q of task %d is too long", task.Rank, task.ID)
+		}
+	}
+}
+
+func outboxTypes(messages []domain.OutboxMessage) []string {
+	rtn := make([]string, 0, len(messages))
+	for _, message := range messages {
+		rtn = append(rtn, fmt.Sprintf("%s %d", message.Type, message.Task.ID))
+	}
+	return rtn
+}
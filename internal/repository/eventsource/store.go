@@ -0,0 +1,281 @@
+package eventsource
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"oa-gogolook/internal/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventStore persists the event stream. Append must store all events or none of them.
+type EventStore interface {
+	Append(ctx context.Context, events ...Event) error
+	// Load returns the events with a sequence number above afterSeq, in order.
+	Load(ctx context.Context, afterSeq int64) ([]Event, error)
+}
+
+// Snapshot is the projected state right after the event with sequence number Seq.
+type Snapshot struct {
+	Seq    int64         `json:"seq"`
+	At     time.Time     `json:"at"`
+	LastID int64         `json:"lastId"`
+	Tasks  []domain.Task `json:"tasks"`
+	// Epoch is the epoch of the change feed, empty in the snapshots of older versions.
+	Epoch string `json:"epoch,omitempty"`
+	// OutboxSeq and OutboxDelivered are the sequence numbers of the last OutboxRecorded
+	// event and of the last delivered one.
+	OutboxSeq       int64 `json:"outboxSeq,omitempty"`
+	OutboxDelivered int64 `json:"outboxDelivered,omitempty"`
+	// ChecklistIDs is the highest checklist item ID every task ever had.
+	ChecklistIDs map[int64]int64 `json:"checklistIds,omitempty"`
+}
+
+// SnapshotStore keeps the latest snapshot, ok is false while none has been saved.
+type SnapshotStore interface {
+	Save(ctx context.Context, snapshot Snapshot) error
+	Load(ctx context.Context) (snapshot Snapshot, ok bool, err error)
+}
+
+type memoryEventStore struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewMemoryEventStore() *memoryEventStore {
+	return &memoryEventStore{}
+}
+
+func (s *memoryEventStore) Append(ctx context.Context, events ...Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *memoryEventStore) Load(ctx context.Context, afterSeq int64) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.events), func(i int) bool {
+		return s.events[i].Seq > afterSeq
+	})
+	return append(make([]Event, 0, len(s.events)-i), s.events[i:]...), nil
+}
+
+type memorySnapshotStore struct {
+	mu       sync.Mutex
+	snapshot *Snapshot
+}
+
+func NewMemorySnapshotStore() *memorySnapshotStore {
+	return &memorySnapshotStore{}
+}
+
+func (s *memorySnapshotStore) Save(ctx context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot = &snapshot
+	return nil
+}
+
+func (s *memorySnapshotStore) Load(ctx context.Context) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshot == nil {
+		return Snapshot{}, false, nil
+	}
+	return *s.snapshot, true, nil
+}
+
+// fileEventStore appends the events as JSON lines to a single file. It keeps the offset of
+// every event in memory, so a load only reads the events it returns.
+type fileEventStore struct {
+	mu   sync.Mutex
+	path string
+	// index lists the sequence number and file offset of every stored event in order, and
+	// size is where the next event goes. Both are read from the file on first use.
+	index   []eventOffset
+	size    int64
+	indexed bool
+}
+
+type eventOffset struct {
+	seq    int64
+	offset int64
+}
+
+func NewFileEventStore(dir string) (*fileEventStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileEventStore{
+		path: filepath.Join(dir, "events.jsonl"),
+	}, nil
+}
+
+// Append writes all events or, when the write fails, cuts the file back to where it was.
+func (s *fileEventStore) Append(ctx context.Context, events ...Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.readIndex(); err != nil {
+		return err
+	}
+	// Encode everything first so a marshalling error cannot leave half the batch behind.
+	var buf []byte
+	offsets := make([]eventOffset, 0, len(events))
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		offsets = append(offsets, eventOffset{seq: e.Seq, offset: s.size + int64(len(buf))})
+		buf = append(append(buf, line...), '\n')
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Truncate(s.size)
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Truncate(s.size)
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.index = append(s.index, offsets...)
+	s.size += int64(len(buf))
+	return nil
+}
+
+func (s *fileEventStore) Load(ctx context.Context, afterSeq int64) ([]Event, error) {
+	s.mu.Lock()
+	if err := s.readIndex(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	i := sort.Search(len(s.index), func(i int) bool {
+		return s.index[i].seq > afterSeq
+	})
+	events := make([]Event, 0, len(s.index)-i)
+	if i == len(s.index) {
+		s.mu.Unlock()
+		return events, nil
+	}
+	// Appends only write beyond size, so the events up to it can be read without the lock.
+	start, end := s.index[i].offset, s.size
+	s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(io.NewSectionReader(f, start, end-start))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// readIndex reads the offsets of the stored events once. A last line that does not parse is
+// the remainder of a write torn by a crash, it is cut off. The caller must hold s.mu.
+func (s *fileEventStore) readIndex() error {
+	if s.indexed {
+		return nil
+	}
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		s.indexed = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader := bufio.NewReaderSize(f, 64*1024)
+	var index []eventOffset
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		var e Event
+		if err != nil || json.Unmarshal(line, &e) != nil {
+			if _, next := reader.Peek(1); next != io.EOF {
+				return fmt.Errorf("event store %s: broken event at offset %d", s.path, offset)
+			}
+			if err := f.Truncate(offset); err != nil {
+				return err
+			}
+			break
+		}
+		index = append(index, eventOffset{seq: e.Seq, offset: offset})
+		offset += int64(len(line))
+	}
+	s.index = index
+	s.size = offset
+	s.indexed = true
+	return nil
+}
+
+// fileSnapshotStore keeps the latest snapshot in a JSON file, replaced atomically on save.
+type fileSnapshotStore struct {
+	path string
+}
+
+func NewFileSnapshotStore(dir string) (*fileSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileSnapshotStore{
+		path: filepath.Join(dir, "snapshot.json"),
+	}, nil
+}
+
+func (s *fileSnapshotStore) Save(ctx context.Context, snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *fileSnapshotStore) Load(ctx context.Context) (Snapshot, bool, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, false, err
+	}
+	return snapshot, true, nil
+}
@@ -0,0 +1,127 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type AttachmentStore struct {
+	Mu          *sync.Mutex
+	IDCounter   *TaskIDCounter
+	attachments map[int64]*domain.Attachment
+	now         func() time.Time
+}
+
+func (s *AttachmentStore) AddAttachment(attachment domain.Attachment) (domain.Attachment, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.attachments[attachment.ID]; ok {
+		return domain.Attachment{}, domain.ErrWrongID
+	}
+	attachment.CreatedAt = s.now()
+	s.attachments[attachment.ID] = &attachment
+	return *s.attachments[attachment.ID], nil
+}
+
+func (s *AttachmentStore) GetAttachment(id int64) (domain.Attachment, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.attachments[id]; !ok {
+		return domain.Attachment{}, domain.ErrDataNotFound
+	}
+	return *s.attachments[id], nil
+}
+
+func (s *AttachmentStore) DeleteAttachment(id int64) error {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.attachments[id]; !ok {
+		return domain.ErrDataNotFound
+	}
+	delete(s.attachments, id)
+	return nil
+}
+
+func (s *AttachmentStore) DeleteTaskAttachments(taskID int64) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	for id, attachment := range s.attachments {
+		if attachment.TaskID == taskID {
+			delete(s.attachments, id)
+		}
+	}
+}
+
+func (s *AttachmentStore) TaskAttachments(taskID int64) []domain.Attachment {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	attachments := make([]domain.Attachment, 0)
+	for _, attachment := range s.attachments {
+		if attachment.TaskID == taskID {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	return attachments
+}
+
+func NewAttachmentStore() *AttachmentStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &AttachmentStore{
+		Mu:          &mu2,
+		IDCounter:   NewTaskIDCounter(&mu1),
+		attachments: map[int64]*domain.Attachment{},
+		now:         time.Now,
+	}
+}
+
+type attachmentRepository struct {
+	store *AttachmentStore
+}
+
+func NewAttachmentRepository() *attachmentRepository {
+	return &attachmentRepository{
+		store: NewAttachmentStore(),
+	}
+}
+
+func (r *attachmentRepository) List(ctx context.Context, taskID int64) ([]domain.Attachment, error) {
+	attachments := r.store.TaskAttachments(taskID)
+	sort.SliceStable(attachments, func(i, j int) bool {
+		return attachments[i].ID < attachments[j].ID
+	})
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	attachment.ID = r.store.IDCounter.Next()
+	rtn, err := r.store.AddAttachment(attachment)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return rtn, nil
+}
+
+func (r *attachmentRepository) Get(ctx context.Context, id int64) (domain.Attachment, error) {
+	rtn, err := r.store.GetAttachment(id)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return rtn, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id int64) error {
+	err := r.store.DeleteAttachment(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *attachmentRepository) DeleteByTask(ctx context.Context, taskID int64) error {
+	r.store.DeleteTaskAttachments(taskID)
+	return nil
+}
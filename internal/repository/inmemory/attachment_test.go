@@ -0,0 +1,190 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func newTestAttachmentStore() *AttachmentStore {
+	store := NewAttachmentStore()
+	store.now = fixedNow
+	return store
+}
+
+func Test_attachmentRepository_Create(t *testing.T) {
+	r := &attachmentRepository{
+		store: newTestAttachmentStore(),
+	}
+	got, err := r.Create(context.Background(), domain.Attachment{
+		TaskID:      1,
+		FileName:    "a.txt",
+		ContentType: "text/plain",
+		Size:        3,
+		BlobKey:     "task-1/a",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	want := domain.Attachment{
+		ID:          1,
+		TaskID:      1,
+		FileName:    "a.txt",
+		ContentType: "text/plain",
+		Size:        3,
+		CreatedAt:   fixedNow(),
+		BlobKey:     "task-1/a",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Create() got = %v, want %v", got, want)
+	}
+}
+
+func Test_attachmentRepository_List(t *testing.T) {
+	type fields struct {
+		store *AttachmentStore
+	}
+	type args struct {
+		ctx    context.Context
+		taskID int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *AttachmentStore)
+		fields     fields
+		args       args
+		want       []domain.Attachment
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *AttachmentStore) {
+				id := store.IDCounter.Next()
+				store.attachments[id] = &domain.Attachment{ID: id, TaskID: 1, FileName: "a.txt"}
+				id = store.IDCounter.Next()
+				store.attachments[id] = &domain.Attachment{ID: id, TaskID: 2, FileName: "b.txt"}
+				id = store.IDCounter.Next()
+				store.attachments[id] = &domain.Attachment{ID: id, TaskID: 1, FileName: "c.txt"}
+			},
+			fields: fields{
+				store: newTestAttachmentStore(),
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskID: 1,
+			},
+			want: []domain.Attachment{
+				{ID: 1, TaskID: 1, FileName: "a.txt"},
+				{ID: 3, TaskID: 1, FileName: "c.txt"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OKEmpty",
+			buildStubs: func(store *AttachmentStore) {
+			},
+			fields: fields{
+				store: newTestAttachmentStore(),
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskID: 1,
+			},
+			want:    []domain.Attachment{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &attachmentRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.List(tt.args.ctx, tt.args.taskID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_attachmentRepository_Delete(t *testing.T) {
+	type fields struct {
+		store *AttachmentStore
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *AttachmentStore)
+		fields     fields
+		args       args
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *AttachmentStore) {
+				id := store.IDCounter.Next()
+				store.attachments[id] = &domain.Attachment{ID: id, TaskID: 1, FileName: "a.txt"}
+			},
+			fields: fields{
+				store: newTestAttachmentStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "NotExists",
+			buildStubs: func(store *AttachmentStore) {
+			},
+			fields: fields{
+				store: newTestAttachmentStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &attachmentRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			if err := r.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_attachmentRepository_DeleteByTask(t *testing.T) {
+	r := &attachmentRepository{
+		store: newTestAttachmentStore(),
+	}
+	for _, taskID := range []int64{1, 2, 1} {
+		id := r.store.IDCounter.Next()
+		r.store.attachments[id] = &domain.Attachment{ID: id, TaskID: taskID, FileName: "a.txt"}
+	}
+	if err := r.DeleteByTask(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteByTask() error = %v", err)
+	}
+	if got := r.store.TaskAttachments(1); len(got) != 0 {
+		t.Errorf("DeleteByTask() left %v", got)
+	}
+	if got := r.store.TaskAttachments(2); len(got) != 1 {
+		t.Errorf("DeleteByTask() removed attachments of another task, got = %v", got)
+	}
+}
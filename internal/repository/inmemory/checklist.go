@@ -0,0 +1,129 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+)
+
+// The checklist operations replace the checklist slice instead of editing it in place, so
+// tasks returned earlier never observe later changes.
+
+func (t *TaskStore) AddChecklistItem(id int64, text string) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	nextID := t.checklistIDs[id] + 1
+	for _, item := range task.Checklist {
+		if item.ID >= nextID {
+			nextID = item.ID + 1
+		}
+	}
+	t.checklistIDs[id] = nextID
+	checklist := make([]domain.ChecklistItem, 0, len(task.Checklist)+1)
+	checklist = append(checklist, task.Checklist...)
+	task.Checklist = append(checklist, domain.ChecklistItem{ID: nextID, Text: text})
+	t.record(task, t.now())
+	return *t.tasks[id], nil
+}
+
+func (t *TaskStore) ReorderChecklist(id int64, itemIDs []int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	if len(itemIDs) != len(task.Checklist) {
+		return domain.Task{}, domain.ErrChecklistOrder
+	}
+	items := make(map[int64]domain.ChecklistItem, len(task.Checklist))
+	for _, item := range task.Checklist {
+		items[item.ID] = item
+	}
+	checklist := make([]domain.ChecklistItem, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		item, ok := items[itemID]
+		if !ok {
+			return domain.Task{}, domain.ErrChecklistOrder
+		}
+		delete(items, itemID)
+		checklist = append(checklist, item)
+	}
+	task.Checklist = checklist
+	t.record(task, t.now())
+	return *t.tasks[id], nil
+}
+
+func (t *TaskStore) ToggleChecklistItem(id int64, itemID int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	checklist := make([]domain.ChecklistItem, len(task.Checklist))
+	copy(checklist, task.Checklist)
+	for i := range checklist {
+		if checklist[i].ID == itemID {
+			checklist[i].Checked = !checklist[i].Checked
+			task.Checklist = checklist
+			t.record(task, t.now())
+			return *t.tasks[id], nil
+		}
+	}
+	return domain.Task{}, domain.ErrChecklistNotFound
+}
+
+func (t *TaskStore) RemoveChecklistItem(id int64, itemID int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	for i, item := range task.Checklist {
+		if item.ID == itemID {
+			checklist := make([]domain.ChecklistItem, 0, len(task.Checklist)-1)
+			checklist = append(checklist, task.Checklist[:i]...)
+			task.Checklist = append(checklist, task.Checklist[i+1:]...)
+			t.record(task, t.now())
+			return *t.tasks[id], nil
+		}
+	}
+	return domain.Task{}, domain.ErrChecklistNotFound
+}
+
+func (r *taskRepository) AddChecklistItem(ctx context.Context, id int64, text string) (domain.Task, error) {
+	rtn, err := r.store.AddChecklistItem(id, text)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (domain.Task, error) {
+	rtn, err := r.store.ReorderChecklist(id, itemIDs)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) ToggleChecklistItem(ctx context.Context, id int64, itemID int64) (domain.Task, error) {
+	rtn, err := r.store.ToggleChecklistItem(id, itemID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) RemoveChecklistItem(ctx context.Context, id int64, itemID int64) (domain.Task, error) {
+	rtn, err := r.store.RemoveChecklistItem(id, itemID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
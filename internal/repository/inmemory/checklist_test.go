@@ -0,0 +1,137 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func newChecklistStore() *TaskStore {
+	store := NewTaskStore()
+	id := store.IDCounter.Next()
+	store.tasks[id] = &domain.Task{
+		ID:     id,
+		Status: domain.StatusIncomplete,
+		Name:   "taskName1",
+		Checklist: []domain.ChecklistItem{
+			{ID: 1, Text: "item1"},
+			{ID: 2, Text: "item2", Checked: true},
+			{ID: 3, Text: "item3"},
+		},
+	}
+	return store
+}
+
+func Test_taskRepository_AddChecklistItem(t *testing.T) {
+	r := &taskRepository{store: newChecklistStore()}
+	got, err := r.AddChecklistItem(context.Background(), 1, "item4")
+	if err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	want := domain.ChecklistItem{ID: 4, Text: "item4"}
+	if len(got.Checklist) != 4 || !reflect.DeepEqual(got.Checklist[3], want) {
+		t.Errorf("AddChecklistItem() got = %v", got.Checklist)
+	}
+	if _, err := r.AddChecklistItem(context.Background(), 5, "item"); err != domain.ErrDataNotFound {
+		t.Errorf("AddChecklistItem() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_taskRepository_ReorderChecklist(t *testing.T) {
+	tests := []struct {
+		name    string
+		itemIDs []int64
+		want    []int64
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			itemIDs: []int64{3, 1, 2},
+			want:    []int64{3, 1, 2},
+			wantErr: nil,
+		},
+		{
+			name:    "MissingItem",
+			itemIDs: []int64{3, 1},
+			want:    []int64{1, 2, 3},
+			wantErr: domain.ErrChecklistOrder,
+		},
+		{
+			name:    "DuplicateItem",
+			itemIDs: []int64{3, 3, 1},
+			want:    []int64{1, 2, 3},
+			wantErr: domain.ErrChecklistOrder,
+		},
+		{
+			name:    "UnknownItem",
+			itemIDs: []int64{3, 1, 9},
+			want:    []int64{1, 2, 3},
+			wantErr: domain.ErrChecklistOrder,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &taskRepository{store: newChecklistStore()}
+			_, err := r.ReorderChecklist(context.Background(), 1, tt.itemIDs)
+			if err != tt.wantErr {
+				t.Fatalf("ReorderChecklist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			task, _ := r.Get(context.Background(), 1)
+			got := make([]int64, 0, len(task.Checklist))
+			for _, item := range task.Checklist {
+				got = append(got, item.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReorderChecklist() order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepository_ToggleChecklistItem(t *testing.T) {
+	r := &taskRepository{store: newChecklistStore()}
+	before, _ := r.Get(context.Background(), 1)
+	got, err := r.ToggleChecklistItem(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("ToggleChecklistItem() error = %v", err)
+	}
+	if !got.Checklist[0].Checked {
+		t.Errorf("ToggleChecklistItem() did not check item 1")
+	}
+	if before.Checklist[0].Checked {
+		t.Errorf("ToggleChecklistItem() changed a previously returned task")
+	}
+	if _, err := r.ToggleChecklistItem(context.Background(), 1, 9); err != domain.ErrChecklistNotFound {
+		t.Errorf("ToggleChecklistItem() error = %v, want %v", err, domain.ErrChecklistNotFound)
+	}
+}
+
+func Test_taskRepository_RemoveChecklistItem(t *testing.T) {
+	r := &taskRepository{store: newChecklistStore()}
+	got, err := r.RemoveChecklistItem(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("RemoveChecklistItem() error = %v", err)
+	}
+	want := []domain.ChecklistItem{{ID: 1, Text: "item1"}, {ID: 3, Text: "item3"}}
+	if !reflect.DeepEqual(got.Checklist, want) {
+		t.Errorf("RemoveChecklistItem() got = %v, want %v", got.Checklist, want)
+	}
+	if _, err := r.RemoveChecklistItem(context.Background(), 1, 2); err != domain.ErrChecklistNotFound {
+		t.Errorf("RemoveChecklistItem() error = %v, want %v", err, domain.ErrChecklistNotFound)
+	}
+}
+
+func Test_taskRepository_AddChecklistItemAfterRemove(t *testing.T) {
+	r := &taskRepository{store: newChecklistStore()}
+	_, _ = r.AddChecklistItem(context.Background(), 1, "item4")
+	_, _ = r.RemoveChecklistItem(context.Background(), 1, 4)
+	_, _ = r.RemoveChecklistItem(context.Background(), 1, 3)
+	got, err := r.AddChecklistItem(context.Background(), 1, "item5")
+	if err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	if last := got.Checklist[len(got.Checklist)-1]; last.ID != 5 {
+		t.Errorf("AddChecklistItem() reused item ID %d", last.ID)
+	}
+}
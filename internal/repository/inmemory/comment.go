@@ -0,0 +1,162 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type CommentStore struct {
+	Mu        *sync.Mutex
+	IDCounter *TaskIDCounter
+	comments  map[int64]*domain.Comment
+	now       func() time.Time
+}
+
+func (s *CommentStore) AddComment(comment domain.Comment) (domain.Comment, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.comments[comment.ID]; ok {
+		return domain.Comment{}, domain.ErrWrongID
+	}
+	comment.CreatedAt = s.now()
+	comment.UpdatedAt = comment.CreatedAt
+	s.comments[comment.ID] = &comment
+	return *s.comments[comment.ID], nil
+}
+
+func (s *CommentStore) GetComment(id int64) (domain.Comment, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.comments[id]; !ok {
+		return domain.Comment{}, domain.ErrDataNotFound
+	}
+	return *s.comments[id], nil
+}
+
+func (s *CommentStore) UpdateComment(id int64, body string) (domain.Comment, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.comments[id]; !ok {
+		return domain.Comment{}, domain.ErrDataNotFound
+	}
+	comment := s.comments[id]
+	comment.Body = body
+	comment.UpdatedAt = s.now()
+	return *s.comments[id], nil
+}
+
+func (s *CommentStore) DeleteComment(id int64) error {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.comments[id]; !ok {
+		return domain.ErrDataNotFound
+	}
+	delete(s.comments, id)
+	return nil
+}
+
+func (s *CommentStore) DeleteTaskComments(taskID int64) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	for id, comment := range s.comments {
+		if comment.TaskID == taskID {
+			delete(s.comments, id)
+		}
+	}
+}
+
+func (s *CommentStore) TaskComments(taskID int64) []domain.Comment {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	comments := make([]domain.Comment, 0)
+	for _, comment := range s.comments {
+		if comment.TaskID == taskID {
+			comments = append(comments, *comment)
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].ID < comments[j].ID
+	})
+	return comments
+}
+
+func NewCommentStore() *CommentStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &CommentStore{
+		Mu:        &mu2,
+		IDCounter: NewTaskIDCounter(&mu1),
+		comments:  map[int64]*domain.Comment{},
+		now:       time.Now,
+	}
+}
+
+type commentRepository struct {
+	store *CommentStore
+}
+
+func NewCommentRepository() *commentRepository {
+	return &commentRepository{
+		store: NewCommentStore(),
+	}
+}
+
+func (r *commentRepository) List(ctx context.Context, taskID int64, offset int, limit int) ([]domain.Comment, int, error) {
+	comments := r.store.TaskComments(taskID)
+	total := len(comments)
+	if offset >= total {
+		return make([]domain.Comment, 0), total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return comments[offset:end], total, nil
+}
+
+func (r *commentRepository) Create(ctx context.Context, taskID int64, authorID int64, body string) (domain.Comment, error) {
+	id := r.store.IDCounter.Next()
+	comment := domain.Comment{
+		ID:       id,
+		TaskID:   taskID,
+		AuthorID: authorID,
+		Body:     body,
+	}
+	rtn, err := r.store.AddComment(comment)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return rtn, nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, id int64, body string) (domain.Comment, error) {
+	rtn, err := r.store.UpdateComment(id, body)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return rtn, nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id int64) error {
+	err := r.store.DeleteComment(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *commentRepository) Get(ctx context.Context, id int64) (domain.Comment, error) {
+	rtn, err := r.store.GetComment(id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return rtn, nil
+}
+
+func (r *commentRepository) DeleteByTask(ctx context.Context, taskID int64) error {
+	r.store.DeleteTaskComments(taskID)
+	return nil
+}
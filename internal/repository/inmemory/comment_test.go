@@ -0,0 +1,329 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func fixedNow() time.Time {
+	return time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func newTestCommentStore() *CommentStore {
+	store := NewCommentStore()
+	store.now = fixedNow
+	return store
+}
+
+func Test_commentRepository_Create(t *testing.T) {
+	type fields struct {
+		store *CommentStore
+	}
+	type args struct {
+		ctx      context.Context
+		taskID   int64
+		authorID int64
+		body     string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    domain.Comment
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx:      context.Background(),
+				taskID:   1,
+				authorID: 2,
+				body:     "comment body",
+			},
+			want: domain.Comment{
+				ID:        1,
+				TaskID:    1,
+				AuthorID:  2,
+				Body:      "comment body",
+				CreatedAt: fixedNow(),
+				UpdatedAt: fixedNow(),
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &commentRepository{
+				store: tt.fields.store,
+			}
+			got, err := r.Create(tt.args.ctx, tt.args.taskID, tt.args.authorID, tt.args.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_List(t *testing.T) {
+	type fields struct {
+		store *CommentStore
+	}
+	type args struct {
+		ctx    context.Context
+		taskID int64
+		offset int
+		limit  int
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *CommentStore)
+		fields     fields
+		args       args
+		wantIDs    []int64
+		wantTotal  int
+		wantErr    bool
+	}{
+		{
+			name: "FirstPage",
+			buildStubs: func(store *CommentStore) {
+				for i := 0; i < 5; i++ {
+					id := store.IDCounter.Next()
+					store.comments[id] = &domain.Comment{ID: id, TaskID: 1, Body: "body"}
+				}
+				id := store.IDCounter.Next()
+				store.comments[id] = &domain.Comment{ID: id, TaskID: 2, Body: "body"}
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskID: 1,
+				offset: 0,
+				limit:  2,
+			},
+			wantIDs:   []int64{1, 2},
+			wantTotal: 5,
+			wantErr:   false,
+		},
+		{
+			name: "LastPage",
+			buildStubs: func(store *CommentStore) {
+				for i := 0; i < 5; i++ {
+					id := store.IDCounter.Next()
+					store.comments[id] = &domain.Comment{ID: id, TaskID: 1, Body: "body"}
+				}
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskID: 1,
+				offset: 4,
+				limit:  2,
+			},
+			wantIDs:   []int64{5},
+			wantTotal: 5,
+			wantErr:   false,
+		},
+		{
+			name: "OutOfRange",
+			buildStubs: func(store *CommentStore) {
+				id := store.IDCounter.Next()
+				store.comments[id] = &domain.Comment{ID: id, TaskID: 1, Body: "body"}
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskID: 1,
+				offset: 20,
+				limit:  20,
+			},
+			wantIDs:   []int64{},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &commentRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, total, err := r.List(tt.args.ctx, tt.args.taskID, tt.args.offset, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			gotIDs := make([]int64, 0, len(got))
+			for _, comment := range got {
+				gotIDs = append(gotIDs, comment.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("List() got = %v, want %v", gotIDs, tt.wantIDs)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("List() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_Update(t *testing.T) {
+	edited := fixedNow().Add(time.Hour)
+	type fields struct {
+		store *CommentStore
+	}
+	type args struct {
+		ctx  context.Context
+		id   int64
+		body string
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *CommentStore)
+		fields     fields
+		args       args
+		want       domain.Comment
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *CommentStore) {
+				id := store.IDCounter.Next()
+				store.comments[id] = &domain.Comment{ID: id, TaskID: 1, AuthorID: 1, Body: "body", CreatedAt: fixedNow(), UpdatedAt: fixedNow()}
+				store.now = func() time.Time { return edited }
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx:  context.Background(),
+				id:   1,
+				body: "edited",
+			},
+			want:    domain.Comment{ID: 1, TaskID: 1, AuthorID: 1, Body: "edited", CreatedAt: fixedNow(), UpdatedAt: edited},
+			wantErr: false,
+		},
+		{
+			name: "NotExists",
+			buildStubs: func(store *CommentStore) {
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx:  context.Background(),
+				id:   1,
+				body: "edited",
+			},
+			want:    domain.Comment{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &commentRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.Update(tt.args.ctx, tt.args.id, tt.args.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Update() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_Delete(t *testing.T) {
+	type fields struct {
+		store *CommentStore
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *CommentStore)
+		fields     fields
+		args       args
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *CommentStore) {
+				id := store.IDCounter.Next()
+				store.comments[id] = &domain.Comment{ID: id, TaskID: 1, Body: "body"}
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "NotExists",
+			buildStubs: func(store *CommentStore) {
+			},
+			fields: fields{
+				store: newTestCommentStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &commentRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			if err := r.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_DeleteByTask(t *testing.T) {
+	r := &commentRepository{
+		store: newTestCommentStore(),
+	}
+	_, _ = r.Create(context.Background(), 1, 1, "body1")
+	_, _ = r.Create(context.Background(), 2, 1, "body2")
+	_, _ = r.Create(context.Background(), 1, 1, "body3")
+
+	if err := r.DeleteByTask(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteByTask() error = %v", err)
+	}
+	if _, total, _ := r.List(context.Background(), 1, 0, 10); total != 0 {
+		t.Errorf("DeleteByTask() left %d comments on task 1", total)
+	}
+	if _, total, _ := r.List(context.Background(), 2, 0, 10); total != 1 {
+		t.Errorf("DeleteByTask() removed comments of task 2")
+	}
+}
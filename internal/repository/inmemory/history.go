@@ -0,0 +1,63 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sync"
+	"time"
+)
+
+// HistoryStore keeps the entries of every task in the order they were appended, entries are
+// never changed once stored.
+type HistoryStore struct {
+	Mu        *sync.Mutex
+	IDCounter *TaskIDCounter
+	entries   map[int64][]domain.HistoryEntry
+	now       func() time.Time
+}
+
+func (s *HistoryStore) AppendEntry(entry domain.HistoryEntry) domain.HistoryEntry {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	entry.Timestamp = s.now()
+	s.entries[entry.TaskID] = append(s.entries[entry.TaskID], entry)
+	return entry
+}
+
+func (s *HistoryStore) TaskEntries(taskID int64) []domain.HistoryEntry {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	entries := make([]domain.HistoryEntry, len(s.entries[taskID]))
+	copy(entries, s.entries[taskID])
+	return entries
+}
+
+func NewHistoryStore() *HistoryStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &HistoryStore{
+		Mu:        &mu2,
+		IDCounter: NewTaskIDCounter(&mu1),
+		entries:   map[int64][]domain.HistoryEntry{},
+		now:       time.Now,
+	}
+}
+
+type historyRepository struct {
+	store *HistoryStore
+}
+
+func NewHistoryRepository() *historyRepository {
+	return &historyRepository{
+		store: NewHistoryStore(),
+	}
+}
+
+func (r *historyRepository) Append(ctx context.Context, entry domain.HistoryEntry) (domain.HistoryEntry, error) {
+	entry.ID = r.store.IDCounter.Next()
+	return r.store.AppendEntry(entry), nil
+}
+
+func (r *historyRepository) List(ctx context.Context, taskID int64) ([]domain.HistoryEntry, error) {
+	return r.store.TaskEntries(taskID), nil
+}
@@ -0,0 +1,75 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func Test_historyRepository_Append(t *testing.T) {
+	store := NewHistoryStore()
+	store.now = fixedNow
+	r := &historyRepository{
+		store: store,
+	}
+	actorID := int64(7)
+	got, err := r.Append(context.Background(), domain.HistoryEntry{
+		TaskID:  1,
+		Action:  domain.HistoryActionUpdate,
+		ActorID: &actorID,
+		Changes: []domain.FieldChange{{Field: "status", From: domain.StatusIncomplete, To: domain.StatusComplete}},
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	want := domain.HistoryEntry{
+		ID:        1,
+		TaskID:    1,
+		Action:    domain.HistoryActionUpdate,
+		ActorID:   &actorID,
+		Timestamp: fixedNow(),
+		Changes:   []domain.FieldChange{{Field: "status", From: domain.StatusIncomplete, To: domain.StatusComplete}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Append() got = %v, want %v", got, want)
+	}
+}
+
+func Test_historyRepository_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskID  int64
+		wantIDs []int64
+	}{
+		{
+			name:    "OK",
+			taskID:  1,
+			wantIDs: []int64{1, 3},
+		},
+		{
+			name:    "Empty",
+			taskID:  9,
+			wantIDs: []int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewHistoryRepository()
+			_, _ = r.Append(context.Background(), domain.HistoryEntry{TaskID: 1, Action: domain.HistoryActionCreate})
+			_, _ = r.Append(context.Background(), domain.HistoryEntry{TaskID: 2, Action: domain.HistoryActionCreate})
+			_, _ = r.Append(context.Background(), domain.HistoryEntry{TaskID: 1, Action: domain.HistoryActionDelete})
+			got, err := r.List(context.Background(), tt.taskID)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			ids := make([]int64, 0, len(got))
+			for _, entry := range got {
+				ids = append(ids, entry.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("List() got = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
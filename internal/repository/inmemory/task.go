@@ -3,8 +3,11 @@ package inmemory
 import (
 	"context"
 	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/rank"
 	"sort"
+	"strconv"
 	"sync"
+	"time"
 )
 
 type TaskIDCounter struct {
@@ -30,6 +33,149 @@ type TaskStore struct {
 	Mu        *sync.Mutex
 	IDCounter *TaskIDCounter
 	tasks     map[int64]*domain.Task
+	now       func() time.Time
+	// versions keeps the states of every task, oldest first, for reads as of a past time
+	// and the change feed. A purged task is left with a single marker with a nil task, so
+	// it can not be read as of any time and the change feed still reports it as deleted.
+	versions map[int64][]taskVersion
+	// retention is how long versions are kept once they have been replaced, and the
+	// markers of purged tasks once they have been purged, 0 keeps them forever. horizon is
+	// the earliest time reads as of a past time can still answer, and prunedSeq the earliest
+	// sequence number the change feed can still answer changes after.
+	retention time.Duration
+	horizon   time.Time
+	prunedSeq int64
+	// seq is the sequence number of the last version. The numbers start over with the
+	// process, epoch tells them apart.
+	seq   int64
+	epoch string
+	// outbox holds the messages of the versions not delivered yet, oldest first.
+	outbox []domain.OutboxMessage
+	// checklistIDs is the last checklist item ID handed out per task, it only goes up so
+	// the ID of a removed item is never reused.
+	checklistIDs map[int64]int64
+}
+
+type taskVersion struct {
+	seq  int64
+	at   time.Time
+	task *domain.Task
+	// liveFrom and liveUntil are the sequence numbers between which the task of a purge
+	// marker may have been live.
+	liveFrom  int64
+	liveUntil int64
+}
+
+// record stores the state of the task as the version valid from at, along with its outbox
+// message. The caller must hold t.Mu.
+func (t *TaskStore) record(task *domain.Task, at time.Time) {
+	c := *task
+	t.seq++
+	var before *domain.Task
+	if versions := t.versions[task.ID]; len(versions) > 0 {
+		before = versions[len(versions)-1].task
+	}
+	t.versions[task.ID] = append(t.versions[task.ID], taskVersion{seq: t.seq, at: at, task: &c})
+	t.prune(task.ID, at)
+	if message, ok := domain.NewOutboxMessage(t.seq, at, before, &c); ok {
+		t.outbox = append(t.outbox, message)
+	}
+}
+
+// recordPurge drops the versions of the task and marks it as gone. The caller must hold
+// t.Mu.
+func (t *TaskStore) recordPurge(id int64, at time.Time) {
+	versions := t.versions[id]
+	t.seq++
+	t.versions[id] = []taskVersion{{
+		seq:       t.seq,
+		at:        at,
+		liveFrom:  versions[0].seq,
+		liveUntil: versions[len(versions)-1].seq,
+	}}
+}
+
+// prune drops the versions of the task replaced more than the retention before now, except
+// the one valid at that moment. The caller must hold t.Mu.
+func (t *TaskStore) prune(id int64, now time.Time) {
+	if t.retention <= 0 {
+		return
+	}
+	cutoff := now.Add(-t.retention)
+	versions := t.versions[id]
+	keep := 0
+	for keep+1 < len(versions) && !versions[keep+1].at.After(cutoff) {
+		keep++
+	}
+	if keep == 0 {
+		return
+	}
+	first := versions[keep]
+	t.versions[id] = append([]taskVersion(nil), versions[keep:]...)
+	if first.at.After(t.horizon) {
+		t.horizon = first.at
+	}
+	if first.seq > t.prunedSeq {
+		t.prunedSeq = first.seq
+	}
+}
+
+// pruneMarkers drops the markers of the tasks purged more than the retention before now.
+// The caller must hold t.Mu.
+func (t *TaskStore) pruneMarkers(now time.Time) {
+	if t.retention <= 0 {
+		return
+	}
+	cutoff := now.Add(-t.retention)
+	for id, versions := range t.versions {
+		last := versions[len(versions)-1]
+		if last.task == nil && !last.at.After(cutoff) {
+			delete(t.versions, id)
+			if last.seq > t.prunedSeq {
+				t.prunedSeq = last.seq
+			}
+		}
+	}
+}
+
+// visibleAt reports whether the task was live right after the version with sequence
+// number seq. The caller must hold t.Mu.
+func (t *TaskStore) visibleAt(id int64, seq int64) bool {
+	versions := t.versions[id]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].seq <= seq {
+			return versions[i].task != nil && versions[i].task.DeletedAt == nil
+		}
+	}
+	if len(versions) == 1 && versions[0].task == nil {
+		return versions[0].liveFrom <= seq && seq < versions[0].liveUntil
+	}
+	return false
+}
+
+// versionAt returns the task as it was at the given time, ok is false if it did not exist
+// or had been purged. The caller must hold t.Mu.
+func (t *TaskStore) versionAt(id int64, at time.Time) (domain.Task, bool) {
+	versions := t.versions[id]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].at.After(at) {
+			if versions[i].task == nil {
+				return domain.Task{}, false
+			}
+			return *versions[i].task, true
+		}
+	}
+	return domain.Task{}, false
+}
+
+// live returns the task unless it does not exist or sits in the trash. The caller must
+// hold t.Mu.
+func (t *TaskStore) live(id int64) (*domain.Task, bool) {
+	task, ok := t.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return nil, false
+	}
+	return task, true
 }
 
 func (t *TaskStore) AddTask(task domain.Task) (domain.Task, error) {
@@ -38,31 +184,465 @@ func (t *TaskStore) AddTask(task domain.Task) (domain.Task, error) {
 	if _, ok := t.tasks[task.ID]; ok {
 		return domain.Task{}, domain.ErrWrongID
 	}
+	if task.Rank == "" {
+		last := ""
+		for _, existing := range t.tasks {
+			if existing.Rank > last {
+				last = existing.Rank
+			}
+		}
+		task.Rank = rank.After(last)
+	}
+	now := t.now()
+	task.CreatedAt = now
 	t.tasks[task.ID] = &task
+	if len(task.Rank) > rank.MaxLength {
+		t.respread(task.ID)
+	} else {
+		t.record(&task, now)
+	}
 	return *t.tasks[task.ID], nil
 }
 
+// AddTasks adds all the tasks or none of them, each one ranked after the one before.
+func (t *TaskStore) AddTasks(tasks []domain.Task) ([]domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	for _, task := range tasks {
+		if _, ok := t.tasks[task.ID]; ok {
+			return nil, domain.ErrWrongID
+		}
+	}
+	last := ""
+	for _, existing := range t.tasks {
+		if existing.Rank > last {
+			last = existing.Rank
+		}
+	}
+	tooLong := false
+	ids := make([]int64, 0, len(tasks))
+	now := t.now()
+	for i := range tasks {
+		task := tasks[i]
+		task.CreatedAt = now
+		task.Rank = rank.After(last)
+		last = task.Rank
+		tooLong = tooLong || len(task.Rank) > rank.MaxLength
+		t.tasks[task.ID] = &task
+		ids = append(ids, task.ID)
+	}
+	if tooLong {
+		t.respread(ids...)
+	} else {
+		for _, task := range tasks {
+			t.record(t.tasks[task.ID], now)
+		}
+	}
+	rtn := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		rtn = append(rtn, *t.tasks[task.ID])
+	}
+	return rtn, nil
+}
+
+// MoveTask ranks the task right before beforeID, or right after afterID when beforeID is 0.
+func (t *TaskStore) MoveTask(id int64, beforeID int64, afterID int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	refID := beforeID
+	if refID == 0 {
+		refID = afterID
+	}
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if _, ok := t.live(refID); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	for _, task := range t.tasks {
+		if task.Rank == "" && task.DeletedAt == nil {
+			t.respread()
+			break
+		}
+	}
+
+	ordered := make([]*domain.Task, 0, len(t.tasks))
+	for _, task := range t.tasks {
+		if task.ID != id && task.DeletedAt == nil {
+			ordered = append(ordered, task)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rankLess(*ordered[i], *ordered[j])
+	})
+	pos := 0
+	for pos < len(ordered) && ordered[pos].ID != refID {
+		pos++
+	}
+	if beforeID == 0 {
+		pos++
+	}
+	lower, upper := "", ""
+	if pos > 0 {
+		lower = ordered[pos-1].Rank
+	}
+	if pos < len(ordered) {
+		upper = ordered[pos].Rank
+	}
+
+	task := t.tasks[id]
+	task.Rank = rank.Between(lower, upper)
+	if len(task.Rank) > rank.MaxLength {
+		t.respread(id)
+	} else {
+		t.record(task, t.now())
+	}
+	return *t.tasks[id], nil
+}
+
+// respread gives the tasks with the given IDs, and as few of their neighbours as needed,
+// short ranks again while keeping their order; without IDs it respreads every task. Only
+// live tasks are ranked, a task in the trash keeps its rank. Only the tasks whose rank
+// changes get a new version. The caller must hold t.Mu.
+func (t *TaskStore) respread(ids ...int64) {
+	ordered := make([]*domain.Task, 0, len(t.tasks))
+	for _, task := range t.tasks {
+		if task.DeletedAt == nil {
+			ordered = append(ordered, task)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rankLess(*ordered[i], *ordered[j])
+	})
+	wanted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	lo, hi := len(ordered), 0
+	ranks := make([]string, len(ordered))
+	for i, task := range ordered {
+		ranks[i] = task.Rank
+		if wanted[task.ID] {
+			if i < lo {
+				lo = i
+			}
+			hi = i + 1
+		}
+	}
+	if lo >= hi {
+		lo, hi = 0, len(ordered)
+	}
+	start, spread := rank.Respread(ranks, lo, hi)
+	now := t.now()
+	for i, r := range spread {
+		task := ordered[start+i]
+		if task.Rank != r || wanted[task.ID] {
+			task.Rank = r
+			t.record(task, now)
+		}
+	}
+}
+
+func rankLess(a domain.Task, b domain.Task) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.ID < b.ID
+}
+
+func sortTasks(tasks []domain.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return rankLess(tasks[i], tasks[j])
+	})
+}
+
+// DeleteTask moves the task to the trash, it keeps its rank so a restore puts it back in place.
 func (t *TaskStore) DeleteTask(id int64) error {
 	t.Mu.Lock()
 	defer t.Mu.Unlock()
-	if _, ok := t.tasks[id]; !ok {
+	task, ok := t.live(id)
+	if !ok {
 		return domain.ErrDataNotFound
 	}
-	delete(t.tasks, id)
+	deletedAt := t.now()
+	task.DeletedAt = &deletedAt
+	t.record(task, deletedAt)
 	return nil
 }
 
-func (t *TaskStore) UpdateTask(id int64, status domain.Status) (domain.Task, error) {
+func (t *TaskStore) RestoreTask(id int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	task, ok := t.tasks[id]
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if task.DeletedAt == nil {
+		return domain.Task{}, domain.ErrTaskNotDeleted
+	}
+	task.DeletedAt = nil
+	t.record(task, t.now())
+	return *task, nil
+}
+
+// PurgeTasks permanently removes the tasks moved to the trash before deletedBefore and
+// returns their IDs.
+func (t *TaskStore) PurgeTasks(deletedBefore time.Time) []int64 {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	now := t.now()
+	ids := make([]int64, 0)
+	for id, task := range t.tasks {
+		if task.DeletedAt != nil && task.DeletedAt.Before(deletedBefore) {
+			delete(t.tasks, id)
+			t.recordPurge(id, now)
+			delete(t.checklistIDs, id)
+			ids = append(ids, id)
+		}
+	}
+	t.pruneMarkers(now)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (t *TaskStore) ArchiveTask(id int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	task, ok := t.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if task.ArchivedAt != nil {
+		return domain.Task{}, domain.ErrTaskArchived
+	}
+	archivedAt := t.now()
+	task.ArchivedAt = &archivedAt
+	t.record(task, archivedAt)
+	return *task, nil
+}
+
+func (t *TaskStore) UnarchiveTask(id int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	task, ok := t.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	if task.ArchivedAt == nil {
+		return domain.Task{}, domain.ErrTaskNotArchived
+	}
+	task.ArchivedAt = nil
+	t.record(task, t.now())
+	return *task, nil
+}
+
+// ArchiveCompleted archives the completed tasks whose completion is older than
+// completedBefore and returns their IDs.
+func (t *TaskStore) ArchiveCompleted(completedBefore time.Time) []int64 {
 	t.Mu.Lock()
 	defer t.Mu.Unlock()
-	if _, ok := t.tasks[id]; !ok {
+	archivedAt := t.now()
+	ids := make([]int64, 0)
+	for id, task := range t.tasks {
+		if task.DeletedAt != nil || task.ArchivedAt != nil || task.Status != domain.StatusComplete {
+			continue
+		}
+		if task.CompletedAt != nil && task.CompletedAt.Before(completedBefore) {
+			task.ArchivedAt = &archivedAt
+			t.record(task, archivedAt)
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (t *TaskStore) GetTask(id int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	task, ok := t.live(id)
+	if !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	return *task, nil
+}
+
+func (t *TaskStore) GetTaskAsOf(id int64, at time.Time) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if at.Before(t.horizon) {
+		return domain.Task{}, domain.ErrHistoryExpired
+	}
+	task, ok := t.versionAt(id, at)
+	if !ok || task.DeletedAt != nil {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	return task, nil
+}
+
+func (t *TaskStore) TasksAsOf(filter domain.TaskFilter, at time.Time) ([]domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if at.Before(t.horizon) {
+		return nil, domain.ErrHistoryExpired
+	}
+	tasks := make([]domain.Task, 0, len(t.versions))
+	for id := range t.versions {
+		task, ok := t.versionAt(id, at)
+		if !ok || !filter.Match(task) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (t *TaskStore) Tasks(filter domain.TaskFilter) []domain.Task {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	tasks := make([]domain.Task, 0, len(t.tasks))
+	for _, task := range t.tasks {
+		if filter.Match(*task) {
+			tasks = append(tasks, *task)
+		}
+	}
+	return tasks
+}
+
+func (t *TaskStore) Changes(since int64) (domain.TaskChanges, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if since > 0 && since < t.prunedSeq {
+		return domain.TaskChanges{}, domain.ErrSyncTokenExpired
+	}
+	rtn := domain.TaskChanges{
+		Created: make([]domain.Task, 0),
+		Updated: make([]domain.Task, 0),
+		Deleted: make([]int64, 0),
+		Seq:     t.seq,
+		Epoch:   t.epoch,
+	}
+	for id, versions := range t.versions {
+		last := versions[len(versions)-1]
+		if last.seq <= since {
+			continue
+		}
+		before := t.visibleAt(id, since)
+		now := last.task != nil && last.task.DeletedAt == nil
+		switch {
+		case now && before:
+			rtn.Updated = append(rtn.Updated, *last.task)
+		case now:
+			rtn.Created = append(rtn.Created, *last.task)
+		case before:
+			rtn.Deleted = append(rtn.Deleted, id)
+		}
+	}
+	sortTasks(rtn.Created)
+	sortTasks(rtn.Updated)
+	sort.Slice(rtn.Deleted, func(i, j int) bool { return rtn.Deleted[i] < rtn.Deleted[j] })
+	return rtn, nil
+}
+
+func (t *TaskStore) PendingOutbox(limit int) []domain.OutboxMessage {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if limit > len(t.outbox) {
+		limit = len(t.outbox)
+	}
+	rtn := make([]domain.OutboxMessage, limit)
+	copy(rtn, t.outbox)
+	return rtn
+}
+
+func (t *TaskStore) AckOutbox(seq int64) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	i := 0
+	for i < len(t.outbox) && t.outbox[i].Seq <= seq {
+		i++
+	}
+	t.outbox = append([]domain.OutboxMessage(nil), t.outbox[i:]...)
+}
+
+func (t *TaskStore) UpdateTask(id int64, status domain.Status, description string) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
 		return domain.Task{}, domain.ErrDataNotFound
 	}
 	task := t.tasks[id]
+	now := t.now()
+	switch {
+	case status == domain.StatusComplete && task.Status != domain.StatusComplete:
+		completedAt := now
+		task.CompletedAt = &completedAt
+	case status != domain.StatusComplete:
+		task.CompletedAt = nil
+	}
 	task.Status = status
+	task.Description = description
+	t.record(task, now)
+	return *t.tasks[id], nil
+}
+
+func (t *TaskStore) RevertTask(id int64, to domain.Task) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	task.Status = to.Status
+	task.CompletedAt = nil
+	if to.CompletedAt != nil {
+		completedAt := *to.CompletedAt
+		task.CompletedAt = &completedAt
+	}
+	task.Description = to.Description
+	t.record(task, t.now())
+	return *t.tasks[id], nil
+}
+
+func (t *TaskStore) AssignTask(id int64, assigneeID *int64) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	task.AssigneeID = assigneeID
+	t.record(task, t.now())
 	return *t.tasks[id], nil
 }
 
+func (t *TaskStore) EstimateTask(id int64, estimate *domain.Estimate) (domain.Task, error) {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	if _, ok := t.live(id); !ok {
+		return domain.Task{}, domain.ErrDataNotFound
+	}
+	task := t.tasks[id]
+	task.Estimate = estimate
+	t.record(task, t.now())
+	return *t.tasks[id], nil
+}
+
+func (t *TaskStore) TasksByAssignee(assigneeID int64) []domain.Task {
+	t.Mu.Lock()
+	defer t.Mu.Unlock()
+	tasks := make([]domain.Task, 0)
+	for _, task := range t.tasks {
+		if task.DeletedAt != nil || task.ArchivedAt != nil {
+			continue
+		}
+		if task.AssigneeID != nil && *task.AssigneeID == assigneeID {
+			tasks = append(tasks, *task)
+		}
+	}
+	return tasks
+}
+
 func NewTaskStore() *TaskStore {
 	mu1 := sync.Mutex{}
 	mu2 := sync.Mutex{}
@@ -70,6 +650,11 @@ func NewTaskStore() *TaskStore {
 		Mu:        &mu2,
 		IDCounter: NewTaskIDCounter(&mu1),
 		tasks:     map[int64]*domain.Task{},
+		now:       time.Now,
+		versions:  map[int64][]taskVersion{},
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+
+		checklistIDs: map[int64]int64{},
 	}
 }
 
@@ -83,27 +668,36 @@ func NewTaskRepository() *taskRepository {
 	}
 }
 
-func (r *taskRepository) List(ctx context.Context) ([]domain.Task, error) {
-	var tasks []domain.Task
-	for _, task := range r.store.tasks {
-		tasks = append(tasks, *task)
-	}
-	sort.SliceStable(tasks, func(i, j int) bool {
-		return tasks[i].ID < tasks[j].ID
-	})
-	if len(tasks) == 0 {
-		return make([]domain.Task, 0), nil
+// NewTaskRepositoryWithRetention returns a repository that drops the versions of a task
+// once they have been replaced for longer than retention. Reads as of a time before the
+// dropped versions fail with domain.ErrHistoryExpired, and the change feed after their
+// sequence numbers with domain.ErrSyncTokenExpired.
+func NewTaskRepositoryWithRetention(retention time.Duration) *taskRepository {
+	store := NewTaskStore()
+	store.retention = retention
+	return &taskRepository{
+		store: store,
 	}
+}
+
+func (r *taskRepository) List(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	tasks := r.store.Tasks(filter)
+	sortTasks(tasks)
 	return tasks, nil
 }
 
-func (r *taskRepository) Create(ctx context.Context, name string) (domain.Task, error) {
-	id := r.store.IDCounter.Next()
-	task := domain.Task{
-		ID:     id,
-		Status: domain.StatusIncomplete,
-		Name:   name,
+func (r *taskRepository) ListAsOf(ctx context.Context, filter domain.TaskFilter, at time.Time) ([]domain.Task, error) {
+	tasks, err := r.store.TasksAsOf(filter, at)
+	if err != nil {
+		return nil, err
 	}
+	sortTasks(tasks)
+	return tasks, nil
+}
+
+func (r *taskRepository) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
+	task.ID = r.store.IDCounter.Next()
+	task.Status = domain.StatusIncomplete
 	rtn, err := r.store.AddTask(task)
 	if err != nil {
 		return domain.Task{}, err
@@ -111,11 +705,30 @@ func (r *taskRepository) Create(ctx context.Context, name string) (domain.Task,
 	return rtn, nil
 }
 
-func (r *taskRepository) Update(ctx context.Context, id int64, status domain.Status) (domain.Task, error) {
-	if _, ok := r.store.tasks[id]; !ok {
-		return domain.Task{}, domain.ErrDataNotFound
+func (r *taskRepository) CreateMany(ctx context.Context, tasks []domain.Task) ([]domain.Task, error) {
+	created := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		task.ID = r.store.IDCounter.Next()
+		task.Status = domain.StatusIncomplete
+		created = append(created, task)
+	}
+	rtn, err := r.store.AddTasks(created)
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) Update(ctx context.Context, id int64, status domain.Status, description string) (domain.Task, error) {
+	rtn, err := r.store.UpdateTask(id, status, description)
+	if err != nil {
+		return domain.Task{}, err
 	}
-	rtn, err := r.store.UpdateTask(id, status)
+	return rtn, nil
+}
+
+func (r *taskRepository) Revert(ctx context.Context, id int64, to domain.Task) (domain.Task, error) {
+	rtn, err := r.store.RevertTask(id, to)
 	if err != nil {
 		return domain.Task{}, err
 	}
@@ -130,9 +743,93 @@ func (r *taskRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (r *taskRepository) Restore(ctx context.Context, id int64) (domain.Task, error) {
+	rtn, err := r.store.RestoreTask(id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) Purge(ctx context.Context, deletedBefore time.Time) ([]int64, error) {
+	return r.store.PurgeTasks(deletedBefore), nil
+}
+
+func (r *taskRepository) Archive(ctx context.Context, id int64) (domain.Task, error) {
+	rtn, err := r.store.ArchiveTask(id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) Unarchive(ctx context.Context, id int64) (domain.Task, error) {
+	rtn, err := r.store.UnarchiveTask(id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) ([]int64, error) {
+	return r.store.ArchiveCompleted(completedBefore), nil
+}
+
 func (r *taskRepository) Get(ctx context.Context, id int64) (domain.Task, error) {
-	if _, ok := r.store.tasks[id]; !ok {
-		return domain.Task{}, domain.ErrDataNotFound
+	rtn, err := r.store.GetTask(id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) GetAsOf(ctx context.Context, id int64, at time.Time) (domain.Task, error) {
+	rtn, err := r.store.GetTaskAsOf(id, at)
+	if err != nil {
+		return domain.Task{}, err
 	}
-	return *r.store.tasks[id], nil
+	return rtn, nil
+}
+
+func (r *taskRepository) Assign(ctx context.Context, id int64, assigneeID *int64) (domain.Task, error) {
+	rtn, err := r.store.AssignTask(id, assigneeID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) Estimate(ctx context.Context, id int64, estimate *domain.Estimate) (domain.Task, error) {
+	rtn, err := r.store.EstimateTask(id, estimate)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
+}
+
+func (r *taskRepository) ListByAssignee(ctx context.Context, assigneeID int64) ([]domain.Task, error) {
+	tasks := r.store.TasksByAssignee(assigneeID)
+	sortTasks(tasks)
+	return tasks, nil
+}
+
+func (r *taskRepository) Changes(ctx context.Context, since int64) (domain.TaskChanges, error) {
+	return r.store.Changes(since)
+}
+
+func (r *taskRepository) PendingOutbox(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	return r.store.PendingOutbox(limit), nil
+}
+
+func (r *taskRepository) AckOutbox(ctx context.Context, seq int64) error {
+	r.store.AckOutbox(seq)
+	return nil
+}
+
+func (r *taskRepository) Move(ctx context.Context, id int64, beforeID int64, afterID int64) (domain.Task, error) {
+	rtn, err := r.store.MoveTask(id, beforeID, afterID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return rtn, nil
 }
@@ -2,9 +2,11 @@ package inmemory
 
 import (
 	"context"
+	"fmt"
 	"oa-gogolook/internal/domain"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_taskRepository_Create(t *testing.T) {
@@ -26,7 +28,7 @@ func Test_taskRepository_Create(t *testing.T) {
 		{
 			name: "OK",
 			buildStubs: func(store *TaskStore) {
-
+				store.now = fixedNow
 			},
 			fields: fields{
 				store: NewTaskStore(),
@@ -36,9 +38,11 @@ func Test_taskRepository_Create(t *testing.T) {
 				name: "taskName",
 			},
 			want: domain.Task{
-				ID:     1,
-				Status: domain.StatusIncomplete,
-				Name:   "taskName",
+				ID:        1,
+				Status:    domain.StatusIncomplete,
+				Name:      "taskName",
+				Rank:      "V",
+				CreatedAt: fixedNow(),
 			},
 			wantErr: false,
 		},
@@ -70,7 +74,7 @@ func Test_taskRepository_Create(t *testing.T) {
 
 			tt.buildStubs(r.store)
 
-			got, err := r.Create(tt.args.ctx, tt.args.name)
+			got, err := r.Create(tt.args.ctx, domain.Task{Name: tt.args.name})
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
@@ -154,7 +158,7 @@ func Test_taskRepository_List(t *testing.T) {
 				store: tt.fields.store,
 			}
 			tt.buildStubs(r.store)
-			got, err := r.List(tt.args.ctx)
+			got, err := r.List(tt.args.ctx, domain.TaskFilter{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -205,9 +209,10 @@ func Test_taskRepository_Update(t *testing.T) {
 				status: domain.StatusComplete,
 			},
 			want: domain.Task{
-				ID:     1,
-				Status: domain.StatusComplete,
-				Name:   "taskName1",
+				ID:          1,
+				Status:      domain.StatusComplete,
+				Name:        "taskName1",
+				CompletedAt: timePtr(fixedNow()),
 			},
 			wantErr: false,
 		},
@@ -257,8 +262,9 @@ func Test_taskRepository_Update(t *testing.T) {
 			r := &taskRepository{
 				store: tt.fields.store,
 			}
+			r.store.now = fixedNow
 			tt.buildStubs(r.store)
-			got, err := r.Update(tt.args.ctx, tt.args.id, tt.args.status)
+			got, err := r.Update(tt.args.ctx, tt.args.id, tt.args.status, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -317,6 +323,27 @@ func Test_taskRepository_Delete(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "AlreadyDeleted",
+			buildStubs: func(store *TaskStore) {
+				id := store.IDCounter.Next()
+				deletedAt := time.Now()
+				store.tasks[id] = &domain.Task{
+					ID:        id,
+					Status:    domain.StatusIncomplete,
+					Name:      "taskName1",
+					DeletedAt: &deletedAt,
+				}
+			},
+			fields: fields{
+				store: NewTaskStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -469,3 +496,894 @@ func Test_taskRepository_Get(t *testing.T) {
 		})
 	}
 }
+
+func Test_taskRepository_Assign(t *testing.T) {
+	assigneeID := int64(7)
+	type fields struct {
+		store *TaskStore
+	}
+	type args struct {
+		ctx        context.Context
+		id         int64
+		assigneeID *int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *TaskStore)
+		fields     fields
+		args       args
+		want       domain.Task
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *TaskStore) {
+				id := store.IDCounter.Next()
+				store.tasks[id] = &domain.Task{
+					ID:     id,
+					Status: domain.StatusIncomplete,
+					Name:   "taskName1",
+				}
+			},
+			fields: fields{
+				store: NewTaskStore(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				assigneeID: &assigneeID,
+			},
+			want: domain.Task{
+				ID:         1,
+				Status:     domain.StatusIncomplete,
+				Name:       "taskName1",
+				AssigneeID: &assigneeID,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unassign",
+			buildStubs: func(store *TaskStore) {
+				id := store.IDCounter.Next()
+				store.tasks[id] = &domain.Task{
+					ID:         id,
+					Status:     domain.StatusIncomplete,
+					Name:       "taskName1",
+					AssigneeID: &assigneeID,
+				}
+			},
+			fields: fields{
+				store: NewTaskStore(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				assigneeID: nil,
+			},
+			want: domain.Task{
+				ID:     1,
+				Status: domain.StatusIncomplete,
+				Name:   "taskName1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "NotExists",
+			buildStubs: func(store *TaskStore) {
+			},
+			fields: fields{
+				store: NewTaskStore(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				assigneeID: &assigneeID,
+			},
+			want:    domain.Task{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &taskRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.Assign(tt.args.ctx, tt.args.id, tt.args.assigneeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Assign() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Assign() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepository_CreateMany(t *testing.T) {
+	tests := []struct {
+		name       string
+		buildStubs func(store *TaskStore)
+		tasks      []domain.Task
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *TaskStore) {
+				store.tasks[9] = &domain.Task{ID: 9, Name: "existing", Rank: "V"}
+			},
+			tasks:   []domain.Task{{Name: "taskName1"}, {Name: "taskName2", Tags: []string{"release"}}},
+			wantErr: false,
+		},
+		{
+			name: "WrongID",
+			buildStubs: func(store *TaskStore) {
+				store.tasks[2] = &domain.Task{ID: 2, Name: "existing", Rank: "V"}
+			},
+			tasks:   []domain.Task{{Name: "taskName1"}, {Name: "taskName2"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &taskRepository{
+				store: NewTaskStore(),
+			}
+			tt.buildStubs(r.store)
+			got, err := r.CreateMany(context.Background(), tt.tasks)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateMany() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if len(r.store.tasks) != 1 {
+					t.Errorf("CreateMany() left %d tasks, want 1", len(r.store.tasks))
+				}
+				return
+			}
+			for i, task := range got {
+				if task.ID != int64(i+1) || task.Name != tt.tasks[i].Name || task.Status != domain.StatusIncomplete {
+					t.Errorf("CreateMany() got = %v", task)
+				}
+				if task.Rank <= "V" || (i > 0 && task.Rank <= got[i-1].Rank) {
+					t.Errorf("CreateMany() ranks = %v, %v", got[0].Rank, task.Rank)
+				}
+			}
+		})
+	}
+}
+
+func Test_taskRepository_Estimate(t *testing.T) {
+	estimate := domain.Estimate{Value: 2.5, Unit: domain.EstimateUnitHours}
+	tests := []struct {
+		name     string
+		id       int64
+		estimate *domain.Estimate
+		want     domain.Task
+		wantErr  bool
+	}{
+		{
+			name:     "OK",
+			id:       1,
+			estimate: &estimate,
+			want: domain.Task{
+				ID:       1,
+				Status:   domain.StatusIncomplete,
+				Name:     "taskName1",
+				Estimate: &estimate,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Clear",
+			id:       1,
+			estimate: nil,
+			want: domain.Task{
+				ID:     1,
+				Status: domain.StatusIncomplete,
+				Name:   "taskName1",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "NotExists",
+			id:       5,
+			estimate: &estimate,
+			want:     domain.Task{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &taskRepository{
+				store: NewTaskStore(),
+			}
+			id := r.store.IDCounter.Next()
+			r.store.tasks[id] = &domain.Task{
+				ID:       id,
+				Status:   domain.StatusIncomplete,
+				Name:     "taskName1",
+				Estimate: &domain.Estimate{Value: 1, Unit: domain.EstimateUnitPoints},
+			}
+			got, err := r.Estimate(context.Background(), tt.id, tt.estimate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Estimate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Estimate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepository_ListByAssignee(t *testing.T) {
+	assigneeID := int64(7)
+	otherID := int64(8)
+	type fields struct {
+		store *TaskStore
+	}
+	type args struct {
+		ctx        context.Context
+		assigneeID int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *TaskStore)
+		fields     fields
+		args       args
+		want       []domain.Task
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *TaskStore) {
+				id := store.IDCounter.Next()
+				store.tasks[id] = &domain.Task{ID: id, Name: "taskName1", AssigneeID: &assigneeID}
+				id = store.IDCounter.Next()
+				store.tasks[id] = &domain.Task{ID: id, Name: "taskName2", AssigneeID: &otherID}
+				id = store.IDCounter.Next()
+				store.tasks[id] = &domain.Task{ID: id, Name: "taskName3"}
+				id = store.IDCounter.Next()
+				store.tasks[id] = &domain.Task{ID: id, Name: "taskName4", AssigneeID: &assigneeID}
+			},
+			fields: fields{
+				store: NewTaskStore(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				assigneeID: assigneeID,
+			},
+			want: []domain.Task{
+				{ID: 1, Name: "taskName1", AssigneeID: &assigneeID},
+				{ID: 4, Name: "taskName4", AssigneeID: &assigneeID},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OKEmpty",
+			buildStubs: func(store *TaskStore) {
+			},
+			fields: fields{
+				store: NewTaskStore(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				assigneeID: assigneeID,
+			},
+			want:    []domain.Task{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &taskRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.ListByAssignee(tt.args.ctx, tt.args.assigneeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListByAssignee() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListByAssignee() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func listNames(t *testing.T, r *taskRepository) []string {
+	tasks, err := r.List(context.Background(), domain.TaskFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	return names(tasks)
+}
+
+func names(tasks []domain.Task) []string {
+	rtn := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		rtn = append(rtn, task.Name)
+	}
+	return rtn
+}
+
+func Test_taskRepository_Move(t *testing.T) {
+	type args struct {
+		id       int64
+		beforeID int64
+		afterID  int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "BeforeFirst",
+			args:    args{id: 3, beforeID: 1},
+			want:    []string{"task3", "task1", "task2"},
+			wantErr: false,
+		},
+		{
+			name:    "BeforeMiddle",
+			args:    args{id: 3, beforeID: 2},
+			want:    []string{"task1", "task3", "task2"},
+			wantErr: false,
+		},
+		{
+			name:    "AfterLast",
+			args:    args{id: 1, afterID: 3},
+			want:    []string{"task2", "task3", "task1"},
+			wantErr: false,
+		},
+		{
+			name:    "AfterMiddle",
+			args:    args{id: 1, afterID: 2},
+			want:    []string{"task2", "task1", "task3"},
+			wantErr: false,
+		},
+		{
+			name:    "ReferenceNotExists",
+			args:    args{id: 1, afterID: 9},
+			want:    []string{"task1", "task2", "task3"},
+			wantErr: true,
+		},
+		{
+			name:    "NotExists",
+			args:    args{id: 9, afterID: 1},
+			want:    []string{"task1", "task2", "task3"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &taskRepository{
+				store: NewTaskStore(),
+			}
+			for _, name := range []string{"task1", "task2", "task3"} {
+				_, _ = r.Create(context.Background(), domain.Task{Name: name})
+			}
+			_, err := r.Move(context.Background(), tt.args.id, tt.args.beforeID, tt.args.afterID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Move() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := listNames(t, r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Move() order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepository_MoveRebalance(t *testing.T) {
+	r := &taskRepository{
+		store: NewTaskStore(),
+	}
+	for _, name := range []string{"task1", "task2", "task3"} {
+		_, _ = r.Create(context.Background(), domain.Task{Name: name})
+	}
+	// Moving back and forth between the same neighbours makes the ranks grow until the
+	// store spreads them again.
+	for i := 0; i < 200; i++ {
+		if _, err := r.Move(context.Background(), 3, 2, 0); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if _, err := r.Move(context.Background(), 2, 3, 0); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+	tasks, _ := r.List(context.Background(), domain.TaskFilter{})
+	for _, task := range tasks {
+		if len(task.Rank) > 16 {
+			t.Errorf("rank %q of task %d was not rebalanced", task.Rank, task.ID)
+		}
+	}
+	if got, want := listNames(t, r), []string{"task1", "task2", "task3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after rebalance = %v, want %v", got, want)
+	}
+}
+
+// Test_taskRepository_Respread checks that a respread only rewrites the neighbourhood of
+// the long rank, leaves tasks in the trash alone and sends no outbox messages.
+func Test_taskRepository_Respread(t *testing.T) {
+	r := &taskRepository{
+		store: NewTaskStore(),
+	}
+	for i := 0; i < 3000; i++ {
+		if _, err := r.Create(context.Background(), domain.Task{Name: "task"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := r.Delete(context.Background(), 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	versions := 0
+	for _, v := range r.store.versions {
+		versions += len(v)
+	}
+	// Appending steps the last rank up, it only rarely needs a respread of the last tasks.
+	if versions > 3100 {
+		t.Errorf("3000 creates wrote %d versions", versions)
+	}
+	trashed := len(r.store.versions[1])
+	far := len(r.store.versions[1000])
+	outbox := len(r.store.outbox)
+	for i := 0; i < 200; i++ {
+		if _, err := r.Move(context.Background(), 3, 2, 0); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if _, err := r.Move(context.Background(), 2, 3, 0); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+	if got := len(r.store.versions[1]); got != trashed {
+		t.Errorf("the task in the trash got %d new versions", got-trashed)
+	}
+	if got := len(r.store.versions[1000]); got != far {
+		t.Errorf("a task far from the moves got %d new versions", got-far)
+	}
+	if got := len(r.store.outbox); got != outbox {
+		t.Errorf("moves queued %d outbox messages, want none", got-outbox)
+	}
+}
+
+func Test_taskRepository_MoveWithoutRanks(t *testing.T) {
+	r := &taskRepository{
+		store: NewTaskStore(),
+	}
+	for _, name := range []string{"task1", "task2", "task3"} {
+		id := r.store.IDCounter.Next()
+		r.store.tasks[id] = &domain.Task{ID: id, Name: name}
+	}
+	if _, err := r.Move(context.Background(), 1, 0, 3); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if got, want := listNames(t, r), []string{"task2", "task3", "task1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Move() order = %v, want %v", got, want)
+	}
+}
+
+func newTestTrashRepository(now time.Time) *taskRepository {
+	store := NewTaskStore()
+	store.now = func() time.Time { return now }
+	r := &taskRepository{
+		store: store,
+	}
+	for _, name := range []string{"task1", "task2", "task3"} {
+		_, _ = r.Create(context.Background(), domain.Task{Name: name})
+	}
+	return r
+}
+
+func Test_taskRepository_Trash(t *testing.T) {
+	deletedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := newTestTrashRepository(deletedAt)
+	if err := r.Delete(context.Background(), 2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := r.Get(context.Background(), 2); err != domain.ErrDataNotFound {
+		t.Errorf("Get() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if _, err := r.Update(context.Background(), 2, domain.StatusComplete, ""); err != domain.ErrDataNotFound {
+		t.Errorf("Update() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if got, want := listNames(t, r), []string{"task1", "task3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	tasks, _ := r.List(context.Background(), domain.TaskFilter{IncludeDeleted: true})
+	if len(tasks) != 3 || tasks[1].DeletedAt == nil || !tasks[1].DeletedAt.Equal(deletedAt) {
+		t.Errorf("List() with deleted = %v, want task 2 deleted at %v", tasks, deletedAt)
+	}
+
+	got, err := r.Restore(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got.DeletedAt != nil {
+		t.Errorf("Restore() deletedAt = %v, want nil", got.DeletedAt)
+	}
+	if got, want := listNames(t, r), []string{"task1", "task2", "task3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() after restore = %v, want %v", got, want)
+	}
+	if _, err := r.Restore(context.Background(), 2); err != domain.ErrTaskNotDeleted {
+		t.Errorf("Restore() error = %v, want %v", err, domain.ErrTaskNotDeleted)
+	}
+	if _, err := r.Restore(context.Background(), 9); err != domain.ErrDataNotFound {
+		t.Errorf("Restore() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_taskRepository_Purge(t *testing.T) {
+	deletedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name          string
+		deletedBefore time.Time
+		want          []int64
+		wantNames     []string
+	}{
+		{
+			name:          "Expired",
+			deletedBefore: deletedAt.Add(time.Second),
+			want:          []int64{1, 3},
+			wantNames:     []string{"task2"},
+		},
+		{
+			name:          "NotExpired",
+			deletedBefore: deletedAt,
+			want:          []int64{},
+			wantNames:     []string{"task1", "task2", "task3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestTrashRepository(deletedAt)
+			_ = r.Delete(context.Background(), 1)
+			_ = r.Delete(context.Background(), 3)
+			got, err := r.Purge(context.Background(), tt.deletedBefore)
+			if err != nil {
+				t.Fatalf("Purge() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Purge() got = %v, want %v", got, tt.want)
+			}
+			tasks, _ := r.List(context.Background(), domain.TaskFilter{IncludeDeleted: true})
+			if got := names(tasks); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("List() after purge = %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+}
+
+// Test_taskRepository_Versions pins down the growth of the version history, one version
+// per write and a single marker left of a purged task.
+func Test_taskRepository_Versions(t *testing.T) {
+	deletedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := newTestTrashRepository(deletedAt)
+	_, _ = r.Update(context.Background(), 1, domain.StatusComplete, "")
+	_, _ = r.Update(context.Background(), 1, domain.StatusIncomplete, "")
+	_ = r.Delete(context.Background(), 1)
+	_, _ = r.Purge(context.Background(), deletedAt.Add(time.Second))
+
+	want := map[int64]int{1: 1, 2: 1, 3: 1}
+	for id, n := range want {
+		if got := len(r.store.versions[id]); got != n {
+			t.Errorf("versions of task %d = %d, want %d", id, got, n)
+		}
+	}
+	if last := r.store.versions[1][0]; last.task != nil {
+		t.Errorf("version of the purged task = %v, want the purge marker", last.task)
+	}
+	if _, err := r.GetAsOf(context.Background(), 1, deletedAt.Add(-time.Second)); err != domain.ErrDataNotFound {
+		t.Errorf("GetAsOf() of the purged task error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_taskRepository_VersionRetention(t *testing.T) {
+	ctx := context.Background()
+	r := NewTaskRepositoryWithRetention(time.Hour)
+	now := fixedNow()
+	r.store.now = func() time.Time { return now }
+	_, _ = r.Create(ctx, domain.Task{Name: "task1"})
+	_, _ = r.Create(ctx, domain.Task{Name: "task2"})
+	_ = r.Delete(ctx, 2)
+	old, _ := r.Changes(ctx, 0)
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Minute)
+		_, _ = r.Update(ctx, 1, domain.StatusComplete, fmt.Sprintf("update %d", i))
+	}
+	_, _ = r.Purge(ctx, now)
+
+	now = now.Add(2 * time.Hour)
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "late")
+	_, _ = r.Purge(ctx, now)
+
+	if got := len(r.store.versions[1]); got != 2 {
+		t.Errorf("versions of task 1 = %d, want the one valid an hour ago and the latest", got)
+	}
+	if _, ok := r.store.versions[2]; ok {
+		t.Errorf("versions of task 2 = %v, want the old purge marker dropped", r.store.versions[2])
+	}
+	if _, err := r.GetAsOf(ctx, 1, fixedNow()); err != domain.ErrHistoryExpired {
+		t.Errorf("GetAsOf() before the retention error = %v, want %v", err, domain.ErrHistoryExpired)
+	}
+	if _, err := r.ListAsOf(ctx, domain.TaskFilter{}, fixedNow()); err != domain.ErrHistoryExpired {
+		t.Errorf("ListAsOf() before the retention error = %v, want %v", err, domain.ErrHistoryExpired)
+	}
+	task, err := r.GetAsOf(ctx, 1, now.Add(-time.Hour))
+	if err != nil || task.Description != "update 9" {
+		t.Errorf("GetAsOf() within the retention = %v, %v, want the last update", task, err)
+	}
+	if _, err := r.Changes(ctx, old.Seq); err != domain.ErrSyncTokenExpired {
+		t.Errorf("Changes() before the retention error = %v, want %v", err, domain.ErrSyncTokenExpired)
+	}
+	if got, err := r.Changes(ctx, 0); err != nil || len(got.Created) != 1 {
+		t.Errorf("Changes(0) = %+v, %v, want task 1", got, err)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func Test_taskRepository_Archive(t *testing.T) {
+	r := newTestTrashRepository(fixedNow())
+	got, err := r.Archive(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if got.ArchivedAt == nil || !got.ArchivedAt.Equal(fixedNow()) {
+		t.Errorf("Archive() archivedAt = %v, want %v", got.ArchivedAt, fixedNow())
+	}
+	if _, err := r.Archive(context.Background(), 2); err != domain.ErrTaskArchived {
+		t.Errorf("Archive() error = %v, want %v", err, domain.ErrTaskArchived)
+	}
+	if _, err := r.Get(context.Background(), 2); err != nil {
+		t.Errorf("Get() error = %v, want archived task", err)
+	}
+	if got, want := listNames(t, r), []string{"task1", "task3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	tasks, _ := r.List(context.Background(), domain.TaskFilter{IncludeArchived: true})
+	if len(tasks) != 3 {
+		t.Errorf("List() with archived returned %d tasks, want 3", len(tasks))
+	}
+
+	if _, err := r.Unarchive(context.Background(), 2); err != nil {
+		t.Fatalf("Unarchive() error = %v", err)
+	}
+	if got, want := listNames(t, r), []string{"task1", "task2", "task3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() after unarchive = %v, want %v", got, want)
+	}
+	if _, err := r.Unarchive(context.Background(), 2); err != domain.ErrTaskNotArchived {
+		t.Errorf("Unarchive() error = %v, want %v", err, domain.ErrTaskNotArchived)
+	}
+	if _, err := r.Archive(context.Background(), 9); err != domain.ErrDataNotFound {
+		t.Errorf("Archive() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_taskRepository_ArchiveCompleted(t *testing.T) {
+	tests := []struct {
+		name            string
+		completedBefore time.Time
+		want            []int64
+	}{
+		{
+			name:            "Expired",
+			completedBefore: fixedNow().Add(time.Second),
+			want:            []int64{1},
+		},
+		{
+			name:            "NotExpired",
+			completedBefore: fixedNow(),
+			want:            []int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestTrashRepository(fixedNow())
+			_, _ = r.Update(context.Background(), 1, domain.StatusComplete, "")
+			_, _ = r.Update(context.Background(), 3, domain.StatusComplete, "")
+			_ = r.Delete(context.Background(), 3)
+			got, err := r.ArchiveCompleted(context.Background(), tt.completedBefore)
+			if err != nil {
+				t.Fatalf("ArchiveCompleted() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ArchiveCompleted() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRepository_UpdateCompletedAt(t *testing.T) {
+	r := newTestTrashRepository(fixedNow())
+	got, _ := r.Update(context.Background(), 1, domain.StatusComplete, "")
+	if got.CompletedAt == nil || !got.CompletedAt.Equal(fixedNow()) {
+		t.Fatalf("Update() completedAt = %v, want %v", got.CompletedAt, fixedNow())
+	}
+	r.store.now = func() time.Time { return fixedNow().Add(time.Hour) }
+	got, _ = r.Update(context.Background(), 1, domain.StatusComplete, "edited")
+	if !got.CompletedAt.Equal(fixedNow()) {
+		t.Errorf("Update() completedAt = %v, want unchanged %v", got.CompletedAt, fixedNow())
+	}
+	got, _ = r.Update(context.Background(), 1, domain.StatusIncomplete, "")
+	if got.CompletedAt != nil {
+		t.Errorf("Update() completedAt = %v, want nil", got.CompletedAt)
+	}
+}
+
+func Test_taskRepository_AsOf(t *testing.T) {
+	ctx := context.Background()
+	store := NewTaskStore()
+	tick := 0
+	store.now = func() time.Time {
+		tick++
+		return fixedNow().Add(time.Duration(tick) * time.Minute)
+	}
+	r := &taskRepository{
+		store: store,
+	}
+	_, _ = r.Create(ctx, domain.Task{Name: "task1"})       // minute 1
+	_, _ = r.Create(ctx, domain.Task{Name: "task2"})       // minute 2
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "done") // minute 3 (completedAt 4)
+	_ = r.Delete(ctx, 2)                                   // minute 5
+	_, _ = r.Purge(ctx, fixedNow().Add(time.Hour))         // minute 6
+	_, _ = r.AddChecklistItem(ctx, 1, "item")              // minute 7
+
+	tests := []struct {
+		name       string
+		at         time.Time
+		wantNames  []string
+		wantStatus domain.Status
+	}{
+		{
+			name:      "BeforeCreate",
+			at:        fixedNow(),
+			wantNames: []string{},
+		},
+		{
+			name:       "Created",
+			at:         fixedNow().Add(2 * time.Minute),
+			wantNames:  []string{"task1"},
+			wantStatus: domain.StatusIncomplete,
+		},
+		{
+			name:       "Updated",
+			at:         fixedNow().Add(3 * time.Minute),
+			wantNames:  []string{"task1"},
+			wantStatus: domain.StatusComplete,
+		},
+		{
+			name:       "Deleted",
+			at:         fixedNow().Add(4 * time.Minute),
+			wantNames:  []string{"task1"},
+			wantStatus: domain.StatusComplete,
+		},
+		{
+			name:       "Purged",
+			at:         fixedNow().Add(5 * time.Minute),
+			wantNames:  []string{"task1"},
+			wantStatus: domain.StatusComplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := r.ListAsOf(ctx, domain.TaskFilter{}, tt.at)
+			if err != nil {
+				t.Fatalf("ListAsOf() error = %v", err)
+			}
+			if got := names(tasks); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("ListAsOf() = %v, want %v", got, tt.wantNames)
+			}
+			task, err := r.GetAsOf(ctx, 1, tt.at)
+			if len(tt.wantNames) == 0 {
+				if err != domain.ErrDataNotFound {
+					t.Errorf("GetAsOf() error = %v, want %v", err, domain.ErrDataNotFound)
+				}
+				return
+			}
+			if err != nil || task.Status != tt.wantStatus || len(task.Checklist) != 0 {
+				t.Errorf("GetAsOf() = %v, %v, want status %v without checklist", task, err, tt.wantStatus)
+			}
+			// Task 2 has been purged since, it is gone at any time.
+			if _, err := r.GetAsOf(ctx, 2, tt.at); err != domain.ErrDataNotFound {
+				t.Errorf("GetAsOf() task 2 error = %v, want %v", err, domain.ErrDataNotFound)
+			}
+		})
+	}
+	if task, _ := r.GetAsOf(ctx, 1, fixedNow().Add(time.Hour)); len(task.Checklist) != 1 {
+		t.Errorf("GetAsOf() now = %v, want the checklist item", task)
+	}
+}
+
+func Test_taskRepository_Changes(t *testing.T) {
+	r := NewTaskRepository()
+	ctx := context.Background()
+	for _, name := range []string{"taskName1", "taskName2", "taskName3"} {
+		_, _ = r.Create(ctx, domain.Task{Name: name})
+	}
+	_ = r.Delete(ctx, 3)
+	first, _ := r.Changes(ctx, 0)
+
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "done")
+	_ = r.Delete(ctx, 2)
+	_, _ = r.Create(ctx, domain.Task{Name: "taskName4"})
+	_, _ = r.Create(ctx, domain.Task{Name: "taskName5"})
+	_ = r.Delete(ctx, 5)
+	_, _ = r.Restore(ctx, 3)
+	second, _ := r.Changes(ctx, first.Seq)
+
+	if got := names(first.Created); !reflect.DeepEqual(got, []string{"taskName1", "taskName2"}) {
+		t.Errorf("Changes(0) Created = %v", got)
+	}
+	if len(first.Updated) != 0 || len(first.Deleted) != 0 {
+		t.Errorf("Changes(0) = %+v, want created tasks only", first)
+	}
+	if got := names(second.Created); !reflect.DeepEqual(got, []string{"taskName3", "taskName4"}) {
+		t.Errorf("Changes() Created = %v, want the restored and the new task", got)
+	}
+	if got := names(second.Updated); !reflect.DeepEqual(got, []string{"taskName1"}) || second.Updated[0].Status != domain.StatusComplete {
+		t.Errorf("Changes() Updated = %+v", second.Updated)
+	}
+	if !reflect.DeepEqual(second.Deleted, []int64{2}) {
+		t.Errorf("Changes() Deleted = %v, want [2]", second.Deleted)
+	}
+	if second.Seq <= first.Seq {
+		t.Errorf("Changes() Seq = %d, want more than %d", second.Seq, first.Seq)
+	}
+
+	// Purging a task the client already dropped is no change for it.
+	_, _ = r.Purge(ctx, time.Now().Add(time.Hour))
+	third, _ := r.Changes(ctx, second.Seq)
+	if len(third.Created)+len(third.Updated)+len(third.Deleted) != 0 || third.Seq <= second.Seq {
+		t.Errorf("Changes() after Purge() = %+v", third)
+	}
+	if got, _ := r.Changes(ctx, first.Seq); !reflect.DeepEqual(got.Deleted, []int64{2}) {
+		t.Errorf("Changes() Deleted after Purge() = %v, want [2]", got.Deleted)
+	}
+}
+
+func Test_taskRepository_Outbox(t *testing.T) {
+	r := NewTaskRepository()
+	ctx := context.Background()
+	_, _ = r.Create(ctx, domain.Task{Name: "taskName1"})
+	_, _ = r.Create(ctx, domain.Task{Name: "taskName2"})
+	_, _ = r.Update(ctx, 1, domain.StatusComplete, "done")
+	_ = r.Delete(ctx, 2)
+	_, _ = r.Restore(ctx, 2)
+	_ = r.Delete(ctx, 2)
+	_, _ = r.Purge(ctx, time.Now().Add(time.Hour))
+
+	got, _ := r.PendingOutbox(ctx, 10)
+	want := []string{"task.created 1", "task.created 2", "task.updated 1", "task.deleted 2", "task.created 2", "task.deleted 2"}
+	if types := outboxTypes(got); !reflect.DeepEqual(types, want) {
+		t.Fatalf("PendingOutbox() = %v, want %v", types, want)
+	}
+	if got[0].Before != nil || got[2].Before.Status != domain.StatusIncomplete || got[2].Task.Status != domain.StatusComplete {
+		t.Errorf("PendingOutbox() Before = %v, %v, want the task before the change", got[0].Before, got[2].Before)
+	}
+	if got[4].Before == nil || got[4].Before.DeletedAt == nil {
+		t.Errorf("PendingOutbox() restore Before = %v, want the deleted task", got[4].Before)
+	}
+
+	if limited, _ := r.PendingOutbox(ctx, 2); len(limited) != 2 || limited[1].ID != got[1].ID {
+		t.Errorf("PendingOutbox(2) = %v, want the first two messages", outboxTypes(limited))
+	}
+	_ = r.AckOutbox(ctx, got[3].Seq)
+	rest, _ := r.PendingOutbox(ctx, 10)
+	if !reflect.DeepEqual(rest, got[4:]) {
+		t.Errorf("PendingOutbox() after AckOutbox() = %v, want %v", outboxTypes(rest), outboxTypes(got[4:]))
+	}
+}
+
+func outboxTypes(messages []domain.OutboxMessage) []string {
+	rtn := make([]string, 0, len(messages))
+	for _, message := range messages {
+		rtn = append(rtn, fmt.Sprintf("%s %d", message.Type, message.Task.ID))
+	}
+	return rtn
+}
@@ -0,0 +1,105 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+)
+
+type TemplateStore struct {
+	Mu        *sync.Mutex
+	IDCounter *TaskIDCounter
+	templates map[int64]*domain.Template
+}
+
+func (s *TemplateStore) AddTemplate(template domain.Template) (domain.Template, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.templates[template.ID]; ok {
+		return domain.Template{}, domain.ErrWrongID
+	}
+	s.templates[template.ID] = &template
+	return *s.templates[template.ID], nil
+}
+
+func (s *TemplateStore) GetTemplate(id int64) (domain.Template, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.templates[id]; !ok {
+		return domain.Template{}, domain.ErrDataNotFound
+	}
+	return *s.templates[id], nil
+}
+
+func (s *TemplateStore) DeleteTemplate(id int64) error {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.templates[id]; !ok {
+		return domain.ErrDataNotFound
+	}
+	delete(s.templates, id)
+	return nil
+}
+
+func (s *TemplateStore) Templates() []domain.Template {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	templates := make([]domain.Template, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, *template)
+	}
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+func NewTemplateStore() *TemplateStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &TemplateStore{
+		Mu:        &mu2,
+		IDCounter: NewTaskIDCounter(&mu1),
+		templates: map[int64]*domain.Template{},
+	}
+}
+
+type templateRepository struct {
+	store *TemplateStore
+}
+
+func NewTemplateRepository() *templateRepository {
+	return &templateRepository{
+		store: NewTemplateStore(),
+	}
+}
+
+func (r *templateRepository) Create(ctx context.Context, template domain.Template) (domain.Template, error) {
+	template.ID = r.store.IDCounter.Next()
+	rtn, err := r.store.AddTemplate(template)
+	if err != nil {
+		return domain.Template{}, err
+	}
+	return rtn, nil
+}
+
+func (r *templateRepository) List(ctx context.Context) ([]domain.Template, error) {
+	return r.store.Templates(), nil
+}
+
+func (r *templateRepository) Get(ctx context.Context, id int64) (domain.Template, error) {
+	rtn, err := r.store.GetTemplate(id)
+	if err != nil {
+		return domain.Template{}, err
+	}
+	return rtn, nil
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id int64) error {
+	err := r.store.DeleteTemplate(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func Test_templateRepository(t *testing.T) {
+	r := NewTemplateRepository()
+	ctx := context.Background()
+	onboarding := domain.Template{
+		Name:  "onboarding",
+		Tasks: []domain.TemplateTask{{Name: "laptop", Subtasks: []string{"order", "set up"}}},
+	}
+	created, err := r.Create(ctx, onboarding)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	onboarding.ID = 1
+	if !reflect.DeepEqual(created, onboarding) {
+		t.Errorf("Create() got = %v, want %v", created, onboarding)
+	}
+	_, _ = r.Create(ctx, domain.Template{Name: "release"})
+	if got, _ := r.Get(ctx, 1); !reflect.DeepEqual(got, onboarding) {
+		t.Errorf("Get() got = %v, want %v", got, onboarding)
+	}
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := r.Get(ctx, 1); err != domain.ErrDataNotFound {
+		t.Errorf("Get() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if err := r.Delete(ctx, 1); err != domain.ErrDataNotFound {
+		t.Errorf("Delete() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if list, _ := r.List(ctx); len(list) != 1 || list[0].Name != "release" {
+		t.Errorf("List() got = %v", list)
+	}
+}
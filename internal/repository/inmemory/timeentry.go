@@ -0,0 +1,135 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type TimeEntryStore struct {
+	Mu        *sync.Mutex
+	IDCounter *TaskIDCounter
+	entries   map[int64]*domain.TimeEntry
+}
+
+func (s *TimeEntryStore) AddEntry(entry domain.TimeEntry) (domain.TimeEntry, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.entries[entry.ID]; ok {
+		return domain.TimeEntry{}, domain.ErrWrongID
+	}
+	s.entries[entry.ID] = &entry
+	return entry, nil
+}
+
+// StartTimer adds the running entry unless the user already has one, the check and the
+// insert happen under the same lock.
+func (s *TimeEntryStore) StartTimer(entry domain.TimeEntry) (domain.TimeEntry, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	for _, existing := range s.entries {
+		if existing.UserID == entry.UserID && existing.End == nil {
+			return domain.TimeEntry{}, domain.ErrTimerRunning
+		}
+	}
+	if _, ok := s.entries[entry.ID]; ok {
+		return domain.TimeEntry{}, domain.ErrWrongID
+	}
+	s.entries[entry.ID] = &entry
+	return entry, nil
+}
+
+func (s *TimeEntryStore) StopTimer(taskID int64, userID int64, end time.Time) (domain.TimeEntry, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	for _, entry := range s.entries {
+		if entry.TaskID == taskID && entry.UserID == userID && entry.End == nil {
+			entry.End = &end
+			return *entry, nil
+		}
+	}
+	return domain.TimeEntry{}, domain.ErrTimerNotRunning
+}
+
+// Entries returns the entries accepted by match ordered by start.
+func (s *TimeEntryStore) Entries(match func(entry domain.TimeEntry) bool) []domain.TimeEntry {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	entries := make([]domain.TimeEntry, 0)
+	for _, entry := range s.entries {
+		if match(*entry) {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Start.Before(entries[j].Start)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+func NewTimeEntryStore() *TimeEntryStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &TimeEntryStore{
+		Mu:        &mu2,
+		IDCounter: NewTaskIDCounter(&mu1),
+		entries:   map[int64]*domain.TimeEntry{},
+	}
+}
+
+type timeEntryRepository struct {
+	store *TimeEntryStore
+}
+
+func NewTimeEntryRepository() *timeEntryRepository {
+	return &timeEntryRepository{
+		store: NewTimeEntryStore(),
+	}
+}
+
+func (r *timeEntryRepository) Create(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	entry.ID = r.store.IDCounter.Next()
+	rtn, err := r.store.AddEntry(entry)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return rtn, nil
+}
+
+func (r *timeEntryRepository) StartTimer(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	entry.ID = r.store.IDCounter.Next()
+	entry.End = nil
+	rtn, err := r.store.StartTimer(entry)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return rtn, nil
+}
+
+func (r *timeEntryRepository) StopTimer(ctx context.Context, taskID int64, userID int64, end time.Time) (domain.TimeEntry, error) {
+	rtn, err := r.store.StopTimer(taskID, userID, end)
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	return rtn, nil
+}
+
+func (r *timeEntryRepository) ListByTask(ctx context.Context, taskID int64) ([]domain.TimeEntry, error) {
+	return r.store.Entries(func(entry domain.TimeEntry) bool {
+		return entry.TaskID == taskID
+	}), nil
+}
+
+func (r *timeEntryRepository) ListBetween(ctx context.Context, from time.Time, to time.Time, userID int64) ([]domain.TimeEntry, error) {
+	return r.store.Entries(func(entry domain.TimeEntry) bool {
+		if userID != 0 && entry.UserID != userID {
+			return false
+		}
+		return entry.Start.Before(to) && (entry.End == nil || entry.End.After(from))
+	}), nil
+}
@@ -0,0 +1,142 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func Test_timeEntryRepository_StartTimer(t *testing.T) {
+	tests := []struct {
+		name    string
+		running domain.TimeEntry
+		entry   domain.TimeEntry
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			running: domain.TimeEntry{TaskID: 1, UserID: 2, Start: fixedNow()},
+			entry:   domain.TimeEntry{TaskID: 2, UserID: 1, Start: fixedNow()},
+			wantErr: nil,
+		},
+		{
+			name:    "RunningOnOtherTask",
+			running: domain.TimeEntry{TaskID: 1, UserID: 1, Start: fixedNow()},
+			entry:   domain.TimeEntry{TaskID: 2, UserID: 1, Start: fixedNow()},
+			wantErr: domain.ErrTimerRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTimeEntryRepository()
+			if _, err := r.StartTimer(context.Background(), tt.running); err != nil {
+				t.Fatalf("StartTimer() error = %v", err)
+			}
+			got, err := r.StartTimer(context.Background(), tt.entry)
+			if err != tt.wantErr {
+				t.Errorf("StartTimer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.ID != 2 || got.End != nil) {
+				t.Errorf("StartTimer() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_timeEntryRepository_StopTimer(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskID  int64
+		userID  int64
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			taskID:  1,
+			userID:  1,
+			wantErr: nil,
+		},
+		{
+			name:    "OtherTask",
+			taskID:  2,
+			userID:  1,
+			wantErr: domain.ErrTimerNotRunning,
+		},
+		{
+			name:    "OtherUser",
+			taskID:  1,
+			userID:  2,
+			wantErr: domain.ErrTimerNotRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTimeEntryRepository()
+			_, _ = r.StartTimer(context.Background(), domain.TimeEntry{TaskID: 1, UserID: 1, Start: fixedNow()})
+			end := fixedNow().Add(time.Hour)
+			got, err := r.StopTimer(context.Background(), tt.taskID, tt.userID, end)
+			if err != tt.wantErr {
+				t.Errorf("StopTimer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.End == nil || !got.End.Equal(end)) {
+				t.Errorf("StopTimer() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_timeEntryRepository_ListBetween(t *testing.T) {
+	r := NewTimeEntryRepository()
+	day := fixedNow()
+	for _, entry := range []domain.TimeEntry{
+		{TaskID: 1, UserID: 1, Start: day.Add(-25 * time.Hour), End: timePtr(day.Add(-24 * time.Hour))},
+		{TaskID: 1, UserID: 1, Start: day.Add(-11 * time.Hour), End: timePtr(day.Add(-9 * time.Hour))},
+		{TaskID: 2, UserID: 2, Start: day, End: timePtr(day.Add(time.Hour))},
+		{TaskID: 2, UserID: 1, Start: day.Add(30 * time.Hour), End: timePtr(day.Add(31 * time.Hour))},
+	} {
+		_, _ = r.Create(context.Background(), entry)
+	}
+	_, _ = r.StartTimer(context.Background(), domain.TimeEntry{TaskID: 3, UserID: 3, Start: day.Add(-48 * time.Hour)})
+
+	from := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	tests := []struct {
+		name    string
+		userID  int64
+		wantIDs []int64
+	}{
+		{
+			name:    "AllUsers",
+			userID:  0,
+			wantIDs: []int64{5, 2, 3},
+		},
+		{
+			name:    "SingleUser",
+			userID:  1,
+			wantIDs: []int64{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ListBetween(context.Background(), from, to, tt.userID)
+			if err != nil {
+				t.Fatalf("ListBetween() error = %v", err)
+			}
+			ids := make([]int64, 0, len(got))
+			for _, entry := range got {
+				ids = append(ids, entry.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ListBetween() got = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("ListBetween() got = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sync"
+	"time"
+)
+
+type UndoStore struct {
+	Mu      *sync.Mutex
+	entries map[string]domain.UndoEntry
+	// lastByActor maps an actor to the token of their last operation.
+	lastByActor map[int64]string
+	now         func() time.Time
+}
+
+func (s *UndoStore) SaveEntry(entry domain.UndoEntry) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	now := s.now()
+	for token, existing := range s.entries {
+		if now.After(existing.ExpiresAt) {
+			s.deleteEntry(token)
+		}
+	}
+	if entry.ActorID != nil {
+		if previous, ok := s.lastByActor[*entry.ActorID]; ok {
+			s.deleteEntry(previous)
+		}
+		s.lastByActor[*entry.ActorID] = entry.Token
+	}
+	s.entries[entry.Token] = entry
+}
+
+func (s *UndoStore) GetEntry(token string) (domain.UndoEntry, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	entry, ok := s.entries[token]
+	if !ok {
+		return domain.UndoEntry{}, domain.ErrUndoNotFound
+	}
+	return entry, nil
+}
+
+func (s *UndoStore) DeleteEntry(token string) error {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.entries[token]; !ok {
+		return domain.ErrUndoNotFound
+	}
+	s.deleteEntry(token)
+	return nil
+}
+
+// deleteEntry removes the entry and its actor index. The caller must hold s.Mu.
+func (s *UndoStore) deleteEntry(token string) {
+	entry := s.entries[token]
+	if entry.ActorID != nil && s.lastByActor[*entry.ActorID] == token {
+		delete(s.lastByActor, *entry.ActorID)
+	}
+	delete(s.entries, token)
+}
+
+func NewUndoStore() *UndoStore {
+	mu := sync.Mutex{}
+	return &UndoStore{
+		Mu:          &mu,
+		entries:     map[string]domain.UndoEntry{},
+		lastByActor: map[int64]string{},
+		now:         time.Now,
+	}
+}
+
+type undoRepository struct {
+	store *UndoStore
+}
+
+func NewUndoRepository() *undoRepository {
+	return &undoRepository{
+		store: NewUndoStore(),
+	}
+}
+
+func (r *undoRepository) Save(ctx context.Context, entry domain.UndoEntry) error {
+	r.store.SaveEntry(entry)
+	return nil
+}
+
+func (r *undoRepository) Get(ctx context.Context, token string) (domain.UndoEntry, error) {
+	return r.store.GetEntry(token)
+}
+
+func (r *undoRepository) Delete(ctx context.Context, token string) error {
+	return r.store.DeleteEntry(token)
+}
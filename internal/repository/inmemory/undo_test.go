@@ -0,0 +1,67 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func Test_undoRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewUndoStore()
+	store.now = fixedNow
+	r := &undoRepository{
+		store: store,
+	}
+	actorID := int64(3)
+	_ = r.Save(ctx, domain.UndoEntry{Token: "expired", ExpiresAt: fixedNow().Add(-time.Second)})
+	_ = r.Save(ctx, domain.UndoEntry{Token: "anonymous", ExpiresAt: fixedNow().Add(time.Minute)})
+	_ = r.Save(ctx, domain.UndoEntry{Token: "first", ActorID: &actorID, ExpiresAt: fixedNow().Add(time.Minute)})
+	_ = r.Save(ctx, domain.UndoEntry{Token: "second", ActorID: &actorID, ExpiresAt: fixedNow().Add(time.Minute)})
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:    "Anonymous",
+			token:   "anonymous",
+			wantErr: nil,
+		},
+		{
+			name:    "LastOfActor",
+			token:   "second",
+			wantErr: nil,
+		},
+		{
+			name:    "ReplacedByActor",
+			token:   "first",
+			wantErr: domain.ErrUndoNotFound,
+		},
+		{
+			name:    "Expired",
+			token:   "expired",
+			wantErr: domain.ErrUndoNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Get(ctx, tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Token != tt.token {
+				t.Errorf("Get() got = %v, want token %s", got, tt.token)
+			}
+		})
+	}
+
+	if err := r.Delete(ctx, "second"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := r.Delete(ctx, "second"); err != domain.ErrUndoNotFound {
+		t.Errorf("Delete() error = %v, want %v", err, domain.ErrUndoNotFound)
+	}
+}
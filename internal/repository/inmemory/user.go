@@ -0,0 +1,92 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+)
+
+type UserStore struct {
+	Mu        *sync.Mutex
+	IDCounter *TaskIDCounter
+	users     map[int64]*domain.User
+}
+
+func (s *UserStore) AddUser(user domain.User) (domain.User, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.users[user.ID]; ok {
+		return domain.User{}, domain.ErrWrongID
+	}
+	s.users[user.ID] = &user
+	return *s.users[user.ID], nil
+}
+
+func (s *UserStore) GetUser(id int64) (domain.User, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return *s.users[id], nil
+}
+
+func (s *UserStore) Users() []domain.User {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	users := make([]domain.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, *user)
+	}
+	return users
+}
+
+func NewUserStore() *UserStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &UserStore{
+		Mu:        &mu2,
+		IDCounter: NewTaskIDCounter(&mu1),
+		users:     map[int64]*domain.User{},
+	}
+}
+
+type userRepository struct {
+	store *UserStore
+}
+
+func NewUserRepository() *userRepository {
+	return &userRepository{
+		store: NewUserStore(),
+	}
+}
+
+func (r *userRepository) List(ctx context.Context) ([]domain.User, error) {
+	users := r.store.Users()
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users, nil
+}
+
+func (r *userRepository) Create(ctx context.Context, name string) (domain.User, error) {
+	id := r.store.IDCounter.Next()
+	user := domain.User{
+		ID:   id,
+		Name: name,
+	}
+	rtn, err := r.store.AddUser(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return rtn, nil
+}
+
+func (r *userRepository) Get(ctx context.Context, id int64) (domain.User, error) {
+	rtn, err := r.store.GetUser(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return rtn, nil
+}
@@ -0,0 +1,210 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func Test_userRepository_Create(t *testing.T) {
+	type fields struct {
+		store *UserStore
+	}
+	type args struct {
+		ctx  context.Context
+		name string
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *UserStore)
+		fields     fields
+		args       args
+		want       domain.User
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *UserStore) {
+			},
+			fields: fields{
+				store: NewUserStore(),
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "userName",
+			},
+			want: domain.User{
+				ID:   1,
+				Name: "userName",
+			},
+			wantErr: false,
+		},
+		{
+			name: "WrongID",
+			buildStubs: func(store *UserStore) {
+				store.users[1] = &domain.User{
+					ID:   1,
+					Name: "userName1",
+				}
+			},
+			fields: fields{
+				store: NewUserStore(),
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "userName",
+			},
+			want:    domain.User{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &userRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.Create(tt.args.ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userRepository_List(t *testing.T) {
+	type fields struct {
+		store *UserStore
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *UserStore)
+		fields     fields
+		args       args
+		want       []domain.User
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *UserStore) {
+				id := store.IDCounter.Next()
+				store.users[id] = &domain.User{ID: id, Name: "userName1"}
+				id = store.IDCounter.Next()
+				store.users[id] = &domain.User{ID: id, Name: "userName2"}
+			},
+			fields: fields{
+				store: NewUserStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: []domain.User{
+				{ID: 1, Name: "userName1"},
+				{ID: 2, Name: "userName2"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OKEmpty",
+			buildStubs: func(store *UserStore) {
+			},
+			fields: fields{
+				store: NewUserStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    []domain.User{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &userRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.List(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userRepository_Get(t *testing.T) {
+	type fields struct {
+		store *UserStore
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(store *UserStore)
+		fields     fields
+		args       args
+		want       domain.User
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *UserStore) {
+				id := store.IDCounter.Next()
+				store.users[id] = &domain.User{ID: id, Name: "userName1"}
+			},
+			fields: fields{
+				store: NewUserStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want:    domain.User{ID: 1, Name: "userName1"},
+			wantErr: false,
+		},
+		{
+			name: "NotExists",
+			buildStubs: func(store *UserStore) {
+			},
+			fields: fields{
+				store: NewUserStore(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want:    domain.User{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &userRepository{
+				store: tt.fields.store,
+			}
+			tt.buildStubs(r.store)
+			got, err := r.Get(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type WebhookStore struct {
+	Mu            *sync.Mutex
+	IDCounter     *TaskIDCounter
+	subscriptions map[int64]*domain.WebhookSubscription
+	now           func() time.Time
+}
+
+func (s *WebhookStore) AddSubscription(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.subscriptions[subscription.ID]; ok {
+		return domain.WebhookSubscription{}, domain.ErrWrongID
+	}
+	subscription.CreatedAt = s.now()
+	s.subscriptions[subscription.ID] = &subscription
+	return *s.subscriptions[subscription.ID], nil
+}
+
+func (s *WebhookStore) GetSubscription(id int64) (domain.WebhookSubscription, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.subscriptions[id]; !ok {
+		return domain.WebhookSubscription{}, domain.ErrDataNotFound
+	}
+	return *s.subscriptions[id], nil
+}
+
+func (s *WebhookStore) DeleteSubscription(id int64) error {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.subscriptions[id]; !ok {
+		return domain.ErrDataNotFound
+	}
+	delete(s.subscriptions, id)
+	return nil
+}
+
+func (s *WebhookStore) Subscriptions() []domain.WebhookSubscription {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	subscriptions := make([]domain.WebhookSubscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions
+}
+
+func NewWebhookStore() *WebhookStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &WebhookStore{
+		Mu:            &mu2,
+		IDCounter:     NewTaskIDCounter(&mu1),
+		subscriptions: map[int64]*domain.WebhookSubscription{},
+		now:           time.Now,
+	}
+}
+
+type webhookRepository struct {
+	store *WebhookStore
+}
+
+func NewWebhookRepository() *webhookRepository {
+	return &webhookRepository{
+		store: NewWebhookStore(),
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	subscription.ID = r.store.IDCounter.Next()
+	rtn, err := r.store.AddSubscription(subscription)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return rtn, nil
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.store.Subscriptions(), nil
+}
+
+func (r *webhookRepository) Get(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+	rtn, err := r.store.GetSubscription(id)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return rtn, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	err := r.store.DeleteSubscription(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MaxFinishedDeliveries is the number of delivered and dead deliveries kept, the
+// dead-letter list never grows beyond it.
+const MaxFinishedDeliveries = 1000
+
+type WebhookDeliveryStore struct {
+	Mu         *sync.Mutex
+	IDCounter  *TaskIDCounter
+	deliveries map[int64]*domain.WebhookDelivery
+	// maxFinished is the number of finished deliveries kept, the oldest ones go first.
+	maxFinished int
+}
+
+// AddDeliveries adds all the deliveries or none of them.
+func (s *WebhookDeliveryStore) AddDeliveries(deliveries []domain.WebhookDelivery) ([]domain.WebhookDelivery, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	for _, delivery := range deliveries {
+		if _, ok := s.deliveries[delivery.ID]; ok {
+			return nil, domain.ErrWrongID
+		}
+	}
+	for i := range deliveries {
+		delivery := deliveries[i]
+		s.deliveries[delivery.ID] = &delivery
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookDeliveryStore) GetDelivery(id int64) (domain.WebhookDelivery, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.deliveries[id]; !ok {
+		return domain.WebhookDelivery{}, domain.ErrDataNotFound
+	}
+	return *s.deliveries[id], nil
+}
+
+// UpdateDelivery stores the delivery and drops the oldest finished deliveries beyond
+// maxFinished.
+func (s *WebhookDeliveryStore) UpdateDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		return domain.WebhookDelivery{}, domain.ErrDataNotFound
+	}
+	s.deliveries[delivery.ID] = &delivery
+	if finishedAt(delivery) != nil {
+		s.pruneFinished()
+	}
+	return delivery, nil
+}
+
+// finishedAt returns when the delivery was delivered or given up, nil while it is pending.
+func finishedAt(delivery domain.WebhookDelivery) *time.Time {
+	if delivery.DeliveredAt != nil {
+		return delivery.DeliveredAt
+	}
+	return delivery.DeadAt
+}
+
+// pruneFinished drops the finished deliveries beyond maxFinished, oldest first. The caller
+// must hold s.Mu.
+func (s *WebhookDeliveryStore) pruneFinished() {
+	finished := make([]*domain.WebhookDelivery, 0)
+	for _, delivery := range s.deliveries {
+		if finishedAt(*delivery) != nil {
+			finished = append(finished, delivery)
+		}
+	}
+	if len(finished) <= s.maxFinished {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		a, b := finishedAt(*finished[i]), finishedAt(*finished[j])
+		if !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return finished[i].ID < finished[j].ID
+	})
+	for _, delivery := range finished[:len(finished)-s.maxFinished] {
+		delete(s.deliveries, delivery.ID)
+	}
+}
+
+// Deliveries returns the deliveries accepted by match ordered by ID.
+func (s *WebhookDeliveryStore) Deliveries(match func(delivery domain.WebhookDelivery) bool) []domain.WebhookDelivery {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range s.deliveries {
+		if match(*delivery) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries
+}
+
+func NewWebhookDeliveryStore() *WebhookDeliveryStore {
+	mu1 := sync.Mutex{}
+	mu2 := sync.Mutex{}
+	return &WebhookDeliveryStore{
+		Mu:          &mu2,
+		IDCounter:   NewTaskIDCounter(&mu1),
+		deliveries:  map[int64]*domain.WebhookDelivery{},
+		maxFinished: MaxFinishedDeliveries,
+	}
+}
+
+type webhookDeliveryRepository struct {
+	store *WebhookDeliveryStore
+}
+
+func NewWebhookDeliveryRepository() *webhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		store: NewWebhookDeliveryStore(),
+	}
+}
+
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) ([]domain.WebhookDelivery, error) {
+	queued := make([]domain.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		delivery.ID = r.store.IDCounter.Next()
+		queued = append(queued, delivery)
+	}
+	rtn, err := r.store.AddDeliveries(queued)
+	if err != nil {
+		return nil, err
+	}
+	return rtn, nil
+}
+
+func (r *webhookDeliveryRepository) Due(ctx context.Context, now time.Time) ([]domain.WebhookDelivery, error) {
+	return r.store.Deliveries(func(delivery domain.WebhookDelivery) bool {
+		return delivery.DeliveredAt == nil && delivery.DeadAt == nil && !delivery.NextAttemptAt.After(now)
+	}), nil
+}
+
+func (r *webhookDeliveryRepository) Get(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	rtn, err := r.store.GetDelivery(id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return rtn, nil
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	rtn, err := r.store.UpdateDelivery(delivery)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return rtn, nil
+}
+
+func (r *webhookDeliveryRepository) ListDead(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	return r.store.Deliveries(func(delivery domain.WebhookDelivery) bool {
+		return delivery.DeadAt != nil
+	}), nil
+}
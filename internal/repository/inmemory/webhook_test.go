@@ -0,0 +1,75 @@
+package inmemory
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func Test_webhookDeliveryRepository_Due(t *testing.T) {
+	r := NewWebhookDeliveryRepository()
+	ctx := context.Background()
+	now := fixedNow()
+	queued, err := r.Enqueue(ctx, []domain.WebhookDelivery{
+		{SubscriptionID: 1, NextAttemptAt: now.Add(-time.Minute)},
+		{SubscriptionID: 1, NextAttemptAt: now},
+		{SubscriptionID: 2, NextAttemptAt: now.Add(time.Minute)},
+		{SubscriptionID: 2, NextAttemptAt: now.Add(-time.Minute)},
+		{SubscriptionID: 3, NextAttemptAt: now.Add(-time.Minute)},
+	})
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	delivered := queued[3]
+	delivered.DeliveredAt = timePtr(now)
+	_, _ = r.Update(ctx, delivered)
+	dead := queued[4]
+	dead.DeadAt = timePtr(now)
+	_, _ = r.Update(ctx, dead)
+
+	due, _ := r.Due(ctx, now)
+	if len(due) != 2 || due[0].ID != 1 || due[1].ID != 2 {
+		t.Errorf("Due() = %v, want deliveries 1 and 2", due)
+	}
+	if got, _ := r.ListDead(ctx); len(got) != 1 || got[0].ID != 5 {
+		t.Errorf("ListDead() = %v, want delivery 5", got)
+	}
+	if _, err := r.Update(ctx, domain.WebhookDelivery{ID: 9}); err != domain.ErrDataNotFound {
+		t.Errorf("Update() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_webhookDeliveryRepository_Prune(t *testing.T) {
+	r := NewWebhookDeliveryRepository()
+	r.store.maxFinished = 2
+	ctx := context.Background()
+	now := fixedNow()
+	queued, _ := r.Enqueue(ctx, []domain.WebhookDelivery{
+		{SubscriptionID: 1, NextAttemptAt: now},
+		{SubscriptionID: 1, NextAttemptAt: now},
+		{SubscriptionID: 1, NextAttemptAt: now},
+		{SubscriptionID: 1, NextAttemptAt: now},
+	})
+	for i, delivery := range queued[:3] {
+		finished := now.Add(time.Duration(i) * time.Minute)
+		if i == 1 {
+			delivery.DeliveredAt = &finished
+		} else {
+			delivery.DeadAt = &finished
+		}
+		_, _ = r.Update(ctx, delivery)
+	}
+
+	if _, err := r.Get(ctx, 1); err != domain.ErrDataNotFound {
+		t.Errorf("Get() of the oldest finished delivery error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	for _, id := range []int64{2, 3, 4} {
+		if _, err := r.Get(ctx, id); err != nil {
+			t.Errorf("Get(%d) error = %v", id, err)
+		}
+	}
+	if got, _ := r.ListDead(ctx); len(got) != 1 || got[0].ID != 3 {
+		t.Errorf("ListDead() = %v, want delivery 3", got)
+	}
+}
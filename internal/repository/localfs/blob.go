@@ -0,0 +1,119 @@
+package localfs
+
+import (
+	"context"
+	"io"
+	"oa-gogolook/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type blobStore struct {
+	dir string
+}
+
+func NewBlobStore(dir string) (*blobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &blobStore{
+		dir: dir,
+	}, nil
+}
+
+// path maps a key to a file below dir and rejects keys escaping it.
+func (s *blobStore) path(key string) (string, error) {
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", domain.ErrInvalidParameters
+	}
+	return p, nil
+}
+
+func (s *blobStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return 0, err
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(p)
+		return n, err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(p)
+		return n, err
+	}
+	return n, nil
+}
+
+func (s *blobStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *blobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DeletePrefix removes every blob whose key starts with prefix, a directory whose keys all
+// start with it is removed as a whole.
+func (s *blobStore) DeletePrefix(ctx context.Context, prefix string) error {
+	err := filepath.Walk(s.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if info.IsDir() {
+			if strings.HasPrefix(key+"/", prefix) {
+				if err := os.RemoveAll(p); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			if !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(key, prefix) {
+			return os.Remove(p)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package localfs
+
+import (
+	"context"
+	"io/ioutil"
+	"oa-gogolook/internal/domain"
+	"strings"
+	"testing"
+)
+
+func Test_blobStore_PutOpenDelete(t *testing.T) {
+	s, err := NewBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewBlobStore() error = %v", err)
+	}
+	n, err := s.Put(context.Background(), "task-1/abc", strings.NewReader("hello"))
+	if err != nil || n != 5 {
+		t.Fatalf("Put() = %v, %v", n, err)
+	}
+	f, err := s.Open(context.Background(), "task-1/abc")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	data, _ := ioutil.ReadAll(f)
+	_ = f.Close()
+	if string(data) != "hello" {
+		t.Errorf("Open() got = %q, want %q", data, "hello")
+	}
+	if err := s.Delete(context.Background(), "task-1/abc"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Open(context.Background(), "task-1/abc"); err != domain.ErrDataNotFound {
+		t.Errorf("Open() after Delete() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_blobStore_DeletePrefix(t *testing.T) {
+	s, err := NewBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewBlobStore() error = %v", err)
+	}
+	for _, key := range []string{"task-1/abc", "task-1/def", "task-11/abc", "task-11/def"} {
+		if _, err := s.Put(context.Background(), key, strings.NewReader("hello")); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	if err := s.DeletePrefix(context.Background(), "task-11/d"); err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if _, err := s.Open(context.Background(), "task-11/def"); err != domain.ErrDataNotFound {
+		t.Errorf("Open() after DeletePrefix() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if err := s.DeletePrefix(context.Background(), "task-1/"); err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	for _, key := range []string{"task-1/abc", "task-1/def"} {
+		if _, err := s.Open(context.Background(), key); err != domain.ErrDataNotFound {
+			t.Errorf("Open(%q) after DeletePrefix() error = %v, want %v", key, err, domain.ErrDataNotFound)
+		}
+	}
+	f, err := s.Open(context.Background(), "task-11/abc")
+	if err != nil {
+		t.Fatalf("DeletePrefix() removed the blob of another task, error = %v", err)
+	}
+	_ = f.Close()
+	if err := s.DeletePrefix(context.Background(), "task-2/"); err != nil {
+		t.Errorf("DeletePrefix() without blobs error = %v", err)
+	}
+}
+
+func Test_blobStore_path(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{
+			name:    "OK",
+			key:     "task-1/abc",
+			wantErr: false,
+		},
+		{
+			name:    "Traversal",
+			key:     "../outside",
+			wantErr: true,
+		},
+		{
+			name:    "Root",
+			key:     ".",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &blobStore{dir: t.TempDir()}
+			if _, err := s.path(tt.key); (err != nil) != tt.wantErr {
+				t.Errorf("path() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package localfs
+
+import (
+	"context"
+	"encoding/json"
+	"oa-gogolook/internal/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// reminderFile is the content of the reminder file.
+type reminderFile struct {
+	LastID    int64             `json:"lastId"`
+	Reminders []domain.Reminder `json:"reminders"`
+}
+
+// reminderRepository keeps the reminders in memory and rewrites the whole reminder file on
+// every change, a change is only kept once the file is written so the scheduler finds the
+// same reminders after a restart.
+type reminderRepository struct {
+	mu        sync.Mutex
+	path      string
+	lastID    int64
+	reminders map[int64]domain.Reminder
+}
+
+func NewReminderRepository(dir string) (*reminderRepository, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	r := &reminderRepository{
+		path:      filepath.Join(dir, "reminders.json"),
+		reminders: map[int64]domain.Reminder{},
+	}
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file reminderFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	r.lastID = file.LastID
+	for _, reminder := range file.Reminders {
+		r.reminders[reminder.ID] = reminder
+	}
+	return r, nil
+}
+
+// save writes the reminders to a temporary file and renames it over the reminder file.
+// The caller must hold r.mu.
+func (r *reminderRepository) save(lastID int64, reminders map[int64]domain.Reminder) error {
+	file := reminderFile{
+		LastID:    lastID,
+		Reminders: sorted(reminders, func(domain.Reminder) bool { return true }),
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// put stores the reminder if the reminder file can be written. The caller must hold r.mu.
+func (r *reminderRepository) put(lastID int64, reminder domain.Reminder) error {
+	reminders := make(map[int64]domain.Reminder, len(r.reminders)+1)
+	for id, existing := range r.reminders {
+		reminders[id] = existing
+	}
+	reminders[reminder.ID] = reminder
+	if err := r.save(lastID, reminders); err != nil {
+		return err
+	}
+	r.lastID = lastID
+	r.reminders = reminders
+	return nil
+}
+
+func sorted(reminders map[int64]domain.Reminder, match func(domain.Reminder) bool) []domain.Reminder {
+	rtn := make([]domain.Reminder, 0)
+	for _, reminder := range reminders {
+		if match(reminder) {
+			rtn = append(rtn, reminder)
+		}
+	}
+	sort.Slice(rtn, func(i, j int) bool {
+		return rtn[i].ID < rtn[j].ID
+	})
+	return rtn
+}
+
+func (r *reminderRepository) Create(ctx context.Context, reminder domain.Reminder) (domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminder.ID = r.lastID + 1
+	if err := r.put(reminder.ID, reminder); err != nil {
+		return domain.Reminder{}, err
+	}
+	return reminder, nil
+}
+
+func (r *reminderRepository) Get(ctx context.Context, id int64) (domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminder, ok := r.reminders[id]
+	if !ok {
+		return domain.Reminder{}, domain.ErrDataNotFound
+	}
+	return reminder, nil
+}
+
+func (r *reminderRepository) ListByTask(ctx context.Context, taskID int64) ([]domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sorted(r.reminders, func(reminder domain.Reminder) bool {
+		return reminder.TaskID == taskID
+	}), nil
+}
+
+func (r *reminderRepository) ListPending(ctx context.Context) ([]domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sorted(r.reminders, func(reminder domain.Reminder) bool {
+		return reminder.FiredAt == nil && reminder.FailedAt == nil
+	}), nil
+}
+
+func (r *reminderRepository) UpdateMany(ctx context.Context, updated []domain.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminders := make(map[int64]domain.Reminder, len(r.reminders))
+	for id, existing := range r.reminders {
+		reminders[id] = existing
+	}
+	for _, reminder := range updated {
+		if _, ok := reminders[reminder.ID]; ok {
+			reminders[reminder.ID] = reminder
+		}
+	}
+	if err := r.save(r.lastID, reminders); err != nil {
+		return err
+	}
+	r.reminders = reminders
+	return nil
+}
+
+func (r *reminderRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reminders[id]; !ok {
+		return domain.ErrDataNotFound
+	}
+	reminders := make(map[int64]domain.Reminder, len(r.reminders))
+	for existing, reminder := range r.reminders {
+		if existing != id {
+			reminders[existing] = reminder
+		}
+	}
+	if err := r.save(r.lastID, reminders); err != nil {
+		return err
+	}
+	r.reminders = reminders
+	return nil
+}
+
+func (r *reminderRepository) DeleteByTask(ctx context.Context, taskID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminders := make(map[int64]domain.Reminder, len(r.reminders))
+	for id, reminder := range r.reminders {
+		if reminder.TaskID != taskID {
+			reminders[id] = reminder
+		}
+	}
+	if len(reminders) == len(r.reminders) {
+		return nil
+	}
+	if err := r.save(r.lastID, reminders); err != nil {
+		return err
+	}
+	r.reminders = reminders
+	return nil
+}
@@ -0,0 +1,94 @@
+package localfs
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_reminderRepository_Restart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r, err := NewReminderRepository(dir)
+	if err != nil {
+		t.Fatalf("NewReminderRepository() error = %v", err)
+	}
+	at := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	for _, taskID := range []int64{1, 1, 2} {
+		if _, err := r.Create(ctx, domain.Reminder{TaskID: taskID, At: &at}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	fired, _ := r.Get(ctx, 1)
+	fired.FiredAt = &at
+	if err := r.UpdateMany(ctx, []domain.Reminder{fired, {ID: 9, TaskID: 9}}); err != nil {
+		t.Fatalf("UpdateMany() error = %v", err)
+	}
+	if err := r.Delete(ctx, 3); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	restarted, err := NewReminderRepository(dir)
+	if err != nil {
+		t.Fatalf("NewReminderRepository() error = %v", err)
+	}
+	want, _ := r.ListByTask(ctx, 1)
+	if got, _ := restarted.ListByTask(ctx, 1); !reflect.DeepEqual(got, want) || len(got) != 2 {
+		t.Errorf("ListByTask() after restart = %v, want %v", got, want)
+	}
+	if got, _ := restarted.ListPending(ctx); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("ListPending() after restart = %v", got)
+	}
+	if _, err := restarted.Get(ctx, 3); err != domain.ErrDataNotFound {
+		t.Errorf("Get() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if created, _ := restarted.Create(ctx, domain.Reminder{TaskID: 3, At: &at}); created.ID != 4 {
+		t.Errorf("Create() after restart got ID %d, want 4", created.ID)
+	}
+}
+
+func Test_reminderRepository_DeleteByTask(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r, err := NewReminderRepository(dir)
+	if err != nil {
+		t.Fatalf("NewReminderRepository() error = %v", err)
+	}
+	at := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	for _, taskID := range []int64{1, 2, 1} {
+		_, _ = r.Create(ctx, domain.Reminder{TaskID: taskID, At: &at})
+	}
+	if err := r.DeleteByTask(ctx, 1); err != nil {
+		t.Fatalf("DeleteByTask() error = %v", err)
+	}
+	restarted, err := NewReminderRepository(dir)
+	if err != nil {
+		t.Fatalf("NewReminderRepository() error = %v", err)
+	}
+	if got, _ := restarted.ListPending(ctx); len(got) != 1 || got[0].TaskID != 2 {
+		t.Errorf("ListPending() after DeleteByTask() = %v", got)
+	}
+}
+
+func Test_reminderRepository_FailedWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r, err := NewReminderRepository(dir)
+	if err != nil {
+		t.Fatalf("NewReminderRepository() error = %v", err)
+	}
+	// A directory in place of the temporary file makes every write fail.
+	if err := os.Mkdir(filepath.Join(dir, "reminders.json.tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Create(ctx, domain.Reminder{TaskID: 1}); err == nil {
+		t.Fatalf("Create() error = nil, want an error")
+	}
+	if got, _ := r.ListPending(ctx); len(got) != 0 {
+		t.Errorf("ListPending() after failed write = %v", got)
+	}
+}
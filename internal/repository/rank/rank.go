@@ -0,0 +1,201 @@
+// Package rank implements fractional indexing: ranks are base-62 strings compared
+// lexicographically, and a new rank can always be generated between two others.
+package rank
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// MaxLength is the rank length above which repositories should Respread their ranks.
+const MaxLength = 16
+
+func digit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	default:
+		return int(c-'a') + 36
+	}
+}
+
+// Between returns a rank strictly between a and b. An empty a means "before everything"
+// and an empty b "after everything"; a must sort before b when both are set.
+// Ranks never end with the zero digit, which keeps them comparable as fractions.
+func Between(a string, b string) string {
+	// Skip the common prefix, missing digits of a count as zero.
+	n := 0
+	for n < len(b) {
+		ca := byte('0')
+		if n < len(a) {
+			ca = a[n]
+		}
+		if ca != b[n] {
+			break
+		}
+		n++
+	}
+	if n > 0 {
+		rest := ""
+		if n < len(a) {
+			rest = a[n:]
+		}
+		return b[:n] + Between(rest, b[n:])
+	}
+
+	da := 0
+	if a != "" {
+		da = digit(a[0])
+	}
+	db := base
+	if b != "" {
+		db = digit(b[0])
+	}
+	if db-da > 1 {
+		return string(digits[(da+db)/2])
+	}
+	// The first digits are adjacent: b's first digit alone sorts before b if b goes on,
+	// otherwise extend a.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[da]) + Between(rest, "")
+}
+
+// After returns a rank after a for appending. Unlike Between(a, "") it steps up by one at a
+// fixed digit instead of halving the rest of the range: the digit is the (2k+1)th for a
+// starting with k 'z' digits, so every extra leading 'z' makes room for base times more
+// ranks and appending n ranks keeps them about 2*log62(n) digits long.
+func After(a string) string {
+	if a == "" {
+		return Between("", "")
+	}
+	k := 0
+	for k < len(a) && a[k] == 'z' {
+		k++
+	}
+	b := []byte(a)
+	for len(b) < 2*k+1 {
+		b = append(b, '0')
+	}
+	b = b[:2*k+1]
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != 'z' {
+			b[i] = digits[digit(b[i])+1]
+			break
+		}
+		b[i] = '0'
+	}
+	end := len(b)
+	for end > 0 && b[end-1] == '0' {
+		end--
+	}
+	return string(b[:end])
+}
+
+// maxSpreadLength is the longest rank SpreadBetween generates, base^maxSpreadLength still
+// fits in a uint64.
+const maxSpreadLength = 10
+
+// SpreadBetween returns n evenly spaced ranks in ascending order strictly between a and b,
+// with the same meaning of empty bounds as Between, all as short as possible. An empty b
+// only spreads them over the lower half of the ranks after a. It returns nil when they
+// would be longer than maxSpreadLength.
+func SpreadBetween(a string, b string, n int) []string {
+	capacity := uint64(1)
+	for length := 1; length <= maxSpreadLength; length++ {
+		capacity *= uint64(base)
+		lower := truncate(a, length)
+		upper := truncate(b, length)
+		if b == "" {
+			// Only the lower half of an open end is used, the rest is left for appending.
+			upper = lower + (capacity-lower)/2
+		}
+		if upper <= lower || upper-lower <= uint64(n) {
+			continue
+		}
+		step := (upper - lower) / uint64(n+1)
+		ranks := make([]string, n)
+		for i := range ranks {
+			ranks[i] = encode(lower+uint64(i+1)*step, length)
+		}
+		return ranks
+	}
+	return nil
+}
+
+// truncate reads the first length digits of r as an integer, missing digits count as zero.
+func truncate(r string, length int) uint64 {
+	var v uint64
+	for i := 0; i < length; i++ {
+		v *= uint64(base)
+		if i < len(r) {
+			v += uint64(digit(r[i]))
+		}
+	}
+	return v
+}
+
+// Respread picks the ranks to rewrite when a rank of ranks[lo:hi] grew beyond MaxLength.
+// ranks is in ascending order, the window around lo and hi doubles until its ranks can be
+// spread again between the ranks right outside of it, so only the neighbourhood changes.
+// It returns the index of the first rewritten rank and the new ranks.
+func Respread(ranks []string, lo int, hi int) (int, []string) {
+	for size := 8; ; size *= 2 {
+		start, end := lo-size, hi+size
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ranks) {
+			end = len(ranks)
+		}
+		a, b := "", ""
+		if start > 0 {
+			a = ranks[start-1]
+		}
+		if end < len(ranks) {
+			b = ranks[end]
+		}
+		if spread := SpreadBetween(a, b, end-start); spread != nil {
+			return start, spread
+		}
+		if start == 0 && end == len(ranks) {
+			return 0, Spread(len(ranks))
+		}
+	}
+}
+
+// Spread returns n evenly spaced ranks in ascending order, all as short as possible.
+func Spread(n int) []string {
+	length := 1
+	capacity := uint64(base)
+	for capacity < uint64(n+1)*uint64(base) {
+		length++
+		capacity *= uint64(base)
+	}
+	step := capacity / uint64(n+1)
+	ranks := make([]string, n)
+	for i := range ranks {
+		ranks[i] = encode(uint64(i+1)*step, length)
+	}
+	return ranks
+}
+
+// encode writes v as a fixed length base-62 fraction without trailing zero digits.
+func encode(v uint64, length int) string {
+	b := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		b[i] = digits[v%uint64(base)]
+		v /= uint64(base)
+	}
+	end := length
+	for end > 0 && b[end-1] == '0' {
+		end--
+	}
+	return string(b[:end])
+}
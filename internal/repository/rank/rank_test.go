@@ -0,0 +1,174 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{name: "Empty", a: "", b: ""},
+		{name: "First", a: "", b: "V"},
+		{name: "Last", a: "V", b: ""},
+		{name: "Gap", a: "A", b: "Z"},
+		{name: "Adjacent", a: "A", b: "B"},
+		{name: "AdjacentLongerB", a: "A", b: "B5"},
+		{name: "Prefix", a: "V", b: "V1"},
+		{name: "CommonPrefix", a: "Vz", b: "W"},
+		{name: "Smallest", a: "", b: "01"},
+		{name: "Largest", a: "z", b: ""},
+		{name: "Deep", a: "zzzz", b: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Between(tt.a, tt.b)
+			if got <= tt.a || (tt.b != "" && got >= tt.b) {
+				t.Errorf("Between(%q, %q) = %q, not in between", tt.a, tt.b, got)
+			}
+			if strings.HasSuffix(got, "0") {
+				t.Errorf("Between(%q, %q) = %q ends with zero", tt.a, tt.b, got)
+			}
+		})
+	}
+}
+
+func TestBetweenRandomInserts(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 2000; i++ {
+		pos := rnd.Intn(len(ranks) + 1)
+		a, b := "", ""
+		if pos > 0 {
+			a = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			b = ranks[pos]
+		}
+		r := Between(a, b)
+		ranks = append(ranks[:pos], append([]string{r}, ranks[pos:]...)...)
+	}
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatalf("ranks are not sorted")
+	}
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i] == ranks[i-1] {
+			t.Fatalf("duplicate rank %q", ranks[i])
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 61, 62, 1000, 5000} {
+		ranks := Spread(n)
+		if len(ranks) != n {
+			t.Fatalf("Spread(%d) returned %d ranks", n, len(ranks))
+		}
+		for i, r := range ranks {
+			if r == "" || strings.HasSuffix(r, "0") || len(r) > 4 {
+				t.Fatalf("Spread(%d)[%d] = %q", n, i, r)
+			}
+			if i > 0 && ranks[i-1] >= r {
+				t.Fatalf("Spread(%d) not ascending at %d: %q >= %q", n, i, ranks[i-1], r)
+			}
+		}
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		a    string
+		want string
+	}{
+		{a: "", want: "V"},
+		{a: "V", want: "W"},
+		{a: "Vz", want: "W"},
+		{a: "V1z", want: "W"},
+		{a: "y", want: "z"},
+		{a: "z", want: "z01"},
+		{a: "zyz", want: "zz"},
+		{a: "zz", want: "zz001"},
+	}
+	for _, tt := range tests {
+		if got := After(tt.a); got != tt.want || got <= tt.a {
+			t.Errorf("After(%q) = %q, want %q", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestAfterAppend(t *testing.T) {
+	r := ""
+	for i := 0; i < 100000; i++ {
+		next := After(r)
+		if next <= r || strings.HasSuffix(next, "0") {
+			t.Fatalf("After(%q) = %q", r, next)
+		}
+		r = next
+	}
+	if len(r) > 5 {
+		t.Errorf("rank after 100000 appends = %q, want at most 5 digits", r)
+	}
+}
+
+func TestSpreadBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		n    int
+		nil  bool
+	}{
+		{name: "Empty", a: "", b: "", n: 100},
+		{name: "Gap", a: "A", b: "Z", n: 5},
+		{name: "Narrow", a: "A", b: "B", n: 100},
+		{name: "Last", a: "zzzz", b: "", n: 3},
+		{name: "Full", a: "zzzzzzzzzzzzzzzz", b: "", n: 1, nil: true},
+		{name: "Deep", a: "Vzzzzzzzzzzzzzzz1", b: "W", n: 1, nil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranks := SpreadBetween(tt.a, tt.b, tt.n)
+			if tt.nil {
+				if ranks != nil {
+					t.Fatalf("SpreadBetween(%q, %q, %d) = %v, want nil", tt.a, tt.b, tt.n, ranks)
+				}
+				return
+			}
+			if len(ranks) != tt.n {
+				t.Fatalf("SpreadBetween(%q, %q, %d) returned %d ranks", tt.a, tt.b, tt.n, len(ranks))
+			}
+			prev := tt.a
+			for i, r := range ranks {
+				if r <= prev || (tt.b != "" && r >= tt.b) || strings.HasSuffix(r, "0") || len(r) > maxSpreadLength {
+					t.Fatalf("SpreadBetween(%q, %q, %d)[%d] = %q", tt.a, tt.b, tt.n, i, r)
+				}
+				prev = r
+			}
+		})
+	}
+}
+
+func TestRespread(t *testing.T) {
+	ranks := Spread(1000)
+	// A rank squeezed between two neighbours until it is too long.
+	ranks[500] = ranks[499] + "zzzzzzzzzzzzzzzz1"
+	sort.Strings(ranks)
+	start, spread := Respread(ranks, 500, 501)
+	if start > 500 || start+len(spread) < 501 || len(spread) > 64 {
+		t.Fatalf("Respread() rewrote %d ranks from %d, want a small window around 500", len(spread), start)
+	}
+	copy(ranks[start:], spread)
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatalf("ranks are not sorted after Respread()")
+	}
+	for i, r := range ranks {
+		if len(r) > MaxLength {
+			t.Errorf("rank %d = %q is too long", i, r)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package internal
 
 import (
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 	"log"
+	"net"
+	grpcdelivery "oa-gogolook/internal/delevery/grpc"
 	"oa-gogolook/internal/delevery/http"
 	"oa-gogolook/internal/domain"
 )
@@ -12,9 +15,48 @@ type Server struct {
 	config domain.AppConfig
 }
 
-func NewHttpServer(usecase domain.TaskUseCase, config domain.AppConfig) (*Server, error) {
+// Usecases groups the use cases served by the HTTP and gRPC servers.
+type Usecases struct {
+	Task         domain.TaskUseCase
+	User         domain.UserUseCase
+	Comment      domain.CommentUseCase
+	Attachment   domain.AttachmentUseCase
+	Checklist    domain.ChecklistUseCase
+	History      domain.HistoryUseCase
+	Undo         domain.UndoUseCase
+	TimeTracking domain.TimeTrackingUseCase
+	Velocity     domain.VelocityUseCase
+	Template     domain.TemplateUseCase
+	Reminder     domain.ReminderUseCase
+	Webhook      domain.WebhookUseCase
+	TaskEvents   domain.TaskEventBroker
+	Sync         domain.SyncUseCase
+}
+
+func NewHttpServer(usecases Usecases, config domain.AppConfig) (*Server, error) {
 	router := gin.Default()
-	http.NewTaskHandler(router, usecase)
+	router.Use(http.ActorMiddleware())
+	http.NewTaskHandler(router, usecases.Task)
+	http.NewUserHandler(router, usecases.User)
+	http.NewCommentHandler(router, usecases.Comment)
+	http.NewAttachmentHandler(router, usecases.Attachment, config.MaxAttachmentSize)
+	http.NewChecklistHandler(router, usecases.Checklist)
+	http.NewHistoryHandler(router, usecases.History)
+	http.NewUndoHandler(router, usecases.Undo)
+	http.NewTimeTrackingHandler(router, usecases.TimeTracking)
+	http.NewVelocityHandler(router, usecases.Velocity)
+	http.NewTemplateHandler(router, usecases.Template)
+	http.NewReminderHandler(router, usecases.Reminder)
+	http.NewWebhookHandler(router, usecases.Webhook)
+	http.NewTaskEventHandler(router, usecases.TaskEvents, config.TaskEventHeartbeat)
+	http.NewSyncHandler(router, usecases.Sync)
+	http.NewTaskSocketHandler(router, usecases.Task, usecases.TaskEvents, config.SocketPingInterval, config.SocketSendBuffer)
+	if err := http.NewGraphQLHandler(router, usecases.Task, config.GraphQLMaxDepth, config.GraphQLComplexity); err != nil {
+		return nil, err
+	}
+	if err := http.NewOpenAPIHandler(router); err != nil {
+		return nil, err
+	}
 	server := &Server{}
 	server.Router = router
 	server.config = config
@@ -30,3 +72,34 @@ func (server *Server) Start() {
 		panic(err)
 	}
 }
+
+type GrpcServer struct {
+	Server *grpc.Server
+	config domain.AppConfig
+}
+
+// NewGrpcServer serves the task use case over gRPC.
+func NewGrpcServer(usecases Usecases, config domain.AppConfig) (*GrpcServer, error) {
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcdelivery.UnaryActorInterceptor()),
+		grpc.StreamInterceptor(grpcdelivery.StreamActorInterceptor()),
+	)
+	grpcdelivery.NewTaskServer(s, usecases.Task, usecases.TaskEvents)
+	server := &GrpcServer{}
+	server.Server = s
+	server.config = config
+	return server, nil
+}
+
+func (server *GrpcServer) Start() {
+	if server.config.GrpcAddress == "" {
+		log.Fatal("grpc address is empty")
+	}
+	lis, err := net.Listen("tcp", server.config.GrpcAddress)
+	if err != nil {
+		panic(err)
+	}
+	if err := server.Server.Serve(lis); err != nil {
+		panic(err)
+	}
+}
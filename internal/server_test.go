@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestNewHttpServer_OpenAPIRoutes fails when a route is added to or removed from the
+// server without the OpenAPI document following.
+func TestNewHttpServer_OpenAPIRoutes(t *testing.T) {
+	server, err := NewHttpServer(Usecases{}, domain.AppConfig{})
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+	server.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var document struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+
+	var routes []string
+	for _, route := range server.Router.Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	var documented []string
+	pathParameter := regexp.MustCompile(`{([^}]+)}`)
+	for path, item := range document.Paths {
+		for method, operation := range item {
+			documented = append(documented, strings.ToUpper(method)+" "+pathParameter.ReplaceAllString(path, ":$1"))
+
+			var inPath []string
+			for _, parameter := range operation.Parameters {
+				if parameter.In == "path" {
+					inPath = append(inPath, parameter.Name)
+				}
+			}
+			var want []string
+			for _, match := range pathParameter.FindAllStringSubmatch(path, -1) {
+				want = append(want, match[1])
+			}
+			require.ElementsMatch(t, want, inPath, "%s %s", method, path)
+			require.NotEmpty(t, operation.Responses, "%s %s", method, path)
+		}
+	}
+	require.ElementsMatch(t, routes, documented)
+}
@@ -0,0 +1,135 @@
+// Package stream fans the task changes out to the live subscribers of the service.
+package stream
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SubscriberBuffer is how many events a subscriber may fall behind before it is dropped.
+const SubscriberBuffer = 64
+
+type broker struct {
+	mu          sync.Mutex
+	epoch       string
+	lastID      int64
+	buffer      []domain.TaskEvent
+	size        int
+	subscribers map[int64]chan domain.TaskEvent
+	nextSub     int64
+	now         func() time.Time
+}
+
+// NewBroker keeps the last size events for the subscribers that resume with a
+// Last-Event-ID. Publish never blocks, a subscriber that falls SubscriberBuffer events
+// behind gets its channel closed and has to resume. The IDs restart from 1 with the
+// process, so they belong to an epoch named after the boot time and a client resuming
+// from another epoch is told to reload.
+func NewBroker(size int) *broker {
+	return &broker{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:        size,
+		buffer:      make([]domain.TaskEvent, 0, size),
+		subscribers: make(map[int64]chan domain.TaskEvent),
+		now:         time.Now,
+	}
+}
+
+func (b *broker) Publish(ctx context.Context, eventType domain.TaskEventType, task domain.Task) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	event := domain.TaskEvent{
+		ID:         b.lastID,
+		Epoch:      b.epoch,
+		Type:       eventType,
+		OccurredAt: b.now(),
+		Task:       task,
+		Origin:     domain.OriginFromContext(ctx),
+	}
+	if b.size > 0 {
+		if len(b.buffer) == b.size {
+			copy(b.buffer, b.buffer[1:])
+			b.buffer = b.buffer[:len(b.buffer)-1]
+		}
+		b.buffer = append(b.buffer, event)
+	}
+	for id, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			close(ch)
+			delete(b.subscribers, id)
+		}
+	}
+}
+
+func (b *broker) Subscribe(epoch string, lastEventID *int64) (domain.TaskEventSubscription, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rtn := domain.TaskEventSubscription{Epoch: b.epoch, LastID: b.lastID}
+	if lastEventID != nil {
+		rtn.Replay, rtn.Missed = b.replay(epoch, *lastEventID)
+	}
+	ch := make(chan domain.TaskEvent, SubscriberBuffer)
+	b.nextSub++
+	id := b.nextSub
+	b.subscribers[id] = ch
+	rtn.Events = ch
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if _, ok := b.subscribers[id]; ok {
+				close(ch)
+				delete(b.subscribers, id)
+			}
+		})
+	}
+	return rtn, cancel
+}
+
+// replay returns the buffered events after lastEventID. An ID of another epoch, or ahead
+// of the last event, was handed out before a restart, so the client missed an unknown
+// number of events.
+func (b *broker) replay(epoch string, lastEventID int64) ([]domain.TaskEvent, bool) {
+	if epoch != b.epoch || lastEventID > b.lastID {
+		return nil, true
+	}
+	if lastEventID == b.lastID {
+		return nil, false
+	}
+	oldest := b.lastID + 1
+	if len(b.buffer) > 0 {
+		oldest = b.buffer[0].ID
+	}
+	if lastEventID < oldest-1 {
+		return nil, true
+	}
+	rtn := make([]domain.TaskEvent, 0)
+	for _, event := range b.buffer {
+		if event.ID > lastEventID {
+			rtn = append(rtn, event)
+		}
+	}
+	return rtn, false
+}
+
+// HandleTaskEvent is the domain.EventHandler feeding the broker, a task restored from the
+// trash is seen as created.
+func (b *broker) HandleTaskEvent(ctx context.Context, event domain.DomainEvent) error {
+	switch e := event.(type) {
+	case domain.TaskCreated:
+		b.Publish(ctx, domain.TaskEventCreated, e.Task)
+	case domain.TaskUpdated:
+		b.Publish(ctx, domain.TaskEventUpdated, e.Task)
+	case domain.TaskDeleted:
+		b.Publish(ctx, domain.TaskEventDeleted, e.Task)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func eventIDs(events []domain.TaskEvent) []int64 {
+	rtn := make([]int64, 0)
+	for _, event := range events {
+		rtn = append(rtn, event.ID)
+	}
+	return rtn
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func Test_broker_Subscribe(t *testing.T) {
+	tests := []struct {
+		name        string
+		otherEpoch  bool
+		lastEventID *int64
+		wantReplay  []int64
+		wantMissed  bool
+	}{
+		{
+			name:        "New",
+			lastEventID: nil,
+			wantReplay:  []int64{},
+			wantMissed:  false,
+		},
+		{
+			name:        "UpToDate",
+			lastEventID: int64Ptr(5),
+			wantReplay:  []int64{},
+			wantMissed:  false,
+		},
+		{
+			name:        "Resume",
+			lastEventID: int64Ptr(3),
+			wantReplay:  []int64{4, 5},
+			wantMissed:  false,
+		},
+		{
+			name:        "ResumeFromOldest",
+			lastEventID: int64Ptr(2),
+			wantReplay:  []int64{3, 4, 5},
+			wantMissed:  false,
+		},
+		{
+			name:        "Evicted",
+			lastEventID: int64Ptr(1),
+			wantReplay:  []int64{},
+			wantMissed:  true,
+		},
+		{
+			name:        "BeforeRestart",
+			lastEventID: int64Ptr(9),
+			wantReplay:  []int64{},
+			wantMissed:  true,
+		},
+		{
+			name:        "OtherEpoch",
+			otherEpoch:  true,
+			lastEventID: int64Ptr(3),
+			wantReplay:  []int64{},
+			wantMissed:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBroker(3)
+			for i := 0; i < 5; i++ {
+				b.Publish(context.Background(), domain.TaskEventCreated, domain.Task{ID: int64(i + 1)})
+			}
+			epoch := b.epoch
+			if tt.otherEpoch {
+				epoch = NewBroker(3).epoch + "0"
+			}
+			got, cancel := b.Subscribe(epoch, tt.lastEventID)
+			defer cancel()
+			if ids := eventIDs(got.Replay); !reflect.DeepEqual(ids, tt.wantReplay) {
+				t.Errorf("Subscribe() Replay = %v, want %v", ids, tt.wantReplay)
+			}
+			if got.Missed != tt.wantMissed || got.Epoch != b.epoch || got.LastID != 5 {
+				t.Errorf("Subscribe() Missed, Epoch, LastID = %v, %v, %v, want %v, %v, 5", got.Missed, got.Epoch, got.LastID, tt.wantMissed, b.epoch)
+			}
+
+			b.Publish(context.Background(), domain.TaskEventDeleted, domain.Task{ID: 1})
+			if event := <-got.Events; event.ID != 6 || event.Epoch != b.epoch || event.Type != domain.TaskEventDeleted {
+				t.Errorf("Events got %+v, want the deleted event 6", event)
+			}
+		})
+	}
+}
+
+func Test_broker_SlowSubscriber(t *testing.T) {
+	b := NewBroker(0)
+	slow, cancelSlow := b.Subscribe("", nil)
+	fast, cancelFast := b.Subscribe("", nil)
+	defer cancelFast()
+
+	for i := 0; i < SubscriberBuffer+1; i++ {
+		b.Publish(context.Background(), domain.TaskEventUpdated, domain.Task{ID: 1})
+		<-fast.Events
+	}
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != SubscriberBuffer {
+		t.Errorf("slow subscriber got %d events before it was dropped, want %d", received, SubscriberBuffer)
+	}
+	cancelSlow()
+
+	b.Publish(context.Background(), domain.TaskEventUpdated, domain.Task{ID: 1})
+	if event, ok := <-fast.Events; !ok || event.ID != SubscriberBuffer+2 {
+		t.Errorf("fast subscriber got %+v, %v, want event %d", event, ok, SubscriberBuffer+2)
+	}
+}
+
+func Test_broker_HandleTaskEvent(t *testing.T) {
+	b := NewBroker(10)
+	task := domain.Task{ID: 1, Name: "taskName1"}
+	for _, event := range []domain.DomainEvent{
+		domain.TaskCreated{Task: task},
+		domain.TaskUpdated{Before: task, Task: task},
+		domain.TaskDeleted{Task: task},
+		domain.TaskCreated{Task: task, Restored: true},
+	} {
+		if err := b.HandleTaskEvent(context.Background(), event); err != nil {
+			t.Fatalf("HandleTaskEvent() error = %v", err)
+		}
+	}
+
+	sub, cancel := b.Subscribe(b.epoch, int64Ptr(0))
+	defer cancel()
+	got := make([]domain.TaskEventType, 0, len(sub.Replay))
+	for _, e := range sub.Replay {
+		got = append(got, e.Type)
+	}
+	want := []domain.TaskEventType{domain.TaskEventCreated, domain.TaskEventUpdated, domain.TaskEventDeleted, domain.TaskEventCreated}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HandleTaskEvent() published %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"oa-gogolook/internal/domain"
+)
+
+const defaultContentType = "application/octet-stream"
+
+type attachmentUsecase struct {
+	attachmentRepository domain.AttachmentRepository
+	taskRepository       domain.TaskRepository
+	blobStore            domain.BlobStore
+	maxSize              int64
+}
+
+func NewAttachmentUsecase(attachmentRepository domain.AttachmentRepository, taskRepository domain.TaskRepository, blobStore domain.BlobStore, maxSize int64) *attachmentUsecase {
+	return &attachmentUsecase{
+		attachmentRepository: attachmentRepository,
+		taskRepository:       taskRepository,
+		blobStore:            blobStore,
+		maxSize:              maxSize,
+	}
+}
+
+func (u *attachmentUsecase) List(ctx context.Context, taskID int64) (domain.ListAttachmentResponse, error) {
+	var rtn domain.ListAttachmentResponse
+	if _, err := u.taskRepository.Get(ctx, taskID); err != nil {
+		return rtn, err
+	}
+	got, err := u.attachmentRepository.List(ctx, taskID)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+// Upload streams the content into the blob store and only records the attachment
+// once the whole content is stored within the size limit.
+func (u *attachmentUsecase) Upload(ctx context.Context, taskID int64, req domain.UploadAttachmentRequest) (domain.UploadAttachmentResponse, error) {
+	var rtn domain.UploadAttachmentResponse
+	if _, err := u.taskRepository.Get(ctx, taskID); err != nil {
+		return rtn, err
+	}
+	key, err := newBlobKey(taskID)
+	if err != nil {
+		return rtn, err
+	}
+	size, err := u.blobStore.Put(ctx, key, io.LimitReader(req.Content, u.maxSize+1))
+	if err != nil {
+		return rtn, err
+	}
+	if size > u.maxSize {
+		_ = u.blobStore.Delete(ctx, key)
+		return rtn, domain.ErrAttachmentTooBig
+	}
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	got, err := u.attachmentRepository.Create(ctx, domain.Attachment{
+		TaskID:      taskID,
+		FileName:    req.FileName,
+		ContentType: contentType,
+		Size:        size,
+		BlobKey:     key,
+	})
+	if err != nil {
+		_ = u.blobStore.Delete(ctx, key)
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *attachmentUsecase) Open(ctx context.Context, taskID int64, id int64) (domain.Attachment, io.ReadSeekCloser, error) {
+	got, err := u.get(ctx, taskID, id)
+	if err != nil {
+		return got, nil, err
+	}
+	content, err := u.blobStore.Open(ctx, got.BlobKey)
+	if err != nil {
+		return got, nil, err
+	}
+	return got, content, nil
+}
+
+func (u *attachmentUsecase) Delete(ctx context.Context, taskID int64, id int64) error {
+	got, err := u.get(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	err = u.attachmentRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	return u.blobStore.Delete(ctx, got.BlobKey)
+}
+
+func (u *attachmentUsecase) get(ctx context.Context, taskID int64, id int64) (domain.Attachment, error) {
+	got, err := u.attachmentRepository.Get(ctx, id)
+	if err != nil {
+		return got, err
+	}
+	if got.TaskID != taskID {
+		return domain.Attachment{}, domain.ErrDataNotFound
+	}
+	return got, nil
+}
+
+// HandleTaskEvent removes the attachments of a purged task along with their content.
+func (u *attachmentUsecase) HandleTaskEvent(ctx context.Context, event domain.DomainEvent) error {
+	e, ok := event.(domain.TaskPurged)
+	if !ok {
+		return nil
+	}
+	if err := u.attachmentRepository.DeleteByTask(ctx, e.ID); err != nil {
+		return err
+	}
+	return u.blobStore.DeletePrefix(ctx, blobPrefix(e.ID))
+}
+
+func newBlobKey(taskID int64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return blobPrefix(taskID) + hex.EncodeToString(b), nil
+}
+
+// blobPrefix starts the blob key of every attachment of a task.
+func blobPrefix(taskID int64) string {
+	return fmt.Sprintf("task-%d/", taskID)
+}
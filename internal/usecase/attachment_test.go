@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"io/ioutil"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"oa-gogolook/internal/repository/localfs"
+	"strings"
+	"testing"
+)
+
+func newTestAttachmentUsecase(t *testing.T) *attachmentUsecase {
+	blobStore, err := localfs.NewBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewBlobStore() error = %v", err)
+	}
+	u := &attachmentUsecase{
+		attachmentRepository: inmemory.NewAttachmentRepository(),
+		taskRepository:       inmemory.NewTaskRepository(),
+		blobStore:            blobStore,
+		maxSize:              8,
+	}
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+	return u
+}
+
+func Test_attachmentUsecase_Upload(t *testing.T) {
+	tests := []struct {
+		name            string
+		taskID          int64
+		content         string
+		contentType     string
+		wantErr         error
+		wantContentType string
+	}{
+		{
+			name:            "OK",
+			taskID:          1,
+			content:         "12345678",
+			contentType:     "text/plain",
+			wantErr:         nil,
+			wantContentType: "text/plain",
+		},
+		{
+			name:            "DefaultContentType",
+			taskID:          1,
+			content:         "1234",
+			contentType:     "",
+			wantErr:         nil,
+			wantContentType: defaultContentType,
+		},
+		{
+			name:        "TooLarge",
+			taskID:      1,
+			content:     "123456789",
+			contentType: "text/plain",
+			wantErr:     domain.ErrAttachmentTooBig,
+		},
+		{
+			name:        "TaskNotFound",
+			taskID:      5,
+			content:     "1234",
+			contentType: "text/plain",
+			wantErr:     domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestAttachmentUsecase(t)
+			got, err := u.Upload(context.Background(), tt.taskID, domain.UploadAttachmentRequest{
+				FileName:    "a.txt",
+				ContentType: tt.contentType,
+				Content:     strings.NewReader(tt.content),
+			})
+			if err != tt.wantErr {
+				t.Fatalf("Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				list, _ := u.attachmentRepository.List(context.Background(), tt.taskID)
+				if len(list) != 0 {
+					t.Errorf("Upload() recorded an attachment on failure")
+				}
+				return
+			}
+			if got.Result.Size != int64(len(tt.content)) || got.Result.ContentType != tt.wantContentType {
+				t.Errorf("Upload() got = %v", got)
+			}
+			_, content, err := u.Open(context.Background(), tt.taskID, got.Result.ID)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer content.Close()
+			data, _ := ioutil.ReadAll(content)
+			if string(data) != tt.content {
+				t.Errorf("Open() got = %q, want %q", data, tt.content)
+			}
+		})
+	}
+}
+
+func Test_attachmentUsecase_Delete(t *testing.T) {
+	u := newTestAttachmentUsecase(t)
+	got, err := u.Upload(context.Background(), 1, domain.UploadAttachmentRequest{
+		FileName: "a.txt",
+		Content:  strings.NewReader("1234"),
+	})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if err := u.Delete(context.Background(), 2, got.Result.ID); err != domain.ErrDataNotFound {
+		t.Errorf("Delete() on other task error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if err := u.Delete(context.Background(), 1, got.Result.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := u.blobStore.Open(context.Background(), got.Result.BlobKey); err != domain.ErrDataNotFound {
+		t.Errorf("Delete() left the blob behind, error = %v", err)
+	}
+}
+
+func Test_attachmentUsecase_HandleTaskEvent(t *testing.T) {
+	u := newTestAttachmentUsecase(t)
+	got, err := u.Upload(context.Background(), 1, domain.UploadAttachmentRequest{
+		FileName: "a.txt",
+		Content:  strings.NewReader("1234"),
+	})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if err := u.HandleTaskEvent(context.Background(), domain.TaskDeleted{Task: domain.Task{ID: 1}}); err != nil {
+		t.Fatalf("HandleTaskEvent() error = %v", err)
+	}
+	if _, err := u.attachmentRepository.Get(context.Background(), got.Result.ID); err != nil {
+		t.Fatalf("HandleTaskEvent() removed the attachment of a trashed task, error = %v", err)
+	}
+	if err := u.HandleTaskEvent(context.Background(), domain.TaskPurged{ID: 1}); err != nil {
+		t.Fatalf("HandleTaskEvent() error = %v", err)
+	}
+	if _, err := u.attachmentRepository.Get(context.Background(), got.Result.ID); err != domain.ErrDataNotFound {
+		t.Errorf("HandleTaskEvent() left the attachment behind, error = %v", err)
+	}
+	if _, err := u.blobStore.Open(context.Background(), got.Result.BlobKey); err != domain.ErrDataNotFound {
+		t.Errorf("HandleTaskEvent() left the blob behind, error = %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+)
+
+// checklistUsecase changes a checklist through the task use case, so every change is
+// published and recorded like any other change of the task.
+type checklistUsecase struct {
+	taskUsecase domain.TaskUseCase
+}
+
+func NewChecklistUsecase(taskUsecase domain.TaskUseCase) *checklistUsecase {
+	return &checklistUsecase{
+		taskUsecase: taskUsecase,
+	}
+}
+
+func (u *checklistUsecase) AddItem(ctx context.Context, taskID int64, req domain.AddChecklistItemRequest) (domain.ChecklistResponse, error) {
+	return u.taskUsecase.AddChecklistItem(ctx, taskID, req.Text)
+}
+
+func (u *checklistUsecase) Reorder(ctx context.Context, taskID int64, req domain.ReorderChecklistRequest) (domain.ChecklistResponse, error) {
+	return u.taskUsecase.ReorderChecklist(ctx, taskID, req.ItemIDs)
+}
+
+func (u *checklistUsecase) ToggleItem(ctx context.Context, taskID int64, id int64) (domain.ChecklistResponse, error) {
+	return u.taskUsecase.ToggleChecklistItem(ctx, taskID, id)
+}
+
+func (u *checklistUsecase) RemoveItem(ctx context.Context, taskID int64, id int64) (domain.ChecklistResponse, error) {
+	return u.taskUsecase.RemoveChecklistItem(ctx, taskID, id)
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/eventbus"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"testing"
+)
+
+func Test_checklistUsecase(t *testing.T) {
+	r := inmemory.NewTaskRepository()
+	u := NewChecklistUsecase(&taskUsecase{taskRepository: r})
+	_, _ = r.Create(context.Background(), domain.Task{Name: "taskName1"})
+
+	for _, text := range []string{"item1", "item2", "item3", "item4"} {
+		if _, err := u.AddItem(context.Background(), 1, domain.AddChecklistItemRequest{Text: text}); err != nil {
+			t.Fatalf("AddItem() error = %v", err)
+		}
+	}
+	got, err := u.ToggleItem(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("ToggleItem() error = %v", err)
+	}
+	if p := got.Result.Progress(); p == nil || *p != 25 {
+		t.Errorf("Progress() = %v, want 25", p)
+	}
+	got, err = u.Reorder(context.Background(), 1, domain.ReorderChecklistRequest{ItemIDs: []int64{4, 3, 2, 1}})
+	if err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	if got.Result.Checklist[0].Text != "item4" {
+		t.Errorf("Reorder() got = %v", got.Result.Checklist)
+	}
+	got, err = u.RemoveItem(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("RemoveItem() error = %v", err)
+	}
+	if p := got.Result.Progress(); p == nil || *p != 33 {
+		t.Errorf("Progress() = %v, want 33", p)
+	}
+	if _, err := u.ToggleItem(context.Background(), 5, 1); err != domain.ErrDataNotFound {
+		t.Errorf("ToggleItem() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+// Test_checklistUsecase_Events checks that checklist changes and the tasks of a template
+// reach the subscribers of the bus like the changes made through the task use case.
+func Test_checklistUsecase_Events(t *testing.T) {
+	events := eventbus.NewBus()
+	got := make([]domain.DomainEvent, 0)
+	events.Subscribe("record", func(ctx context.Context, event domain.DomainEvent) error {
+		got = append(got, event)
+		return nil
+	})
+	r := inmemory.NewTaskRepository()
+	tasks := &taskUsecase{taskRepository: r, events: events}
+	checklist := NewChecklistUsecase(tasks)
+	templates := NewTemplateUsecase(inmemory.NewTemplateRepository(), tasks)
+	_, _ = templates.templateRepository.Create(context.Background(), domain.Template{
+		Name:  "release",
+		Tasks: []domain.TemplateTask{{Name: "freeze"}, {Name: "ship"}},
+	})
+
+	_, _ = r.Create(context.Background(), domain.Task{Name: "taskName1"})
+	_, _ = r.AddChecklistItem(context.Background(), 1, "item1")
+	if _, err := checklist.ToggleItem(context.Background(), 1, 1); err != nil {
+		t.Fatalf("ToggleItem() error = %v", err)
+	}
+	if _, err := templates.Instantiate(context.Background(), 1, domain.InstantiateTemplateRequest{}); err != nil {
+		t.Fatalf("Instantiate() error = %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("published %v, want 3 events", got)
+	}
+	toggled, ok := got[0].(domain.TaskUpdated)
+	if !ok || toggled.Before.Checklist[0].Checked || !toggled.Task.Checklist[0].Checked {
+		t.Errorf("ToggleItem() published %v, want the toggle in Before and Task", got[0])
+	}
+	var created []string
+	for _, event := range got[1:] {
+		if e, ok := event.(domain.TaskCreated); ok {
+			created = append(created, e.Task.Name)
+		}
+	}
+	if want := []string{"freeze", "ship"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("Instantiate() published TaskCreated for %v, want %v", created, want)
+	}
+}
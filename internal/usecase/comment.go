@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+)
+
+type commentUsecase struct {
+	commentRepository domain.CommentRepository
+	taskRepository    domain.TaskRepository
+	userRepository    domain.UserRepository
+}
+
+func NewCommentUsecase(commentRepository domain.CommentRepository, taskRepository domain.TaskRepository, userRepository domain.UserRepository) *commentUsecase {
+	return &commentUsecase{
+		commentRepository: commentRepository,
+		taskRepository:    taskRepository,
+		userRepository:    userRepository,
+	}
+}
+
+func (u *commentUsecase) List(ctx context.Context, taskID int64, req domain.ListCommentRequest) (domain.ListCommentResponse, error) {
+	var rtn domain.ListCommentResponse
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = domain.DefaultCommentPageSize
+	}
+	got, total, err := u.commentRepository.List(ctx, taskID, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	rtn.Page = req.Page
+	rtn.PageSize = req.PageSize
+	rtn.Total = total
+	return rtn, nil
+}
+
+func (u *commentUsecase) Create(ctx context.Context, taskID int64, authorID int64, req domain.CreateCommentRequest) (domain.CreateCommentResponse, error) {
+	var rtn domain.CreateCommentResponse
+	if _, err := u.taskRepository.Get(ctx, taskID); err != nil {
+		return rtn, err
+	}
+	if _, err := u.userRepository.Get(ctx, authorID); err != nil {
+		return rtn, err
+	}
+	got, err := u.commentRepository.Create(ctx, taskID, authorID, req.Body)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *commentUsecase) Update(ctx context.Context, taskID int64, id int64, authorID int64, req domain.UpdateCommentRequest) (domain.UpdateCommentResponse, error) {
+	var rtn domain.UpdateCommentResponse
+	if err := u.checkAuthor(ctx, taskID, id, authorID); err != nil {
+		return rtn, err
+	}
+	got, err := u.commentRepository.Update(ctx, id, req.Body)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *commentUsecase) Delete(ctx context.Context, taskID int64, id int64, authorID int64) error {
+	if err := u.checkAuthor(ctx, taskID, id, authorID); err != nil {
+		return err
+	}
+	err := u.commentRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkAuthor makes sure the comment belongs to the task and was written by authorID.
+func (u *commentUsecase) checkAuthor(ctx context.Context, taskID int64, id int64, authorID int64) error {
+	got, err := u.commentRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if got.TaskID != taskID {
+		return domain.ErrDataNotFound
+	}
+	if got.AuthorID != authorID {
+		return domain.ErrNotCommentAuthor
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"testing"
+)
+
+func newTestCommentUsecase() *commentUsecase {
+	u := &commentUsecase{
+		commentRepository: inmemory.NewCommentRepository(),
+		taskRepository:    inmemory.NewTaskRepository(),
+		userRepository:    inmemory.NewUserRepository(),
+	}
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+	_, _ = u.userRepository.Create(context.Background(), "userName1")
+	_, _ = u.userRepository.Create(context.Background(), "userName2")
+	return u
+}
+
+func Test_commentUsecase_Create(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		taskID   int64
+		authorID int64
+		req      domain.CreateCommentRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "OK",
+			args: args{
+				ctx:      context.Background(),
+				taskID:   1,
+				authorID: 1,
+				req:      domain.CreateCommentRequest{Body: "body"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "TaskNotFound",
+			args: args{
+				ctx:      context.Background(),
+				taskID:   5,
+				authorID: 1,
+				req:      domain.CreateCommentRequest{Body: "body"},
+			},
+			wantErr: domain.ErrDataNotFound,
+		},
+		{
+			name: "AuthorNotFound",
+			args: args{
+				ctx:      context.Background(),
+				taskID:   1,
+				authorID: 5,
+				req:      domain.CreateCommentRequest{Body: "body"},
+			},
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestCommentUsecase()
+			got, err := u.Create(tt.args.ctx, tt.args.taskID, tt.args.authorID, tt.args.req)
+			if err != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.Result.Body != tt.args.req.Body || got.Result.AuthorID != tt.args.authorID) {
+				t.Errorf("Create() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_commentUsecase_List(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          domain.ListCommentRequest
+		wantLen      int
+		wantPage     int
+		wantPageSize int
+	}{
+		{
+			name:         "Defaults",
+			req:          domain.ListCommentRequest{},
+			wantLen:      3,
+			wantPage:     1,
+			wantPageSize: domain.DefaultCommentPageSize,
+		},
+		{
+			name:         "SecondPage",
+			req:          domain.ListCommentRequest{Page: 2, PageSize: 2},
+			wantLen:      1,
+			wantPage:     2,
+			wantPageSize: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestCommentUsecase()
+			for i := 0; i < 3; i++ {
+				_, _ = u.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Body: "body"})
+			}
+			got, err := u.List(context.Background(), 1, tt.req)
+			if err != nil {
+				t.Errorf("List() error = %v", err)
+				return
+			}
+			if len(got.Result) != tt.wantLen || got.Page != tt.wantPage || got.PageSize != tt.wantPageSize || got.Total != 3 {
+				t.Errorf("List() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_commentUsecase_Update(t *testing.T) {
+	type args struct {
+		taskID   int64
+		id       int64
+		authorID int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			args:    args{taskID: 1, id: 1, authorID: 1},
+			wantErr: nil,
+		},
+		{
+			name:    "NotAuthor",
+			args:    args{taskID: 1, id: 1, authorID: 2},
+			wantErr: domain.ErrNotCommentAuthor,
+		},
+		{
+			name:    "WrongTask",
+			args:    args{taskID: 2, id: 1, authorID: 1},
+			wantErr: domain.ErrDataNotFound,
+		},
+		{
+			name:    "NotFound",
+			args:    args{taskID: 1, id: 5, authorID: 1},
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestCommentUsecase()
+			_, _ = u.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Body: "body"})
+			got, err := u.Update(context.Background(), tt.args.taskID, tt.args.id, tt.args.authorID, domain.UpdateCommentRequest{Body: "edited"})
+			if err != tt.wantErr {
+				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Result.Body != "edited" {
+				t.Errorf("Update() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_commentUsecase_Delete(t *testing.T) {
+	type args struct {
+		taskID   int64
+		id       int64
+		authorID int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			args:    args{taskID: 1, id: 1, authorID: 1},
+			wantErr: nil,
+		},
+		{
+			name:    "NotAuthor",
+			args:    args{taskID: 1, id: 1, authorID: 2},
+			wantErr: domain.ErrNotCommentAuthor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestCommentUsecase()
+			_, _ = u.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Body: "body"})
+			if err := u.Delete(context.Background(), tt.args.taskID, tt.args.id, tt.args.authorID); err != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
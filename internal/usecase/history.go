@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"oa-gogolook/internal/domain"
+	"reflect"
+)
+
+// historyTaskUsecase decorates a domain.TaskUseCase and records every create, update,
+// checklist change, delete and restore in the history repository, the other methods are
+// passed through unchanged. The change is already committed when it is recorded, so a
+// failure to record is logged rather than returned.
+type historyTaskUsecase struct {
+	domain.TaskUseCase
+	historyRepository domain.HistoryRepository
+}
+
+func NewHistoryTaskUsecase(taskUsecase domain.TaskUseCase, historyRepository domain.HistoryRepository) *historyTaskUsecase {
+	return &historyTaskUsecase{
+		TaskUseCase:       taskUsecase,
+		historyRepository: historyRepository,
+	}
+}
+
+func (u *historyTaskUsecase) Create(ctx context.Context, req domain.CreateTaskRequest) (domain.CreateTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Create(ctx, req)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, rtn.Result.ID, domain.HistoryActionCreate, diffTasks(domain.Task{}, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) CreateMany(ctx context.Context, tasks []domain.Task) ([]domain.Task, error) {
+	rtn, err := u.TaskUseCase.CreateMany(ctx, tasks)
+	if err != nil {
+		return rtn, err
+	}
+	for _, task := range rtn {
+		u.record(ctx, task.ID, domain.HistoryActionCreate, diffTasks(domain.Task{}, task))
+	}
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) Update(ctx context.Context, req domain.UpdateTaskRequest) (domain.UpdateTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Update(ctx, req)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, req.ID, domain.HistoryActionUpdate, diffTasks(rtn.Before, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) Revert(ctx context.Context, expected domain.Task, to domain.Task) (domain.UpdateTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Revert(ctx, expected, to)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, expected.ID, domain.HistoryActionUpdate, diffTasks(rtn.Before, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) Delete(ctx context.Context, id int64) (domain.DeleteTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Delete(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, id, domain.HistoryActionDelete, nil)
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) Restore(ctx context.Context, id int64) (domain.RestoreTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Restore(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, id, domain.HistoryActionRestore, nil)
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) AddChecklistItem(ctx context.Context, id int64, text string) (domain.ChecklistResponse, error) {
+	rtn, err := u.TaskUseCase.AddChecklistItem(ctx, id, text)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, id, domain.HistoryActionUpdate, diffTasks(rtn.Before, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (domain.ChecklistResponse, error) {
+	rtn, err := u.TaskUseCase.ReorderChecklist(ctx, id, itemIDs)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, id, domain.HistoryActionUpdate, diffTasks(rtn.Before, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) ToggleChecklistItem(ctx context.Context, id int64, itemID int64) (domain.ChecklistResponse, error) {
+	rtn, err := u.TaskUseCase.ToggleChecklistItem(ctx, id, itemID)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, id, domain.HistoryActionUpdate, diffTasks(rtn.Before, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) RemoveChecklistItem(ctx context.Context, id int64, itemID int64) (domain.ChecklistResponse, error) {
+	rtn, err := u.TaskUseCase.RemoveChecklistItem(ctx, id, itemID)
+	if err != nil {
+		return rtn, err
+	}
+	u.record(ctx, id, domain.HistoryActionUpdate, diffTasks(rtn.Before, rtn.Result))
+	return rtn, nil
+}
+
+func (u *historyTaskUsecase) record(ctx context.Context, taskID int64, action domain.HistoryAction, changes []domain.FieldChange) {
+	entry := domain.HistoryEntry{
+		TaskID:  taskID,
+		Action:  action,
+		Changes: changes,
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		entry.ActorID = &actorID
+	}
+	if _, err := u.historyRepository.Append(ctx, entry); err != nil {
+		log.Printf("history: can not record %s of task %d: %v", action, taskID, err)
+	}
+}
+
+// diffTasks lists the user visible fields that differ between before and after, using the
+// JSON field names.
+func diffTasks(before domain.Task, after domain.Task) []domain.FieldChange {
+	changes := make([]domain.FieldChange, 0)
+	add := func(field string, from interface{}, to interface{}) {
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, domain.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	add("name", before.Name, after.Name)
+	add("status", before.Status, after.Status)
+	add("description", before.Description, after.Description)
+	add("assigneeId", before.AssigneeID, after.AssigneeID)
+	add("estimate", before.Estimate, after.Estimate)
+	add("dueAt", before.DueAt, after.DueAt)
+	add("tags", before.Tags, after.Tags)
+	add("checklist", before.Checklist, after.Checklist)
+	return changes
+}
+
+type historyUsecase struct {
+	historyRepository domain.HistoryRepository
+}
+
+func NewHistoryUsecase(historyRepository domain.HistoryRepository) *historyUsecase {
+	return &historyUsecase{
+		historyRepository: historyRepository,
+	}
+}
+
+// List returns the history of a task, oldest entry first. Tasks without history are
+// reported as not found, the history of a deleted task stays readable.
+func (u *historyUsecase) List(ctx context.Context, taskID int64) (domain.ListHistoryResponse, error) {
+	var rtn domain.ListHistoryResponse
+	got, err := u.historyRepository.List(ctx, taskID)
+	if err != nil {
+		return rtn, err
+	}
+	if len(got) == 0 {
+		return rtn, domain.ErrDataNotFound
+	}
+	rtn.Result = got
+	return rtn, nil
+}
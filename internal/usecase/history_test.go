@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"testing"
+)
+
+func newTestHistoryTaskUsecase() (*historyTaskUsecase, *historyUsecase) {
+	historyRepository := inmemory.NewHistoryRepository()
+	taskUsecase := &taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+	}
+	return NewHistoryTaskUsecase(taskUsecase, historyRepository), NewHistoryUsecase(historyRepository)
+}
+
+func Test_historyTaskUsecase_Record(t *testing.T) {
+	u, history := newTestHistoryTaskUsecase()
+	ctx := domain.ContextWithActor(context.Background(), 7)
+	status := domain.StatusComplete
+	description := "done"
+
+	_, _ = u.Create(context.Background(), domain.CreateTaskRequest{Name: "taskName1"})
+	_, _ = u.Update(ctx, domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "taskName1", Description: &description})
+	_, _ = u.Delete(ctx, 1)
+
+	got, err := history.List(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got.Result) != 3 {
+		t.Fatalf("List() returned %d entries, want 3", len(got.Result))
+	}
+	create, update, del := got.Result[0], got.Result[1], got.Result[2]
+	if create.Action != domain.HistoryActionCreate || create.ActorID != nil {
+		t.Errorf("create entry = %v, want anonymous create", create)
+	}
+	if !reflect.DeepEqual(create.Changes, []domain.FieldChange{{Field: "name", From: "", To: "taskName1"}}) {
+		t.Errorf("create changes = %v", create.Changes)
+	}
+	if update.Action != domain.HistoryActionUpdate || update.ActorID == nil || *update.ActorID != 7 {
+		t.Errorf("update entry = %v, want update by 7", update)
+	}
+	wantChanges := []domain.FieldChange{
+		{Field: "status", From: domain.StatusIncomplete, To: domain.StatusComplete},
+		{Field: "description", From: "", To: "done"},
+	}
+	if !reflect.DeepEqual(update.Changes, wantChanges) {
+		t.Errorf("update changes = %v, want %v", update.Changes, wantChanges)
+	}
+	if del.Action != domain.HistoryActionDelete || del.Timestamp.IsZero() {
+		t.Errorf("delete entry = %v, want timestamped delete", del)
+	}
+}
+
+func Test_historyTaskUsecase_CreateMany(t *testing.T) {
+	u, history := newTestHistoryTaskUsecase()
+	ctx := domain.ContextWithActor(context.Background(), 7)
+	got, err := u.CreateMany(ctx, []domain.Task{{Name: "taskName1"}, {Name: "taskName2"}})
+	if err != nil {
+		t.Fatalf("CreateMany() error = %v", err)
+	}
+	for _, task := range got {
+		entries, err := history.List(context.Background(), task.ID)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(entries.Result) != 1 || entries.Result[0].Action != domain.HistoryActionCreate || entries.Result[0].ActorID == nil || *entries.Result[0].ActorID != 7 {
+			t.Fatalf("history of task %d = %v, want one create by 7", task.ID, entries.Result)
+		}
+		want := []domain.FieldChange{{Field: "name", From: "", To: task.Name}}
+		if !reflect.DeepEqual(entries.Result[0].Changes, want) {
+			t.Errorf("create changes = %v, want %v", entries.Result[0].Changes, want)
+		}
+	}
+}
+
+func Test_historyTaskUsecase_Checklist(t *testing.T) {
+	u, history := newTestHistoryTaskUsecase()
+	ctx := context.Background()
+	created, _ := u.Create(ctx, domain.CreateTaskRequest{Name: "taskName1"})
+	if _, err := u.AddChecklistItem(ctx, created.Result.ID, "item1"); err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	if _, err := u.ToggleChecklistItem(ctx, created.Result.ID, 1); err != nil {
+		t.Fatalf("ToggleChecklistItem() error = %v", err)
+	}
+	entries, err := history.List(ctx, created.Result.ID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries.Result) != 3 {
+		t.Fatalf("history = %v, want a create and two updates", entries.Result)
+	}
+	want := []domain.FieldChange{{
+		Field: "checklist",
+		From:  []domain.ChecklistItem{{ID: 1, Text: "item1"}},
+		To:    []domain.ChecklistItem{{ID: 1, Text: "item1", Checked: true}},
+	}}
+	if entries.Result[2].Action != domain.HistoryActionUpdate || !reflect.DeepEqual(entries.Result[2].Changes, want) {
+		t.Errorf("toggle entry = %v, want an update with %v", entries.Result[2], want)
+	}
+}
+
+func Test_historyTaskUsecase_Restore(t *testing.T) {
+	u, history := newTestHistoryTaskUsecase()
+	ctx := context.Background()
+	created, _ := u.Create(ctx, domain.CreateTaskRequest{Name: "taskName1"})
+	_, _ = u.Delete(ctx, created.Result.ID)
+	if _, err := u.Restore(ctx, created.Result.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	entries, _ := history.List(ctx, created.Result.ID)
+	var actions []domain.HistoryAction
+	for _, entry := range entries.Result {
+		actions = append(actions, entry.Action)
+	}
+	want := []domain.HistoryAction{domain.HistoryActionCreate, domain.HistoryActionDelete, domain.HistoryActionRestore}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("history = %v, want %v", actions, want)
+	}
+}
+
+type failingHistoryRepository struct {
+	domain.HistoryRepository
+}
+
+func (r failingHistoryRepository) Append(ctx context.Context, entry domain.HistoryEntry) (domain.HistoryEntry, error) {
+	return domain.HistoryEntry{}, errors.New("disk full")
+}
+
+// Test_historyTaskUsecase_RecordFails checks that a change is reported as done when only
+// its history entry could not be written.
+func Test_historyTaskUsecase_RecordFails(t *testing.T) {
+	u := NewHistoryTaskUsecase(&taskUsecase{taskRepository: inmemory.NewTaskRepository()}, failingHistoryRepository{})
+	created, err := u.Create(context.Background(), domain.CreateTaskRequest{Name: "taskName1"})
+	if err != nil || created.Result.ID != 1 {
+		t.Fatalf("Create() = %v, %v, want the task and no error", created, err)
+	}
+	status := domain.StatusComplete
+	if _, err := u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "taskName1"}); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+	if _, err := u.Delete(context.Background(), 1); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
+
+func Test_historyTaskUsecase_Failed(t *testing.T) {
+	u, history := newTestHistoryTaskUsecase()
+	status := domain.StatusComplete
+	if _, err := u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "taskName1"}); err != domain.ErrDataNotFound {
+		t.Errorf("Update() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if _, err := u.Delete(context.Background(), 1); err != domain.ErrDataNotFound {
+		t.Errorf("Delete() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if _, err := history.List(context.Background(), 1); err != domain.ErrDataNotFound {
+		t.Errorf("List() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
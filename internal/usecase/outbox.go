@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"oa-gogolook/internal/domain"
+)
+
+type outboxUsecase struct {
+	outboxRepository domain.OutboxRepository
+	publisher        domain.Publisher
+	batchSize        int
+}
+
+// NewOutboxUsecase relays the outbox to publisher, batchSize messages at a time.
+func NewOutboxUsecase(outboxRepository domain.OutboxRepository, publisher domain.Publisher, batchSize int) *outboxUsecase {
+	return &outboxUsecase{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+		batchSize:        batchSize,
+	}
+}
+
+// Relay publishes the pending messages in order until none is left or one fails, the failed
+// message and everything after it wait for the next run. A message is acknowledged only
+// after it is published, so it may be published again if the process stops in between;
+// consumers recognise the repeat by its ID.
+func (u *outboxUsecase) Relay(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		pending, err := u.outboxRepository.PendingOutbox(ctx, u.batchSize)
+		if err != nil {
+			return published, err
+		}
+		if len(pending) == 0 {
+			return published, nil
+		}
+		// done is the Seq up to which every message is published.
+		var done int64
+		for i, message := range pending {
+			if err := u.publisher.Publish(ctx, message); err != nil {
+				if done > 0 {
+					if err := u.outboxRepository.AckOutbox(ctx, done); err != nil {
+						return published, err
+					}
+				}
+				return published, fmt.Errorf("outbox message %s not published: %w", message.ID, err)
+			}
+			published++
+			if i == len(pending)-1 || pending[i+1].Seq != message.Seq {
+				done = message.Seq
+			}
+		}
+		if err := u.outboxRepository.AckOutbox(ctx, done); err != nil {
+			return published, err
+		}
+	}
+}
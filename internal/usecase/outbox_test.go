@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"testing"
+)
+
+// flakyPublisher records the published message IDs and fails the ones in fail once.
+type flakyPublisher struct {
+	got  []string
+	fail map[string]bool
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	if p.fail[message.ID] {
+		delete(p.fail, message.ID)
+		return errors.New("unavailable")
+	}
+	p.got = append(p.got, message.ID)
+	return nil
+}
+
+func Test_outboxUsecase_Relay(t *testing.T) {
+	ctx := context.Background()
+	r := inmemory.NewTaskRepository()
+	for _, name := range []string{"taskName1", "taskName2", "taskName3"} {
+		_, _ = r.Create(ctx, domain.Task{Name: name})
+	}
+	_ = r.Delete(ctx, 2)
+	pending, _ := r.PendingOutbox(ctx, 10)
+	ids := make([]string, 0, len(pending))
+	for _, message := range pending {
+		ids = append(ids, message.ID)
+	}
+
+	publisher := &flakyPublisher{fail: map[string]bool{ids[2]: true}}
+	u := NewOutboxUsecase(r, publisher, 2)
+	got, err := u.Relay(ctx)
+	if err == nil || got != 2 {
+		t.Fatalf("Relay() = %d, %v, want 2 and an error", got, err)
+	}
+	if !reflect.DeepEqual(publisher.got, ids[:2]) {
+		t.Errorf("Relay() published %v, want %v", publisher.got, ids[:2])
+	}
+
+	got, err = u.Relay(ctx)
+	if err != nil || got != 2 {
+		t.Fatalf("Relay() = %d, %v, want 2, nil", got, err)
+	}
+	if !reflect.DeepEqual(publisher.got, ids) {
+		t.Errorf("Relay() published %v, want %v", publisher.got, ids)
+	}
+	if got, err := u.Relay(ctx); got != 0 || err != nil {
+		t.Errorf("Relay() of an empty outbox = %d, %v", got, err)
+	}
+}
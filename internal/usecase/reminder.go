@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+type reminderUsecase struct {
+	reminderRepository domain.ReminderRepository
+	taskRepository     domain.TaskRepository
+	notifier           domain.Notifier
+	maxAttempts        int
+	now                func() time.Time
+}
+
+func NewReminderUsecase(reminderRepository domain.ReminderRepository, taskRepository domain.TaskRepository, notifier domain.Notifier, maxAttempts int) *reminderUsecase {
+	return &reminderUsecase{
+		reminderRepository: reminderRepository,
+		taskRepository:     taskRepository,
+		notifier:           notifier,
+		maxAttempts:        maxAttempts,
+		now:                time.Now,
+	}
+}
+
+func (u *reminderUsecase) Create(ctx context.Context, taskID int64, req domain.CreateReminderRequest) (domain.ReminderResponse, error) {
+	var rtn domain.ReminderResponse
+	if (req.At == nil) == (req.BeforeDueMinutes == nil) {
+		return rtn, domain.ErrInvalidPayload
+	}
+	task, err := u.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		return rtn, err
+	}
+	if req.BeforeDueMinutes != nil && task.DueAt == nil {
+		return rtn, domain.ErrTaskNoDueDate
+	}
+	reminder := domain.Reminder{
+		TaskID:           taskID,
+		TaskCreatedAt:    task.CreatedAt,
+		BeforeDueMinutes: req.BeforeDueMinutes,
+	}
+	if req.At != nil {
+		at := req.At.UTC()
+		reminder.At = &at
+	}
+	got, err := u.reminderRepository.Create(ctx, reminder)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *reminderUsecase) List(ctx context.Context, taskID int64) (domain.ListReminderResponse, error) {
+	var rtn domain.ListReminderResponse
+	if _, err := u.taskRepository.Get(ctx, taskID); err != nil {
+		return rtn, err
+	}
+	got, err := u.reminderRepository.ListByTask(ctx, taskID)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *reminderUsecase) Delete(ctx context.Context, taskID int64, id int64) error {
+	got, err := u.reminderRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if got.TaskID != taskID {
+		return domain.ErrDataNotFound
+	}
+	return u.reminderRepository.Delete(ctx, id)
+}
+
+// FireDue notifies the pending reminders whose time has come. A reminder is only marked as
+// fired once the notifier accepted it, and the reminders of a run are written together at
+// its end, so reminders missed while the service was down fire on the first run after a
+// restart and a crash during a run may repeat its deliveries. Reminders of tasks in the
+// trash wait until the task is restored, reminders of a task that is gone and whose ID was
+// handed out again are marked as failed. A reminder that failed maxAttempts times is marked
+// as failed and not retried.
+func (u *reminderUsecase) FireDue(ctx context.Context) (int, error) {
+	pending, err := u.reminderRepository.ListPending(ctx)
+	if err != nil {
+		return 0, err
+	}
+	now := u.now()
+	fired, failed := 0, 0
+	var lastErr, taskErr error
+	changed := make([]domain.Reminder, 0)
+	for _, reminder := range pending {
+		task, err := u.taskRepository.Get(ctx, reminder.TaskID)
+		if err == domain.ErrDataNotFound {
+			continue
+		}
+		if err != nil {
+			taskErr = err
+			break
+		}
+		if !reminder.TaskCreatedAt.IsZero() && !reminder.TaskCreatedAt.Equal(task.CreatedAt) {
+			failedAt := now
+			reminder.FailedAt = &failedAt
+			reminder.LastError = "task no longer exists"
+			changed = append(changed, reminder)
+			continue
+		}
+		at, ok := reminder.FireTime(task)
+		if !ok || at.After(now) {
+			continue
+		}
+		reminder.Attempts++
+		if err := u.notifier.Notify(ctx, domain.Notification{Reminder: reminder, Task: task, FireAt: at}); err != nil {
+			failed++
+			lastErr = err
+			reminder.LastError = err.Error()
+			if reminder.Attempts >= u.maxAttempts {
+				failedAt := now
+				reminder.FailedAt = &failedAt
+			}
+			changed = append(changed, reminder)
+			continue
+		}
+		firedAt := now
+		reminder.FiredAt = &firedAt
+		reminder.LastError = ""
+		changed = append(changed, reminder)
+		fired++
+	}
+	if len(changed) > 0 {
+		if err := u.reminderRepository.UpdateMany(ctx, changed); err != nil {
+			return fired, err
+		}
+	}
+	if taskErr != nil {
+		return fired, taskErr
+	}
+	if failed > 0 {
+		return fired, fmt.Errorf("%d reminders not delivered, last error: %w", failed, lastErr)
+	}
+	return fired, nil
+}
+
+// HandleTaskEvent removes the reminders of a purged task, they could never fire again.
+func (u *reminderUsecase) HandleTaskEvent(ctx context.Context, event domain.DomainEvent) error {
+	if e, ok := event.(domain.TaskPurged); ok {
+		return u.reminderRepository.DeleteByTask(ctx, e.ID)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"oa-gogolook/internal/repository/localfs"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock the tests move by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// recordingNotifier keeps the notifications it gets and fails while err is set.
+type recordingNotifier struct {
+	got []domain.Notification
+	err error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.got = append(n.got, notification)
+	return nil
+}
+
+func newTestReminderUsecase(t *testing.T, dir string, taskRepository domain.TaskRepository, clock *fakeClock, notifier domain.Notifier) *reminderUsecase {
+	reminderRepository, err := localfs.NewReminderRepository(dir)
+	if err != nil {
+		t.Fatalf("NewReminderRepository() error = %v", err)
+	}
+	u := NewReminderUsecase(reminderRepository, taskRepository, notifier, 3)
+	u.now = clock.Now
+	return u
+}
+
+func newTestReminderTasks(due time.Time) domain.TaskRepository {
+	r := inmemory.NewTaskRepository()
+	_, _ = r.Create(context.Background(), domain.Task{Name: "taskName1"})
+	_, _ = r.Create(context.Background(), domain.Task{Name: "taskName2", DueAt: &due})
+	return r
+}
+
+func Test_reminderUsecase_Create(t *testing.T) {
+	now := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	minutes := 30
+	tests := []struct {
+		name    string
+		taskID  int64
+		req     domain.CreateReminderRequest
+		wantErr error
+	}{
+		{
+			name:    "At",
+			taskID:  1,
+			req:     domain.CreateReminderRequest{At: &now},
+			wantErr: nil,
+		},
+		{
+			name:    "BeforeDue",
+			taskID:  2,
+			req:     domain.CreateReminderRequest{BeforeDueMinutes: &minutes},
+			wantErr: nil,
+		},
+		{
+			name:    "NoDueDate",
+			taskID:  1,
+			req:     domain.CreateReminderRequest{BeforeDueMinutes: &minutes},
+			wantErr: domain.ErrTaskNoDueDate,
+		},
+		{
+			name:    "Both",
+			taskID:  2,
+			req:     domain.CreateReminderRequest{At: &now, BeforeDueMinutes: &minutes},
+			wantErr: domain.ErrInvalidPayload,
+		},
+		{
+			name:    "Neither",
+			taskID:  2,
+			req:     domain.CreateReminderRequest{},
+			wantErr: domain.ErrInvalidPayload,
+		},
+		{
+			name:    "TaskNotFound",
+			taskID:  5,
+			req:     domain.CreateReminderRequest{At: &now},
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: now}
+			u := newTestReminderUsecase(t, t.TempDir(), newTestReminderTasks(now.Add(time.Hour)), clock, &recordingNotifier{})
+			got, err := u.Create(context.Background(), tt.taskID, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.Result.ID != 1 || got.Result.TaskID != tt.taskID) {
+				t.Errorf("Create() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_reminderUsecase_FireDue(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	tasks := newTestReminderTasks(clock.now.Add(2 * time.Hour))
+	notifier := &recordingNotifier{}
+	u := newTestReminderUsecase(t, t.TempDir(), tasks, clock, notifier)
+	at := clock.now.Add(10 * time.Minute)
+	minutes := 60
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &at})
+	_, _ = u.Create(ctx, 2, domain.CreateReminderRequest{BeforeDueMinutes: &minutes})
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		wantFired int
+		wantIDs   []int64
+	}{
+		{name: "NothingDue", advance: 9 * time.Minute, wantFired: 0},
+		{name: "AtReached", advance: time.Minute, wantFired: 1, wantIDs: []int64{1}},
+		{name: "AlreadyFired", advance: time.Minute, wantFired: 0, wantIDs: []int64{1}},
+		{name: "BeforeDueReached", advance: 50 * time.Minute, wantFired: 1, wantIDs: []int64{1, 2}},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		fired, err := u.FireDue(ctx)
+		if err != nil || fired != step.wantFired {
+			t.Errorf("%s: FireDue() = %d, %v, want %d", step.name, fired, err, step.wantFired)
+		}
+		if len(notifier.got) != len(step.wantIDs) {
+			t.Fatalf("%s: got %d notifications, want %d", step.name, len(notifier.got), len(step.wantIDs))
+		}
+		for i, id := range step.wantIDs {
+			if notifier.got[i].Reminder.ID != id {
+				t.Errorf("%s: notification %d is reminder %d, want %d", step.name, i, notifier.got[i].Reminder.ID, id)
+			}
+		}
+	}
+	if want := time.Date(2022, 11, 1, 11, 0, 0, 0, time.UTC); !notifier.got[1].FireAt.Equal(want) || notifier.got[1].Task.Name != "taskName2" {
+		t.Errorf("FireDue() notification = %v", notifier.got[1])
+	}
+}
+
+func Test_reminderUsecase_FireDueRetry(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	notifier := &recordingNotifier{err: errors.New("webhook down")}
+	u := newTestReminderUsecase(t, t.TempDir(), newTestReminderTasks(clock.now), clock, notifier)
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &clock.now})
+
+	if fired, err := u.FireDue(ctx); fired != 0 || err == nil {
+		t.Errorf("FireDue() = %d, %v, want 0 and an error", fired, err)
+	}
+	got, _ := u.List(ctx, 1)
+	if got.Result[0].FiredAt != nil || got.Result[0].Attempts != 1 || got.Result[0].LastError != "webhook down" {
+		t.Errorf("List() after failure = %v", got.Result[0])
+	}
+
+	notifier.err = nil
+	clock.Advance(time.Minute)
+	if fired, err := u.FireDue(ctx); fired != 1 || err != nil {
+		t.Errorf("FireDue() = %d, %v, want 1", fired, err)
+	}
+	got, _ = u.List(ctx, 1)
+	if got.Result[0].FiredAt == nil || got.Result[0].Attempts != 2 || got.Result[0].LastError != "" {
+		t.Errorf("List() after retry = %v", got.Result[0])
+	}
+}
+
+func Test_reminderUsecase_FireDueGiveUp(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	notifier := &recordingNotifier{err: errors.New("webhook down")}
+	u := newTestReminderUsecase(t, t.TempDir(), newTestReminderTasks(clock.now), clock, notifier)
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &clock.now})
+
+	for i := 0; i < u.maxAttempts; i++ {
+		_, _ = u.FireDue(ctx)
+		clock.Advance(time.Minute)
+	}
+	got, _ := u.List(ctx, 1)
+	if got.Result[0].FailedAt == nil || got.Result[0].Attempts != u.maxAttempts {
+		t.Errorf("List() after the last attempt = %v", got.Result[0])
+	}
+	notifier.err = nil
+	if fired, err := u.FireDue(ctx); fired != 0 || err != nil {
+		t.Errorf("FireDue() after giving up = %d, %v, want 0", fired, err)
+	}
+}
+
+func Test_reminderUsecase_FireDueAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	tasks := newTestReminderTasks(clock.now)
+	at := clock.now.Add(time.Hour)
+	u := newTestReminderUsecase(t, dir, tasks, clock, &recordingNotifier{})
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &at})
+
+	// The service is down while the reminder comes due.
+	clock.Advance(3 * time.Hour)
+	notifier := &recordingNotifier{}
+	restarted := newTestReminderUsecase(t, dir, tasks, clock, notifier)
+	if fired, err := restarted.FireDue(ctx); fired != 1 || err != nil {
+		t.Errorf("FireDue() = %d, %v, want 1", fired, err)
+	}
+	if len(notifier.got) != 1 || !notifier.got[0].FireAt.Equal(at) {
+		t.Errorf("FireDue() notifications = %v", notifier.got)
+	}
+}
+
+func Test_reminderUsecase_FireDueTrashedTask(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	tasks := newTestReminderTasks(clock.now)
+	notifier := &recordingNotifier{}
+	u := newTestReminderUsecase(t, t.TempDir(), tasks, clock, notifier)
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &clock.now})
+	_ = tasks.Delete(ctx, 1)
+
+	if fired, err := u.FireDue(ctx); fired != 0 || err != nil {
+		t.Errorf("FireDue() = %d, %v, want 0", fired, err)
+	}
+	_, _ = tasks.Restore(ctx, 1)
+	if fired, err := u.FireDue(ctx); fired != 1 || err != nil {
+		t.Errorf("FireDue() after restore = %d, %v, want 1", fired, err)
+	}
+}
+
+func Test_reminderUsecase_FireDueReusedTaskID(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	u := newTestReminderUsecase(t, dir, newTestReminderTasks(clock.now), clock, &recordingNotifier{})
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &clock.now})
+
+	// The in-memory tasks are gone after a restart and task 1 is a different task.
+	notifier := &recordingNotifier{}
+	restarted := newTestReminderUsecase(t, dir, newTestReminderTasks(clock.now), clock, notifier)
+	if fired, err := restarted.FireDue(ctx); fired != 0 || err != nil {
+		t.Errorf("FireDue() = %d, %v, want 0", fired, err)
+	}
+	if got, _ := restarted.reminderRepository.Get(ctx, 1); got.FailedAt == nil || len(notifier.got) != 0 {
+		t.Errorf("FireDue() reminder = %v, notifications %v, want the reminder failed", got, notifier.got)
+	}
+}
+
+// countingReminderRepository counts the writes of FireDue.
+type countingReminderRepository struct {
+	domain.ReminderRepository
+	updates int
+}
+
+func (r *countingReminderRepository) UpdateMany(ctx context.Context, reminders []domain.Reminder) error {
+	r.updates++
+	return r.ReminderRepository.UpdateMany(ctx, reminders)
+}
+
+func Test_reminderUsecase_FireDueWritesOnce(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	u := newTestReminderUsecase(t, t.TempDir(), newTestReminderTasks(clock.now), clock, &recordingNotifier{})
+	for i := 0; i < 3; i++ {
+		_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &clock.now})
+	}
+	repository := &countingReminderRepository{ReminderRepository: u.reminderRepository}
+	u.reminderRepository = repository
+
+	if fired, err := u.FireDue(ctx); fired != 3 || err != nil {
+		t.Errorf("FireDue() = %d, %v, want 3", fired, err)
+	}
+	if repository.updates != 1 {
+		t.Errorf("FireDue() wrote the reminders %d times, want once", repository.updates)
+	}
+	if got, _ := u.reminderRepository.ListPending(ctx); len(got) != 0 {
+		t.Errorf("ListPending() after FireDue() = %v", got)
+	}
+}
+
+func Test_reminderUsecase_HandleTaskEvent(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	tasks := newTestReminderTasks(clock.now)
+	u := newTestReminderUsecase(t, t.TempDir(), tasks, clock, &recordingNotifier{})
+	_, _ = u.Create(ctx, 1, domain.CreateReminderRequest{At: &clock.now})
+	_, _ = u.Create(ctx, 2, domain.CreateReminderRequest{At: &clock.now})
+
+	if err := u.HandleTaskEvent(ctx, domain.TaskPurged{ID: 1}); err != nil {
+		t.Fatalf("HandleTaskEvent() error = %v", err)
+	}
+	if got, _ := u.reminderRepository.ListPending(ctx); len(got) != 1 || got[0].TaskID != 2 {
+		t.Errorf("ListPending() after purge = %v", got)
+	}
+}
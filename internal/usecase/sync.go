@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"strconv"
+	"strings"
+)
+
+type syncUsecase struct {
+	taskRepository domain.TaskRepository
+}
+
+func NewSyncUsecase(taskRepository domain.TaskRepository) *syncUsecase {
+	return &syncUsecase{
+		taskRepository: taskRepository,
+	}
+}
+
+// Sync returns the changes after the token, which is the epoch of the change feed and the
+// sequence number of the last change the client has seen. A token of another epoch or
+// ahead of the change feed was handed out before the feed was reset, the client has to
+// sync from scratch.
+func (u *syncUsecase) Sync(ctx context.Context, req domain.SyncRequest) (domain.SyncResponse, error) {
+	var rtn domain.SyncResponse
+	var epoch string
+	var since int64
+	if req.Since != "" {
+		var err error
+		epoch, since, err = parseSyncToken(req.Since)
+		if err != nil {
+			return rtn, domain.ErrInvalidParameters
+		}
+	}
+	changes, err := u.taskRepository.Changes(ctx, since)
+	if err != nil {
+		return rtn, err
+	}
+	if since > 0 && epoch != changes.Epoch || since > changes.Seq {
+		return rtn, domain.ErrSyncTokenExpired
+	}
+	rtn.Created = changes.Created
+	rtn.Updated = changes.Updated
+	rtn.Deleted = make([]domain.TaskTombstone, 0, len(changes.Deleted))
+	for _, id := range changes.Deleted {
+		rtn.Deleted = append(rtn.Deleted, domain.TaskTombstone{ID: id})
+	}
+	rtn.Token = formatSyncToken(changes.Epoch, changes.Seq)
+	return rtn, nil
+}
+
+func formatSyncToken(epoch string, seq int64) string {
+	if epoch == "" {
+		return strconv.FormatInt(seq, 10)
+	}
+	return epoch + "." + strconv.FormatInt(seq, 10)
+}
+
+// parseSyncToken splits a token made by formatSyncToken, a token without an epoch was
+// handed out by an older version or by a feed without one.
+func parseSyncToken(token string) (string, int64, error) {
+	var epoch string
+	if i := strings.LastIndex(token, "."); i >= 0 {
+		epoch, token = token[:i], token[i+1:]
+	}
+	seq, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	if seq < 0 {
+		return "", 0, domain.ErrInvalidParameters
+	}
+	return epoch, seq, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_syncUsecase_Sync(t *testing.T) {
+	// {epoch} in since stands for the epoch of the change feed.
+	tests := []struct {
+		name        string
+		since       string
+		wantCreated []string
+		wantUpdated []string
+		wantDeleted []domain.TaskTombstone
+		wantErr     error
+	}{
+		{
+			name:        "FromScratch",
+			since:       "",
+			wantCreated: []string{"taskName2"},
+			wantUpdated: []string{},
+			wantDeleted: []domain.TaskTombstone{},
+			wantErr:     nil,
+		},
+		{
+			name:        "AfterCreates",
+			since:       "{epoch}.2",
+			wantCreated: []string{},
+			wantUpdated: []string{"taskName2"},
+			wantDeleted: []domain.TaskTombstone{{ID: 1}},
+			wantErr:     nil,
+		},
+		{
+			name:        "UpToDate",
+			since:       "{epoch}.4",
+			wantCreated: []string{},
+			wantUpdated: []string{},
+			wantDeleted: []domain.TaskTombstone{},
+			wantErr:     nil,
+		},
+		{
+			name:    "NotANumber",
+			since:   "abc",
+			wantErr: domain.ErrInvalidParameters,
+		},
+		{
+			name:    "Negative",
+			since:   "{epoch}.-1",
+			wantErr: domain.ErrInvalidParameters,
+		},
+		{
+			name:    "AheadOfFeed",
+			since:   "{epoch}.5",
+			wantErr: domain.ErrSyncTokenExpired,
+		},
+		{
+			name:    "OtherEpoch",
+			since:   "other.2",
+			wantErr: domain.ErrSyncTokenExpired,
+		},
+		{
+			name:    "WithoutEpoch",
+			since:   "2",
+			wantErr: domain.ErrSyncTokenExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := inmemory.NewTaskRepository()
+			ctx := context.Background()
+			_, _ = r.Create(ctx, domain.Task{Name: "taskName1"})
+			_, _ = r.Create(ctx, domain.Task{Name: "taskName2"})
+			_ = r.Delete(ctx, 1)
+			_, _ = r.Update(ctx, 2, domain.StatusComplete, "")
+			u := NewSyncUsecase(r)
+			changes, _ := r.Changes(ctx, 0)
+			since := strings.ReplaceAll(tt.since, "{epoch}", changes.Epoch)
+
+			got, err := u.Sync(ctx, domain.SyncRequest{Since: since})
+			if err != tt.wantErr {
+				t.Fatalf("Sync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if want := changes.Epoch + ".4"; got.Token != want {
+				t.Errorf("Sync() Token = %q, want %q", got.Token, want)
+			}
+			if !reflect.DeepEqual(taskNames(got.Created), tt.wantCreated) || !reflect.DeepEqual(taskNames(got.Updated), tt.wantUpdated) {
+				t.Errorf("Sync() Created = %v, Updated = %v, want %v, %v", taskNames(got.Created), taskNames(got.Updated), tt.wantCreated, tt.wantUpdated)
+			}
+			if !reflect.DeepEqual(got.Deleted, tt.wantDeleted) {
+				t.Errorf("Sync() Deleted = %v, want %v", got.Deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func taskNames(tasks []domain.Task) []string {
+	rtn := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		rtn = append(rtn, task.Name)
+	}
+	return rtn
+}
@@ -3,21 +3,98 @@ package usecase
 import (
 	"context"
 	"oa-gogolook/internal/domain"
+	"reflect"
+	"sync"
+	"time"
 )
 
+// taskUsecase publishes a domain event after every successful change of a task. The
+// change and its publication happen under the lock of the task, so the events of a task
+// reach the bus in the order of the changes.
 type taskUsecase struct {
-	taskRepository domain.TaskRepository
+	taskRepository    domain.TaskRepository
+	userRepository    domain.UserRepository
+	commentRepository domain.CommentRepository
+	commentPolicy     domain.CommentPolicy
+	events            domain.EventBus
+	locks             taskLocks
 }
 
-func NewTaskUsecase(taskRepository domain.TaskRepository) *taskUsecase {
+func NewTaskUsecase(taskRepository domain.TaskRepository, userRepository domain.UserRepository, commentRepository domain.CommentRepository, commentPolicy domain.CommentPolicy, events domain.EventBus) *taskUsecase {
 	return &taskUsecase{
-		taskRepository: taskRepository,
+		taskRepository:    taskRepository,
+		userRepository:    userRepository,
+		commentRepository: commentRepository,
+		commentPolicy:     commentPolicy,
+		events:            events,
 	}
 }
 
-func (u *taskUsecase) List(ctx context.Context) (domain.ListTaskResponse, error) {
+// taskLocks hands out one of a fixed set of mutexes per task ID. The ID of a new task is
+// only known once the repository returns it, so creating takes all of them at once.
+type taskLocks struct {
+	creating sync.RWMutex
+	stripes  [64]sync.Mutex
+}
+
+func (l *taskLocks) lock(id int64) func() {
+	l.creating.RLock()
+	m := &l.stripes[uint64(id)%uint64(len(l.stripes))]
+	m.Lock()
+	return func() {
+		m.Unlock()
+		l.creating.RUnlock()
+	}
+}
+
+func (l *taskLocks) create() func() {
+	l.creating.Lock()
+	return l.creating.Unlock
+}
+
+// publish hands the event to the bus, a taskUsecase built without one publishes nothing.
+func (u *taskUsecase) publish(ctx context.Context, event domain.DomainEvent) {
+	if u.events != nil {
+		u.events.Publish(ctx, event)
+	}
+}
+
+// change runs fn under the lock of the task and publishes TaskUpdated with the task read
+// before fn.
+func (u *taskUsecase) change(ctx context.Context, id int64, fn func() (domain.Task, error)) (domain.Task, error) {
+	_, got, err := u.changeFrom(ctx, id, fn)
+	return got, err
+}
+
+// changeFrom is change that also returns the task read before fn.
+func (u *taskUsecase) changeFrom(ctx context.Context, id int64, fn func() (domain.Task, error)) (domain.Task, domain.Task, error) {
+	unlock := u.locks.lock(id)
+	defer unlock()
+	before, err := u.taskRepository.Get(ctx, id)
+	if err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	got, err := fn()
+	if err != nil {
+		return domain.Task{}, domain.Task{}, err
+	}
+	u.publish(ctx, domain.TaskUpdated{Before: before, Task: got})
+	return before, got, nil
+}
+
+func (u *taskUsecase) List(ctx context.Context, req domain.ListTaskRequest) (domain.ListTaskResponse, error) {
 	var rtn domain.ListTaskResponse
-	got, err := u.taskRepository.List(ctx)
+	filter := domain.TaskFilter{
+		IncludeDeleted:  req.IncludeDeleted,
+		IncludeArchived: req.IncludeArchived,
+	}
+	var got []domain.Task
+	var err error
+	if req.AsOf.IsZero() {
+		got, err = u.taskRepository.List(ctx, filter)
+	} else {
+		got, err = u.taskRepository.ListAsOf(ctx, filter, req.AsOf)
+	}
 	if err != nil {
 		return rtn, err
 	}
@@ -27,17 +104,42 @@ func (u *taskUsecase) List(ctx context.Context) (domain.ListTaskResponse, error)
 
 func (u *taskUsecase) Create(ctx context.Context, req domain.CreateTaskRequest) (domain.CreateTaskResponse, error) {
 	var rtn domain.CreateTaskResponse
-	got, err := u.taskRepository.Create(ctx, req.Name)
+	unlock := u.locks.create()
+	defer unlock()
+	got, err := u.taskRepository.Create(ctx, domain.Task{
+		Name:        req.Name,
+		Description: req.Description,
+		Estimate:    req.Estimate,
+		DueAt:       req.DueAt,
+		Tags:        req.Tags,
+	})
 	if err != nil {
 		return rtn, err
 	}
+	u.publish(ctx, domain.TaskCreated{Task: got})
 	rtn.Result = got
 	return rtn, nil
 
 }
 
+// CreateMany publishes TaskCreated for every task once all of them are created.
+func (u *taskUsecase) CreateMany(ctx context.Context, tasks []domain.Task) ([]domain.Task, error) {
+	unlock := u.locks.create()
+	defer unlock()
+	got, err := u.taskRepository.CreateMany(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range got {
+		u.publish(ctx, domain.TaskCreated{Task: task})
+	}
+	return got, nil
+}
+
 func (u *taskUsecase) Update(ctx context.Context, req domain.UpdateTaskRequest) (domain.UpdateTaskResponse, error) {
 	var rtn domain.UpdateTaskResponse
+	unlock := u.locks.lock(req.ID)
+	defer unlock()
 	gotTask, err := u.taskRepository.Get(ctx, req.ID)
 	if err != nil {
 		return rtn, err
@@ -45,21 +147,101 @@ func (u *taskUsecase) Update(ctx context.Context, req domain.UpdateTaskRequest)
 	if gotTask.Name != req.Name {
 		return rtn, domain.ErrTaskNameNotMatch
 	}
+	description := gotTask.Description
+	if req.Description != nil {
+		description = *req.Description
+	}
 
-	got, err := u.taskRepository.Update(ctx, req.ID, *req.Status)
+	got, err := u.taskRepository.Update(ctx, req.ID, *req.Status, description)
 	if err != nil {
 		return rtn, err
 	}
+	u.publish(ctx, domain.TaskUpdated{Before: gotTask, Task: got})
 	rtn.Result = got
+	rtn.Before = gotTask
 	return rtn, nil
 }
 
-func (u *taskUsecase) Delete(ctx context.Context, id int64) error {
-	err := u.taskRepository.Delete(ctx, id)
+func (u *taskUsecase) Revert(ctx context.Context, expected domain.Task, to domain.Task) (domain.UpdateTaskResponse, error) {
+	var rtn domain.UpdateTaskResponse
+	unlock := u.locks.lock(expected.ID)
+	defer unlock()
+	gotTask, err := u.taskRepository.Get(ctx, expected.ID)
 	if err != nil {
-		return err
+		return rtn, err
 	}
-	return nil
+	if changedSince(gotTask, expected) {
+		return rtn, domain.ErrUndoConflict
+	}
+	got, err := u.taskRepository.Revert(ctx, expected.ID, to)
+	if err != nil {
+		return rtn, err
+	}
+	u.publish(ctx, domain.TaskUpdated{Before: gotTask, Task: got})
+	rtn.Result = got
+	rtn.Before = gotTask
+	return rtn, nil
+}
+
+// changedSince reports whether the task differs from the state it is expected in. Ranks
+// are ignored since moving other tasks may respread them.
+func changedSince(current domain.Task, expected domain.Task) bool {
+	current.Rank, expected.Rank = "", ""
+	return !reflect.DeepEqual(current, expected)
+}
+
+// Delete moves the task to the trash, its comments are kept until the task is purged.
+func (u *taskUsecase) Delete(ctx context.Context, id int64) (domain.DeleteTaskResponse, error) {
+	var rtn domain.DeleteTaskResponse
+	unlock := u.locks.lock(id)
+	defer unlock()
+	before, err := u.taskRepository.Get(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	if err := u.taskRepository.Delete(ctx, id); err != nil {
+		return rtn, err
+	}
+	u.publish(ctx, domain.TaskDeleted{Task: before})
+	rtn.Before = before
+	return rtn, nil
+}
+
+func (u *taskUsecase) Restore(ctx context.Context, id int64) (domain.RestoreTaskResponse, error) {
+	var rtn domain.RestoreTaskResponse
+	unlock := u.locks.lock(id)
+	defer unlock()
+	got, err := u.taskRepository.Restore(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	u.publish(ctx, domain.TaskCreated{Task: got, Restored: true})
+	rtn.Result = got
+	return rtn, nil
+}
+
+// Purge publishes TaskPurged for every purged task, so the attachments and reminders of
+// the task are removed by their subscribers.
+func (u *taskUsecase) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ids, err := u.taskRepository.Purge(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if u.commentPolicy == domain.CommentPolicyDelete {
+			if err := u.commentRepository.DeleteByTask(ctx, id); err != nil {
+				return len(ids), err
+			}
+		}
+		u.publishPurged(ctx, id)
+	}
+	return len(ids), nil
+}
+
+func (u *taskUsecase) publishPurged(ctx context.Context, id int64) {
+	unlock := u.locks.lock(id)
+	defer unlock()
+	u.publish(ctx, domain.TaskPurged{ID: id})
 }
 
 func (u *taskUsecase) Get(ctx context.Context, id int64) (domain.Task, error) {
@@ -69,3 +251,180 @@ func (u *taskUsecase) Get(ctx context.Context, id int64) (domain.Task, error) {
 	}
 	return got, nil
 }
+
+func (u *taskUsecase) GetAsOf(ctx context.Context, id int64, at time.Time) (domain.Task, error) {
+	got, err := u.taskRepository.GetAsOf(ctx, id, at)
+	if err != nil {
+		return got, err
+	}
+	return got, nil
+}
+
+func (u *taskUsecase) Assign(ctx context.Context, id int64, assigneeID int64) (domain.AssignTaskResponse, error) {
+	var rtn domain.AssignTaskResponse
+	if _, err := u.userRepository.Get(ctx, assigneeID); err != nil {
+		return rtn, err
+	}
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Assign(ctx, id, &assigneeID)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) Unassign(ctx context.Context, id int64) (domain.AssignTaskResponse, error) {
+	var rtn domain.AssignTaskResponse
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Assign(ctx, id, nil)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) SetEstimate(ctx context.Context, id int64, estimate domain.Estimate) (domain.EstimateTaskResponse, error) {
+	var rtn domain.EstimateTaskResponse
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Estimate(ctx, id, &estimate)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) ClearEstimate(ctx context.Context, id int64) (domain.EstimateTaskResponse, error) {
+	var rtn domain.EstimateTaskResponse
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Estimate(ctx, id, nil)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) ListByAssignee(ctx context.Context, assigneeID int64) (domain.ListTaskResponse, error) {
+	var rtn domain.ListTaskResponse
+	if _, err := u.userRepository.Get(ctx, assigneeID); err != nil {
+		return rtn, err
+	}
+	got, err := u.taskRepository.ListByAssignee(ctx, assigneeID)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) Move(ctx context.Context, id int64, req domain.MoveTaskRequest) (domain.MoveTaskResponse, error) {
+	var rtn domain.MoveTaskResponse
+	if (req.BeforeID == 0) == (req.AfterID == 0) || req.BeforeID == id || req.AfterID == id {
+		return rtn, domain.ErrInvalidPayload
+	}
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Move(ctx, id, req.BeforeID, req.AfterID)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) Archive(ctx context.Context, id int64) (domain.ArchiveTaskResponse, error) {
+	var rtn domain.ArchiveTaskResponse
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Archive(ctx, id)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) Unarchive(ctx context.Context, id int64) (domain.ArchiveTaskResponse, error) {
+	var rtn domain.ArchiveTaskResponse
+	got, err := u.change(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.Unarchive(ctx, id)
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *taskUsecase) AutoArchive(ctx context.Context, completedBefore time.Time) (int, error) {
+	ids, err := u.taskRepository.ArchiveCompleted(ctx, completedBefore)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err := u.publishArchived(ctx, id); err != nil {
+			return len(ids), err
+		}
+	}
+	return len(ids), nil
+}
+
+// publishArchived publishes the automatic archiving of a task, which only set ArchivedAt.
+// A task trashed in the meantime was published as deleted already.
+func (u *taskUsecase) publishArchived(ctx context.Context, id int64) error {
+	unlock := u.locks.lock(id)
+	defer unlock()
+	got, err := u.taskRepository.Get(ctx, id)
+	if err == domain.ErrDataNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	before := got
+	before.ArchivedAt = nil
+	u.publish(ctx, domain.TaskUpdated{Before: before, Task: got})
+	return nil
+}
+
+func (u *taskUsecase) AddChecklistItem(ctx context.Context, id int64, text string) (domain.ChecklistResponse, error) {
+	return u.changeChecklist(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.AddChecklistItem(ctx, id, text)
+	})
+}
+
+func (u *taskUsecase) ReorderChecklist(ctx context.Context, id int64, itemIDs []int64) (domain.ChecklistResponse, error) {
+	return u.changeChecklist(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.ReorderChecklist(ctx, id, itemIDs)
+	})
+}
+
+func (u *taskUsecase) ToggleChecklistItem(ctx context.Context, id int64, itemID int64) (domain.ChecklistResponse, error) {
+	return u.changeChecklist(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.ToggleChecklistItem(ctx, id, itemID)
+	})
+}
+
+func (u *taskUsecase) RemoveChecklistItem(ctx context.Context, id int64, itemID int64) (domain.ChecklistResponse, error) {
+	return u.changeChecklist(ctx, id, func() (domain.Task, error) {
+		return u.taskRepository.RemoveChecklistItem(ctx, id, itemID)
+	})
+}
+
+func (u *taskUsecase) changeChecklist(ctx context.Context, id int64, fn func() (domain.Task, error)) (domain.ChecklistResponse, error) {
+	var rtn domain.ChecklistResponse
+	before, got, err := u.changeFrom(ctx, id, fn)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	rtn.Before = before
+	return rtn, nil
+}
@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/eventbus"
 	"oa-gogolook/internal/repository/inmemory"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_taskUsecase_Create(t *testing.T) {
@@ -43,6 +46,7 @@ func Test_taskUsecase_Create(t *testing.T) {
 					ID:     1,
 					Status: domain.StatusIncomplete,
 					Name:   "taskName",
+					Rank:   "V",
 				},
 			},
 			wantErr: false,
@@ -58,6 +62,7 @@ func Test_taskUsecase_Create(t *testing.T) {
 				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			got.Result.CreatedAt = time.Time{}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Create() got = %v, want %v", got, tt.want)
 			}
@@ -83,9 +88,9 @@ func Test_taskUsecase_List(t *testing.T) {
 		{
 			name: "OK",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{
 				taskRepository: inmemory.NewTaskRepository(),
@@ -99,16 +104,19 @@ func Test_taskUsecase_List(t *testing.T) {
 						ID:     1,
 						Status: domain.StatusIncomplete,
 						Name:   "taskName1",
+						Rank:   "V",
 					},
 					{
 						ID:     2,
 						Status: domain.StatusIncomplete,
 						Name:   "taskName2",
+						Rank:   "W",
 					},
 					{
 						ID:     3,
 						Status: domain.StatusIncomplete,
 						Name:   "taskName3",
+						Rank:   "X",
 					},
 				},
 			},
@@ -136,11 +144,12 @@ func Test_taskUsecase_List(t *testing.T) {
 				taskRepository: tt.fields.taskRepository,
 			}
 			tt.buildStubs(u.taskRepository)
-			got, err := u.List(tt.args.ctx)
+			got, err := u.List(tt.args.ctx, domain.ListTaskRequest{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			withoutCreatedAt(got.Result)
 			if !reflect.DeepEqual(got, tt.want) {
 				if len(got.Result) == 0 && len(tt.want.Result) == 0 {
 					return
@@ -170,9 +179,9 @@ func Test_taskUsecase_Update(t *testing.T) {
 		{
 			name: "OK",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -188,6 +197,13 @@ func Test_taskUsecase_Update(t *testing.T) {
 					ID:     1,
 					Status: domain.StatusComplete,
 					Name:   "taskName1",
+					Rank:   "V",
+				},
+				Before: domain.Task{
+					ID:     1,
+					Status: domain.StatusIncomplete,
+					Name:   "taskName1",
+					Rank:   "V",
 				},
 			},
 			wantErr: false,
@@ -195,9 +211,9 @@ func Test_taskUsecase_Update(t *testing.T) {
 		{
 			name: "NameNotMatch",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -214,9 +230,9 @@ func Test_taskUsecase_Update(t *testing.T) {
 		{
 			name: "TaskNotFound",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -233,9 +249,9 @@ func Test_taskUsecase_Update(t *testing.T) {
 		{
 			name: "StatusNotChange",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -251,6 +267,13 @@ func Test_taskUsecase_Update(t *testing.T) {
 					ID:     1,
 					Status: domain.StatusIncomplete,
 					Name:   "taskName1",
+					Rank:   "V",
+				},
+				Before: domain.Task{
+					ID:     1,
+					Status: domain.StatusIncomplete,
+					Name:   "taskName1",
+					Rank:   "V",
 				},
 			},
 			wantErr: false,
@@ -267,6 +290,11 @@ func Test_taskUsecase_Update(t *testing.T) {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if (got.Result.Status == domain.StatusComplete) != (got.Result.CompletedAt != nil) {
+				t.Errorf("Update() completedAt = %v, status %v", got.Result.CompletedAt, got.Result.Status)
+			}
+			got.Result.CompletedAt = nil
+			got.Result.CreatedAt, got.Before.CreatedAt = time.Time{}, time.Time{}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Update() got = %v, want %v", got, tt.want)
 			}
@@ -274,6 +302,35 @@ func Test_taskUsecase_Update(t *testing.T) {
 	}
 }
 
+func Test_taskUsecase_Revert(t *testing.T) {
+	u := &taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+	}
+	created, _ := u.Create(context.Background(), domain.CreateTaskRequest{Name: "taskName1"})
+	completed, _ := u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &domain.StatusComplete, Name: "taskName1"})
+	description := "changed"
+	updated, _ := u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &domain.StatusIncomplete, Name: "taskName1", Description: &description})
+
+	got, err := u.Revert(context.Background(), updated.Result, completed.Result)
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Before, updated.Result) {
+		t.Errorf("Revert() before = %v, want %v", got.Before, updated.Result)
+	}
+	if got.Result.Status != domain.StatusComplete || got.Result.Description != "" || got.Result.CompletedAt == nil || !got.Result.CompletedAt.Equal(*completed.Result.CompletedAt) {
+		t.Errorf("Revert() = %v, want the completed task with its completion time %v", got.Result, completed.Result.CompletedAt)
+	}
+
+	_, err = u.Revert(context.Background(), updated.Result, created.Result)
+	if err != domain.ErrUndoConflict {
+		t.Errorf("Revert() error = %v, want %v", err, domain.ErrUndoConflict)
+	}
+	if current, _ := u.Get(context.Background(), 1); current.Status != domain.StatusComplete {
+		t.Errorf("Revert() changed a task that is not in the expected state, status = %v", current.Status)
+	}
+}
+
 func Test_taskUsecase_Delete(t *testing.T) {
 	type fields struct {
 		taskRepository domain.TaskRepository
@@ -292,9 +349,9 @@ func Test_taskUsecase_Delete(t *testing.T) {
 		{
 			name: "OK",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -306,9 +363,9 @@ func Test_taskUsecase_Delete(t *testing.T) {
 		{
 			name: "NotFound",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -324,7 +381,7 @@ func Test_taskUsecase_Delete(t *testing.T) {
 				taskRepository: tt.fields.taskRepository,
 			}
 			tt.buildStubs(u.taskRepository)
-			if err := u.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			if _, err := u.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
 				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -350,9 +407,9 @@ func Test_taskUsecase_Get(t *testing.T) {
 		{
 			name: "OK",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -363,15 +420,16 @@ func Test_taskUsecase_Get(t *testing.T) {
 				ID:     1,
 				Status: domain.StatusIncomplete,
 				Name:   "taskName1",
+				Rank:   "V",
 			},
 			wantErr: false,
 		},
 		{
 			name: "NotFound",
 			buildStubs: func(repo domain.TaskRepository) {
-				_, _ = repo.Create(context.Background(), "taskName1")
-				_, _ = repo.Create(context.Background(), "taskName2")
-				_, _ = repo.Create(context.Background(), "taskName3")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName3"})
 			},
 			fields: fields{taskRepository: inmemory.NewTaskRepository()},
 			args: args{
@@ -393,9 +451,585 @@ func Test_taskUsecase_Get(t *testing.T) {
 				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			got.CreatedAt = time.Time{}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Get() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_taskUsecase_Assign(t *testing.T) {
+	assigneeID := int64(1)
+	type fields struct {
+		taskRepository domain.TaskRepository
+		userRepository domain.UserRepository
+	}
+	type args struct {
+		ctx        context.Context
+		id         int64
+		assigneeID int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(repo domain.TaskRepository, userRepo domain.UserRepository)
+		fields     fields
+		args       args
+		want       domain.AssignTaskResponse
+		wantErr    error
+	}{
+		{
+			name: "OK",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = userRepo.Create(context.Background(), "userName1")
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				assigneeID: assigneeID,
+			},
+			want: domain.AssignTaskResponse{
+				Result: domain.Task{
+					ID:         1,
+					Status:     domain.StatusIncomplete,
+					Name:       "taskName1",
+					AssigneeID: &assigneeID,
+					Rank:       "V",
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				assigneeID: assigneeID,
+			},
+			want:    domain.AssignTaskResponse{},
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name: "TaskNotFound",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+				_, _ = userRepo.Create(context.Background(), "userName1")
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				assigneeID: assigneeID,
+			},
+			want:    domain.AssignTaskResponse{},
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &taskUsecase{
+				taskRepository: tt.fields.taskRepository,
+				userRepository: tt.fields.userRepository,
+			}
+			tt.buildStubs(u.taskRepository, u.userRepository)
+			got, err := u.Assign(tt.args.ctx, tt.args.id, tt.args.assigneeID)
+			if err != tt.wantErr {
+				t.Errorf("Assign() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			got.Result.CreatedAt = time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Assign() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_Unassign(t *testing.T) {
+	type fields struct {
+		taskRepository domain.TaskRepository
+		userRepository domain.UserRepository
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(repo domain.TaskRepository, userRepo domain.UserRepository)
+		fields     fields
+		args       args
+		want       domain.AssignTaskResponse
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				user, _ := userRepo.Create(context.Background(), "userName1")
+				_, _ = repo.Assign(context.Background(), 1, &user.ID)
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want: domain.AssignTaskResponse{
+				Result: domain.Task{
+					ID:     1,
+					Status: domain.StatusIncomplete,
+					Name:   "taskName1",
+					Rank:   "V",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "TaskNotFound",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want:    domain.AssignTaskResponse{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &taskUsecase{
+				taskRepository: tt.fields.taskRepository,
+				userRepository: tt.fields.userRepository,
+			}
+			tt.buildStubs(u.taskRepository, u.userRepository)
+			got, err := u.Unassign(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unassign() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			got.Result.CreatedAt = time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unassign() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_ListByAssignee(t *testing.T) {
+	assigneeID := int64(1)
+	type fields struct {
+		taskRepository domain.TaskRepository
+		userRepository domain.UserRepository
+	}
+	type args struct {
+		ctx        context.Context
+		assigneeID int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(repo domain.TaskRepository, userRepo domain.UserRepository)
+		fields     fields
+		args       args
+		want       domain.ListTaskResponse
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+				user, _ := userRepo.Create(context.Background(), "userName1")
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName1"})
+				_, _ = repo.Create(context.Background(), domain.Task{Name: "taskName2"})
+				_, _ = repo.Assign(context.Background(), 2, &user.ID)
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				assigneeID: assigneeID,
+			},
+			want: domain.ListTaskResponse{
+				Result: []domain.Task{
+					{
+						ID:         2,
+						Status:     domain.StatusIncomplete,
+						Name:       "taskName2",
+						AssigneeID: &assigneeID,
+						Rank:       "W",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(repo domain.TaskRepository, userRepo domain.UserRepository) {
+			},
+			fields: fields{
+				taskRepository: inmemory.NewTaskRepository(),
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx:        context.Background(),
+				assigneeID: assigneeID,
+			},
+			want:    domain.ListTaskResponse{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &taskUsecase{
+				taskRepository: tt.fields.taskRepository,
+				userRepository: tt.fields.userRepository,
+			}
+			tt.buildStubs(u.taskRepository, u.userRepository)
+			got, err := u.ListByAssignee(tt.args.ctx, tt.args.assigneeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListByAssignee() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			withoutCreatedAt(got.Result)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListByAssignee() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_PurgeCommentPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    domain.CommentPolicy
+		wantTotal int
+	}{
+		{
+			name:      "Delete",
+			policy:    domain.CommentPolicyDelete,
+			wantTotal: 0,
+		},
+		{
+			name:      "Retain",
+			policy:    domain.CommentPolicyRetain,
+			wantTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepository := inmemory.NewCommentRepository()
+			u := &taskUsecase{
+				taskRepository:    inmemory.NewTaskRepository(),
+				commentRepository: commentRepository,
+				commentPolicy:     tt.policy,
+			}
+			_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+			_, _ = commentRepository.Create(context.Background(), 1, 1, "body")
+			if _, err := u.Delete(context.Background(), 1); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, total, _ := commentRepository.List(context.Background(), 1, 0, 10); total != 1 {
+				t.Fatalf("Delete() left %d comments, want 1", total)
+			}
+			purged, err := u.Purge(context.Background(), time.Now().Add(time.Second))
+			if err != nil || purged != 1 {
+				t.Fatalf("Purge() = %d, %v, want 1, nil", purged, err)
+			}
+			_, total, _ := commentRepository.List(context.Background(), 1, 0, 10)
+			if total != tt.wantTotal {
+				t.Errorf("Purge() left %d comments, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_UpdateDescription(t *testing.T) {
+	newDescription := "new *description*"
+	tests := []struct {
+		name        string
+		description *string
+		want        string
+	}{
+		{
+			name:        "Keep",
+			description: nil,
+			want:        "old description",
+		},
+		{
+			name:        "Replace",
+			description: &newDescription,
+			want:        newDescription,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &taskUsecase{
+				taskRepository: inmemory.NewTaskRepository(),
+			}
+			_, _ = u.Create(context.Background(), domain.CreateTaskRequest{Name: "taskName1", Description: "old description"})
+			got, err := u.Update(context.Background(), domain.UpdateTaskRequest{
+				ID:          1,
+				Status:      &domain.StatusComplete,
+				Name:        "taskName1",
+				Description: tt.description,
+			})
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if got.Result.Description != tt.want {
+				t.Errorf("Update() description = %q, want %q", got.Result.Description, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_Move(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int64
+		req     domain.MoveTaskRequest
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			id:      3,
+			req:     domain.MoveTaskRequest{BeforeID: 1},
+			wantErr: nil,
+		},
+		{
+			name:    "BothSet",
+			id:      3,
+			req:     domain.MoveTaskRequest{BeforeID: 1, AfterID: 2},
+			wantErr: domain.ErrInvalidPayload,
+		},
+		{
+			name:    "NoneSet",
+			id:      3,
+			req:     domain.MoveTaskRequest{},
+			wantErr: domain.ErrInvalidPayload,
+		},
+		{
+			name:    "Itself",
+			id:      3,
+			req:     domain.MoveTaskRequest{AfterID: 3},
+			wantErr: domain.ErrInvalidPayload,
+		},
+		{
+			name:    "NotFound",
+			id:      5,
+			req:     domain.MoveTaskRequest{AfterID: 1},
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &taskUsecase{
+				taskRepository: inmemory.NewTaskRepository(),
+			}
+			for _, name := range []string{"taskName1", "taskName2", "taskName3"} {
+				_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: name})
+			}
+			_, err := u.Move(context.Background(), tt.id, tt.req)
+			if err != tt.wantErr {
+				t.Fatalf("Move() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := u.List(context.Background(), domain.ListTaskRequest{})
+			if got.Result[0].ID != tt.id {
+				t.Errorf("Move() first task = %d, want %d", got.Result[0].ID, tt.id)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_Restore(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			id:      1,
+			wantErr: nil,
+		},
+		{
+			name:    "NotDeleted",
+			id:      2,
+			wantErr: domain.ErrTaskNotDeleted,
+		},
+		{
+			name:    "NotFound",
+			id:      3,
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &taskUsecase{
+				taskRepository: inmemory.NewTaskRepository(),
+			}
+			_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+			_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName2"})
+			_, _ = u.Delete(context.Background(), 1)
+			got, err := u.Restore(context.Background(), tt.id)
+			if err != tt.wantErr {
+				t.Fatalf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Result.ID != tt.id || got.Result.DeletedAt != nil) {
+				t.Errorf("Restore() got = %v, want restored task %d", got, tt.id)
+			}
+		})
+	}
+}
+
+func Test_taskUsecase_Archive(t *testing.T) {
+	u := &taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+	}
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName2"})
+
+	got, err := u.Archive(context.Background(), 1)
+	if err != nil || got.Result.ArchivedAt == nil {
+		t.Fatalf("Archive() = %v, %v, want archived task", got, err)
+	}
+	list, _ := u.List(context.Background(), domain.ListTaskRequest{})
+	if len(list.Result) != 1 || list.Result[0].ID != 2 {
+		t.Errorf("List() = %v, want only task 2", list.Result)
+	}
+	list, _ = u.List(context.Background(), domain.ListTaskRequest{IncludeArchived: true})
+	if len(list.Result) != 2 {
+		t.Errorf("List() with archived = %v, want 2 tasks", list.Result)
+	}
+	if _, err := u.Unarchive(context.Background(), 1); err != nil {
+		t.Errorf("Unarchive() error = %v", err)
+	}
+	if _, err := u.Unarchive(context.Background(), 1); err != domain.ErrTaskNotArchived {
+		t.Errorf("Unarchive() error = %v, want %v", err, domain.ErrTaskNotArchived)
+	}
+}
+
+func Test_taskUsecase_AutoArchive(t *testing.T) {
+	u := &taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+	}
+	status := domain.StatusComplete
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName2"})
+	_, _ = u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "taskName1"})
+
+	got, err := u.AutoArchive(context.Background(), time.Now().Add(time.Second))
+	if err != nil || got != 1 {
+		t.Fatalf("AutoArchive() = %d, %v, want 1, nil", got, err)
+	}
+	task, _ := u.Get(context.Background(), 1)
+	if task.ArchivedAt == nil {
+		t.Errorf("AutoArchive() did not archive the completed task")
+	}
+}
+
+func Test_taskUsecase_AsOf(t *testing.T) {
+	u := &taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+	}
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+	asOf := time.Now()
+	time.Sleep(time.Millisecond)
+	_, _ = u.Delete(context.Background(), 1)
+
+	got, err := u.List(context.Background(), domain.ListTaskRequest{AsOf: asOf})
+	if err != nil || len(got.Result) != 1 {
+		t.Errorf("List() as of = %v, %v, want the deleted task", got, err)
+	}
+	if got, _ := u.List(context.Background(), domain.ListTaskRequest{}); len(got.Result) != 0 {
+		t.Errorf("List() = %v, want no tasks", got)
+	}
+	if _, err := u.GetAsOf(context.Background(), 1, asOf); err != nil {
+		t.Errorf("GetAsOf() error = %v", err)
+	}
+}
+
+func Test_taskUsecase_Events(t *testing.T) {
+	events := eventbus.NewBus()
+	got := make([]domain.DomainEvent, 0)
+	events.Subscribe("record", func(ctx context.Context, event domain.DomainEvent) error {
+		got = append(got, event)
+		return nil
+	})
+	u := &taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+		events:         events,
+	}
+	status := domain.StatusComplete
+	_, _ = u.Create(context.Background(), domain.CreateTaskRequest{Name: "taskName1"})
+	_, _ = u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "taskName1"})
+	_, _ = u.Update(context.Background(), domain.UpdateTaskRequest{ID: 1, Status: &status, Name: "taskName2"})
+	_, _ = u.Archive(context.Background(), 1)
+	_, _ = u.Delete(context.Background(), 1)
+	_, _ = u.Restore(context.Background(), 1)
+	_, _ = u.CreateMany(context.Background(), []domain.Task{{Name: "taskName2"}, {Name: "taskName3"}})
+
+	types := make([]string, 0, len(got))
+	for _, e := range got {
+		switch e := e.(type) {
+		case domain.TaskCreated:
+			if e.Restored {
+				types = append(types, "restored:"+e.Task.Name)
+			} else {
+				types = append(types, "created:"+e.Task.Name)
+			}
+		case domain.TaskUpdated:
+			types = append(types, fmt.Sprintf("updated:%d>%d", e.Before.Status, e.Task.Status))
+		case domain.TaskDeleted:
+			types = append(types, "deleted:"+e.Task.Name)
+		}
+	}
+	want := []string{
+		"created:taskName1",
+		fmt.Sprintf("updated:%d>%d", domain.StatusIncomplete, domain.StatusComplete),
+		fmt.Sprintf("updated:%d>%d", domain.StatusComplete, domain.StatusComplete),
+		"deleted:taskName1",
+		"restored:taskName1",
+		"created:taskName2",
+		"created:taskName3",
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("published %v, want %v", types, want)
+	}
+	archived := got[2].(domain.TaskUpdated)
+	if archived.Before.ArchivedAt != nil || archived.Task.ArchivedAt == nil {
+		t.Errorf("Archive() published %v, want the archiving in Before and Task", archived)
+	}
+}
+
+// withoutCreatedAt clears the creation times the repository takes from the wall clock.
+func withoutCreatedAt(tasks []domain.Task) {
+	for i := range tasks {
+		tasks[i].CreatedAt = time.Time{}
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+// templateUsecase creates the tasks of a template through the task use case, so they get
+// their history entries and events like any other new task.
+type templateUsecase struct {
+	templateRepository domain.TemplateRepository
+	taskUsecase        domain.TaskUseCase
+	now                func() time.Time
+}
+
+func NewTemplateUsecase(templateRepository domain.TemplateRepository, taskUsecase domain.TaskUseCase) *templateUsecase {
+	return &templateUsecase{
+		templateRepository: templateRepository,
+		taskUsecase:        taskUsecase,
+		now:                time.Now,
+	}
+}
+
+func (u *templateUsecase) Create(ctx context.Context, req domain.CreateTemplateRequest) (domain.TemplateResponse, error) {
+	var rtn domain.TemplateResponse
+	got, err := u.templateRepository.Create(ctx, domain.Template{
+		Name:  req.Name,
+		Tasks: req.Tasks,
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *templateUsecase) List(ctx context.Context) (domain.ListTemplateResponse, error) {
+	var rtn domain.ListTemplateResponse
+	got, err := u.templateRepository.List(ctx)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *templateUsecase) Get(ctx context.Context, id int64) (domain.TemplateResponse, error) {
+	var rtn domain.TemplateResponse
+	got, err := u.templateRepository.Get(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *templateUsecase) Delete(ctx context.Context, id int64) error {
+	return u.templateRepository.Delete(ctx, id)
+}
+
+// Instantiate creates the tasks of the template in one call, so a failure leaves no
+// partial bundle behind.
+func (u *templateUsecase) Instantiate(ctx context.Context, id int64, req domain.InstantiateTemplateRequest) (domain.InstantiateTemplateResponse, error) {
+	var rtn domain.InstantiateTemplateResponse
+	template, err := u.templateRepository.Get(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	start := req.Start
+	if start.IsZero() {
+		start = u.now()
+	}
+	start = start.UTC()
+	tasks := make([]domain.Task, 0, len(template.Tasks))
+	for _, t := range template.Tasks {
+		task := domain.Task{
+			Name:        t.Name,
+			Description: t.Description,
+		}
+		if t.DueInDays != nil {
+			due := start.AddDate(0, 0, *t.DueInDays)
+			task.DueAt = &due
+		}
+		if len(t.Tags) > 0 {
+			task.Tags = append([]string{}, t.Tags...)
+		}
+		for i, subtask := range t.Subtasks {
+			task.Checklist = append(task.Checklist, domain.ChecklistItem{ID: int64(i + 1), Text: subtask})
+		}
+		tasks = append(tasks, task)
+	}
+	got, err := u.taskUsecase.CreateMany(ctx, tasks)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
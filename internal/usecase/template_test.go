@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"testing"
+	"time"
+)
+
+func newTestTemplateUsecase(now time.Time) *templateUsecase {
+	u := &templateUsecase{
+		templateRepository: inmemory.NewTemplateRepository(),
+		taskUsecase:        &taskUsecase{taskRepository: inmemory.NewTaskRepository()},
+		now: func() time.Time {
+			return now
+		},
+	}
+	three := 3
+	_, _ = u.templateRepository.Create(context.Background(), domain.Template{
+		Name: "release",
+		Tasks: []domain.TemplateTask{
+			{Name: "freeze", Tags: []string{"release"}},
+			{Name: "ship", DueInDays: &three, Subtasks: []string{"tag", "deploy"}},
+		},
+	})
+	return u
+}
+
+func Test_templateUsecase_Instantiate(t *testing.T) {
+	now := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		id      int64
+		req     domain.InstantiateTemplateRequest
+		wantDue time.Time
+		wantErr error
+	}{
+		{
+			name:    "FromNow",
+			id:      1,
+			req:     domain.InstantiateTemplateRequest{},
+			wantDue: now.AddDate(0, 0, 3),
+			wantErr: nil,
+		},
+		{
+			name:    "FromStart",
+			id:      1,
+			req:     domain.InstantiateTemplateRequest{Start: time.Date(2022, 12, 1, 9, 0, 0, 0, time.FixedZone("UTC+1", 60*60))},
+			wantDue: time.Date(2022, 12, 4, 8, 0, 0, 0, time.UTC),
+			wantErr: nil,
+		},
+		{
+			name:    "TemplateNotFound",
+			id:      5,
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTemplateUsecase(now)
+			got, err := u.Instantiate(context.Background(), tt.id, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("Instantiate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Result) != 2 {
+				t.Fatalf("Instantiate() got %d tasks, want 2", len(got.Result))
+			}
+			freeze, ship := got.Result[0], got.Result[1]
+			if freeze.Name != "freeze" || freeze.DueAt != nil || len(freeze.Tags) != 1 || len(freeze.Checklist) != 0 {
+				t.Errorf("Instantiate() first task = %v", freeze)
+			}
+			if ship.DueAt == nil || !ship.DueAt.Equal(tt.wantDue) {
+				t.Errorf("Instantiate() due = %v, want %v", ship.DueAt, tt.wantDue)
+			}
+			if len(ship.Checklist) != 2 || ship.Checklist[1].ID != 2 || ship.Checklist[1].Text != "deploy" {
+				t.Errorf("Instantiate() checklist = %v", ship.Checklist)
+			}
+			if list, _ := u.taskUsecase.List(context.Background(), domain.ListTaskRequest{}); len(list.Result) != 2 {
+				t.Errorf("List() got %d tasks, want 2", len(list.Result))
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"sort"
+	"time"
+)
+
+type timeTrackingUsecase struct {
+	timeEntryRepository domain.TimeEntryRepository
+	taskRepository      domain.TaskRepository
+	userRepository      domain.UserRepository
+	now                 func() time.Time
+}
+
+func NewTimeTrackingUsecase(timeEntryRepository domain.TimeEntryRepository, taskRepository domain.TaskRepository, userRepository domain.UserRepository) *timeTrackingUsecase {
+	return &timeTrackingUsecase{
+		timeEntryRepository: timeEntryRepository,
+		taskRepository:      taskRepository,
+		userRepository:      userRepository,
+		now:                 time.Now,
+	}
+}
+
+func (u *timeTrackingUsecase) checkTaskAndUser(ctx context.Context, taskID int64, userID int64) error {
+	if _, err := u.taskRepository.Get(ctx, taskID); err != nil {
+		return err
+	}
+	if _, err := u.userRepository.Get(ctx, userID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *timeTrackingUsecase) StartTimer(ctx context.Context, taskID int64, userID int64) (domain.TimeEntryResponse, error) {
+	var rtn domain.TimeEntryResponse
+	if err := u.checkTaskAndUser(ctx, taskID, userID); err != nil {
+		return rtn, err
+	}
+	got, err := u.timeEntryRepository.StartTimer(ctx, domain.TimeEntry{
+		TaskID: taskID,
+		UserID: userID,
+		Start:  u.now().UTC(),
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *timeTrackingUsecase) StopTimer(ctx context.Context, taskID int64, userID int64) (domain.TimeEntryResponse, error) {
+	var rtn domain.TimeEntryResponse
+	got, err := u.timeEntryRepository.StopTimer(ctx, taskID, userID, u.now().UTC())
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *timeTrackingUsecase) AddEntry(ctx context.Context, taskID int64, userID int64, req domain.AddTimeEntryRequest) (domain.TimeEntryResponse, error) {
+	var rtn domain.TimeEntryResponse
+	if !req.End.After(req.Start) {
+		return rtn, domain.ErrInvalidPayload
+	}
+	if err := u.checkTaskAndUser(ctx, taskID, userID); err != nil {
+		return rtn, err
+	}
+	end := req.End.UTC()
+	got, err := u.timeEntryRepository.Create(ctx, domain.TimeEntry{
+		TaskID: taskID,
+		UserID: userID,
+		Start:  req.Start.UTC(),
+		End:    &end,
+		Note:   req.Note,
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+// List returns the entries of a task with their totals, running timers count up to now.
+func (u *timeTrackingUsecase) List(ctx context.Context, taskID int64) (domain.ListTimeEntryResponse, error) {
+	var rtn domain.ListTimeEntryResponse
+	if _, err := u.taskRepository.Get(ctx, taskID); err != nil {
+		return rtn, err
+	}
+	got, err := u.timeEntryRepository.ListByTask(ctx, taskID)
+	if err != nil {
+		return rtn, err
+	}
+	now := u.now()
+	byDay := map[string]int64{}
+	for _, entry := range got {
+		rtn.Total += addByDay(byDay, entry.Start, entryEnd(entry, now))
+	}
+	rtn.Result = got
+	rtn.ByDay = dayTotals(byDay)
+	return rtn, nil
+}
+
+// Report aggregates the time tracked from the start of req.From to the end of req.To per
+// task and per day. Entries crossing the range boundaries only count with their inner part.
+func (u *timeTrackingUsecase) Report(ctx context.Context, req domain.TimeReportRequest) (domain.TimeReportResponse, error) {
+	var rtn domain.TimeReportResponse
+	from := req.From.UTC()
+	to := req.To.UTC().AddDate(0, 0, 1)
+	if !to.After(from) {
+		return rtn, domain.ErrInvalidParameters
+	}
+	got, err := u.timeEntryRepository.ListBetween(ctx, from, to, req.UserID)
+	if err != nil {
+		return rtn, err
+	}
+	now := u.now()
+	byDay := map[string]int64{}
+	byTask := map[int64]map[string]int64{}
+	for _, entry := range got {
+		start, end := entry.Start, entryEnd(entry, now)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if byTask[entry.TaskID] == nil {
+			byTask[entry.TaskID] = map[string]int64{}
+		}
+		addByDay(byTask[entry.TaskID], start, end)
+		rtn.Total += addByDay(byDay, start, end)
+	}
+	rtn.From = from.Format(domain.DateFormat)
+	rtn.To = req.To.UTC().Format(domain.DateFormat)
+	rtn.ByDay = dayTotals(byDay)
+	rtn.Tasks = make([]domain.TaskTimeTotal, 0, len(byTask))
+	for taskID, days := range byTask {
+		total := domain.TaskTimeTotal{TaskID: taskID, ByDay: dayTotals(days)}
+		for _, day := range total.ByDay {
+			total.Total += day.Seconds
+		}
+		rtn.Tasks = append(rtn.Tasks, total)
+	}
+	sort.Slice(rtn.Tasks, func(i, j int) bool {
+		return rtn.Tasks[i].TaskID < rtn.Tasks[j].TaskID
+	})
+	return rtn, nil
+}
+
+func entryEnd(entry domain.TimeEntry, now time.Time) time.Time {
+	if entry.End == nil {
+		return now
+	}
+	return *entry.End
+}
+
+// addByDay splits [start, end) at UTC midnights, adds the seconds to the days they fall
+// on and returns the seconds added in total.
+func addByDay(totals map[string]int64, start time.Time, end time.Time) int64 {
+	start, end = start.UTC(), end.UTC()
+	var added int64
+	for start.Before(end) {
+		y, m, d := start.Date()
+		next := time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		if next.After(end) {
+			next = end
+		}
+		seconds := int64(next.Sub(start) / time.Second)
+		totals[start.Format(domain.DateFormat)] += seconds
+		added += seconds
+		start = next
+	}
+	return added
+}
+
+func dayTotals(totals map[string]int64) []domain.DayTotal {
+	days := make([]domain.DayTotal, 0, len(totals))
+	for date, seconds := range totals {
+		days = append(days, domain.DayTotal{Date: date, Seconds: seconds})
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
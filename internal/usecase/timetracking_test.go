@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestTimeTrackingUsecase(now time.Time) *timeTrackingUsecase {
+	u := &timeTrackingUsecase{
+		timeEntryRepository: inmemory.NewTimeEntryRepository(),
+		taskRepository:      inmemory.NewTaskRepository(),
+		userRepository:      inmemory.NewUserRepository(),
+		now: func() time.Time {
+			return now
+		},
+	}
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName1"})
+	_, _ = u.taskRepository.Create(context.Background(), domain.Task{Name: "taskName2"})
+	_, _ = u.userRepository.Create(context.Background(), "userName1")
+	_, _ = u.userRepository.Create(context.Background(), "userName2")
+	return u
+}
+
+func Test_timeTrackingUsecase_StartTimer(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskID  int64
+		userID  int64
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			taskID:  2,
+			userID:  2,
+			wantErr: nil,
+		},
+		{
+			name:    "AlreadyRunning",
+			taskID:  2,
+			userID:  1,
+			wantErr: domain.ErrTimerRunning,
+		},
+		{
+			name:    "TaskNotFound",
+			taskID:  5,
+			userID:  2,
+			wantErr: domain.ErrDataNotFound,
+		},
+		{
+			name:    "UserNotFound",
+			taskID:  1,
+			userID:  5,
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+	now := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTimeTrackingUsecase(now)
+			_, _ = u.StartTimer(context.Background(), 1, 1)
+			got, err := u.StartTimer(context.Background(), tt.taskID, tt.userID)
+			if err != tt.wantErr {
+				t.Errorf("StartTimer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (!got.Result.Start.Equal(now) || got.Result.End != nil) {
+				t.Errorf("StartTimer() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_timeTrackingUsecase_AddEntry(t *testing.T) {
+	start := time.Date(2022, 11, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     domain.AddTimeEntryRequest
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			req:     domain.AddTimeEntryRequest{Start: start, End: start.Add(time.Hour), Note: "review"},
+			wantErr: nil,
+		},
+		{
+			name:    "EndBeforeStart",
+			req:     domain.AddTimeEntryRequest{Start: start, End: start.Add(-time.Hour)},
+			wantErr: domain.ErrInvalidPayload,
+		},
+		{
+			name:    "Empty",
+			req:     domain.AddTimeEntryRequest{Start: start, End: start},
+			wantErr: domain.ErrInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTimeTrackingUsecase(start)
+			got, err := u.AddEntry(context.Background(), 1, 1, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("AddEntry() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.Result.End == nil || !got.Result.End.Equal(tt.req.End) || got.Result.Note != tt.req.Note) {
+				t.Errorf("AddEntry() got = %v", got)
+			}
+		})
+	}
+}
+
+func Test_timeTrackingUsecase_List(t *testing.T) {
+	now := time.Date(2022, 11, 2, 1, 0, 0, 0, time.UTC)
+	u := newTestTimeTrackingUsecase(now)
+	start := time.Date(2022, 11, 1, 23, 0, 0, 0, time.UTC)
+	_, _ = u.AddEntry(context.Background(), 1, 1, domain.AddTimeEntryRequest{Start: start, End: start.Add(90 * time.Minute)})
+	_, _ = u.timeEntryRepository.StartTimer(context.Background(), domain.TimeEntry{TaskID: 1, UserID: 2, Start: now.Add(-30 * time.Minute)})
+
+	got, err := u.List(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got.Result) != 2 {
+		t.Errorf("List() got %d entries, want 2", len(got.Result))
+	}
+	if got.Total != 120*60 {
+		t.Errorf("List() total = %d, want %d", got.Total, 120*60)
+	}
+	wantByDay := []domain.DayTotal{
+		{Date: "2022-11-01", Seconds: 60 * 60},
+		{Date: "2022-11-02", Seconds: 60 * 60},
+	}
+	if !reflect.DeepEqual(got.ByDay, wantByDay) {
+		t.Errorf("List() byDay = %v, want %v", got.ByDay, wantByDay)
+	}
+
+	if _, err := u.List(context.Background(), 5); err != domain.ErrDataNotFound {
+		t.Errorf("List() error = %v, wantErr %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_timeTrackingUsecase_Report(t *testing.T) {
+	day := func(d int, h int) time.Time {
+		return time.Date(2022, 11, d, h, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name    string
+		req     domain.TimeReportRequest
+		want    domain.TimeReportResponse
+		wantErr error
+	}{
+		{
+			name: "AllUsers",
+			req:  domain.TimeReportRequest{From: day(2, 0), To: day(3, 0)},
+			want: domain.TimeReportResponse{
+				From:  "2022-11-02",
+				To:    "2022-11-03",
+				Total: 6 * 3600,
+				Tasks: []domain.TaskTimeTotal{
+					{TaskID: 1, Total: 4 * 3600, ByDay: []domain.DayTotal{{Date: "2022-11-02", Seconds: 2 * 3600}, {Date: "2022-11-03", Seconds: 2 * 3600}}},
+					{TaskID: 2, Total: 2 * 3600, ByDay: []domain.DayTotal{{Date: "2022-11-03", Seconds: 2 * 3600}}},
+				},
+				ByDay: []domain.DayTotal{{Date: "2022-11-02", Seconds: 2 * 3600}, {Date: "2022-11-03", Seconds: 4 * 3600}},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "SingleUser",
+			req:  domain.TimeReportRequest{From: day(3, 0), To: day(3, 0), UserID: 2},
+			want: domain.TimeReportResponse{
+				From:  "2022-11-03",
+				To:    "2022-11-03",
+				Total: 2 * 3600,
+				Tasks: []domain.TaskTimeTotal{
+					{TaskID: 2, Total: 2 * 3600, ByDay: []domain.DayTotal{{Date: "2022-11-03", Seconds: 2 * 3600}}},
+				},
+				ByDay: []domain.DayTotal{{Date: "2022-11-03", Seconds: 2 * 3600}},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "ToBeforeFrom",
+			req:     domain.TimeReportRequest{From: day(3, 0), To: day(2, 0)},
+			wantErr: domain.ErrInvalidParameters,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTimeTrackingUsecase(day(4, 12))
+			// crosses the start of the range, only the 2 hours inside count
+			_, _ = u.AddEntry(context.Background(), 1, 1, domain.AddTimeEntryRequest{Start: day(1, 22), End: day(2, 2)})
+			_, _ = u.AddEntry(context.Background(), 1, 1, domain.AddTimeEntryRequest{Start: day(3, 8), End: day(3, 10)})
+			// still running, counted up to the end of the range
+			_, _ = u.timeEntryRepository.StartTimer(context.Background(), domain.TimeEntry{TaskID: 2, UserID: 2, Start: day(3, 22)})
+			got, err := u.Report(context.Background(), tt.req)
+			if err != tt.wantErr {
+				t.Errorf("Report() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Report() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
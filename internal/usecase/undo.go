@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+// undoTaskUsecase decorates a domain.TaskUseCase, it hands out an undo token for every
+// update and delete and reverses the operation when the token is redeemed in time.
+type undoTaskUsecase struct {
+	domain.TaskUseCase
+	undoRepository domain.UndoRepository
+	window         time.Duration
+	now            func() time.Time
+}
+
+func NewUndoTaskUsecase(taskUsecase domain.TaskUseCase, undoRepository domain.UndoRepository, window time.Duration) *undoTaskUsecase {
+	return &undoTaskUsecase{
+		TaskUseCase:    taskUsecase,
+		undoRepository: undoRepository,
+		window:         window,
+		now:            time.Now,
+	}
+}
+
+func (u *undoTaskUsecase) Update(ctx context.Context, req domain.UpdateTaskRequest) (domain.UpdateTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Update(ctx, req)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.UndoToken, err = u.issue(ctx, domain.HistoryActionUpdate, rtn.Before, rtn.Result)
+	return rtn, err
+}
+
+func (u *undoTaskUsecase) Delete(ctx context.Context, id int64) (domain.DeleteTaskResponse, error) {
+	rtn, err := u.TaskUseCase.Delete(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.UndoToken, err = u.issue(ctx, domain.HistoryActionDelete, rtn.Before, rtn.Before)
+	return rtn, err
+}
+
+func (u *undoTaskUsecase) issue(ctx context.Context, action domain.HistoryAction, before domain.Task, after domain.Task) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	entry := domain.UndoEntry{
+		Token:     hex.EncodeToString(b),
+		TaskID:    before.ID,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		ExpiresAt: u.now().Add(u.window),
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		entry.ActorID = &actorID
+	}
+	if err := u.undoRepository.Save(ctx, entry); err != nil {
+		return "", err
+	}
+	return entry.Token, nil
+}
+
+// Undo reverses the operation of the token. Tokens issued to a user can only be redeemed by
+// that user, and an update is only reverted while the task still looks as it did after it;
+// the task use case compares and reverts under the lock of the task.
+func (u *undoTaskUsecase) Undo(ctx context.Context, token string) (domain.UndoResponse, error) {
+	var rtn domain.UndoResponse
+	entry, err := u.undoRepository.Get(ctx, token)
+	if err != nil {
+		return rtn, err
+	}
+	if entry.ActorID != nil {
+		if actorID, ok := domain.ActorFromContext(ctx); !ok || actorID != *entry.ActorID {
+			return rtn, domain.ErrUndoNotFound
+		}
+	}
+	if u.now().After(entry.ExpiresAt) {
+		_ = u.undoRepository.Delete(ctx, token)
+		return rtn, domain.ErrUndoExpired
+	}
+
+	var got domain.Task
+	switch entry.Action {
+	case domain.HistoryActionUpdate:
+		updated, err := u.TaskUseCase.Revert(ctx, entry.After, entry.Before)
+		if err == domain.ErrDataNotFound {
+			return rtn, domain.ErrUndoConflict
+		}
+		if err != nil {
+			return rtn, err
+		}
+		got = updated.Result
+	case domain.HistoryActionDelete:
+		// A task in the trash cannot be changed, it can only be restored or purged.
+		restored, err := u.TaskUseCase.Restore(ctx, entry.TaskID)
+		if err == domain.ErrTaskNotDeleted || err == domain.ErrDataNotFound {
+			return rtn, domain.ErrUndoConflict
+		}
+		if err != nil {
+			return rtn, err
+		}
+		got = restored.Result
+	default:
+		return rtn, domain.ErrUndoNotFound
+	}
+	if err := u.undoRepository.Delete(ctx, token); err != nil && err != domain.ErrUndoNotFound {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"testing"
+	"time"
+)
+
+func newTestUndoUsecase() *undoTaskUsecase {
+	u := NewUndoTaskUsecase(&taskUsecase{
+		taskRepository: inmemory.NewTaskRepository(),
+	}, inmemory.NewUndoRepository(), time.Minute)
+	_, _ = u.Create(context.Background(), domain.CreateTaskRequest{Name: "taskName1", Description: "before"})
+	return u
+}
+
+func Test_undoTaskUsecase_Undo(t *testing.T) {
+	complete := domain.StatusComplete
+	after := "after"
+	update := func(u *undoTaskUsecase, ctx context.Context) string {
+		rtn, err := u.Update(ctx, domain.UpdateTaskRequest{ID: 1, Status: &complete, Name: "taskName1", Description: &after})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		return rtn.UndoToken
+	}
+	del := func(u *undoTaskUsecase, ctx context.Context) string {
+		rtn, err := u.Delete(ctx, 1)
+		if err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		return rtn.UndoToken
+	}
+	tests := []struct {
+		name       string
+		operate    func(u *undoTaskUsecase, ctx context.Context) string
+		afterwards func(u *undoTaskUsecase)
+		undoCtx    context.Context
+		wantErr    error
+	}{
+		{
+			name:    "Update",
+			operate: update,
+			wantErr: nil,
+		},
+		{
+			name:    "Delete",
+			operate: del,
+			wantErr: nil,
+		},
+		{
+			name:    "ChangedSince",
+			operate: update,
+			afterwards: func(u *undoTaskUsecase) {
+				_, _ = u.Archive(context.Background(), 1)
+			},
+			wantErr: domain.ErrUndoConflict,
+		},
+		{
+			name:    "RestoredSince",
+			operate: del,
+			afterwards: func(u *undoTaskUsecase) {
+				_, _ = u.Restore(context.Background(), 1)
+			},
+			wantErr: domain.ErrUndoConflict,
+		},
+		{
+			name:    "Expired",
+			operate: update,
+			afterwards: func(u *undoTaskUsecase) {
+				u.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+			},
+			wantErr: domain.ErrUndoExpired,
+		},
+		{
+			name: "OtherActor",
+			operate: func(u *undoTaskUsecase, ctx context.Context) string {
+				return update(u, domain.ContextWithActor(ctx, 1))
+			},
+			undoCtx: domain.ContextWithActor(context.Background(), 2),
+			wantErr: domain.ErrUndoNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUndoUsecase()
+			token := tt.operate(u, context.Background())
+			if token == "" {
+				t.Fatal("operation returned no undo token")
+			}
+			if tt.afterwards != nil {
+				tt.afterwards(u)
+			}
+			ctx := tt.undoCtx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			got, err := u.Undo(ctx, token)
+			if err != tt.wantErr {
+				t.Fatalf("Undo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Result.Status != domain.StatusIncomplete || got.Result.Description != "before" || got.Result.DeletedAt != nil {
+				t.Errorf("Undo() got = %v, want the original task", got.Result)
+			}
+			if _, err := u.Undo(ctx, token); err != domain.ErrUndoNotFound {
+				t.Errorf("Undo() twice error = %v, want %v", err, domain.ErrUndoNotFound)
+			}
+		})
+	}
+}
+
+func Test_undoTaskUsecase_UndoCompletedAt(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUndoUsecase()
+	completed, _ := u.Update(ctx, domain.UpdateTaskRequest{ID: 1, Status: &domain.StatusComplete, Name: "taskName1"})
+	reopened, err := u.Update(ctx, domain.UpdateTaskRequest{ID: 1, Status: &domain.StatusIncomplete, Name: "taskName1"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := u.Undo(ctx, reopened.UndoToken)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got.Result.CompletedAt == nil || !got.Result.CompletedAt.Equal(*completed.Result.CompletedAt) {
+		t.Errorf("Undo() completedAt = %v, want %v", got.Result.CompletedAt, completed.Result.CompletedAt)
+	}
+}
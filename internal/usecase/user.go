@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+)
+
+type userUsecase struct {
+	userRepository domain.UserRepository
+}
+
+func NewUserUsecase(userRepository domain.UserRepository) *userUsecase {
+	return &userUsecase{
+		userRepository: userRepository,
+	}
+}
+
+func (u *userUsecase) List(ctx context.Context) (domain.ListUserResponse, error) {
+	var rtn domain.ListUserResponse
+	got, err := u.userRepository.List(ctx)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *userUsecase) Create(ctx context.Context, req domain.CreateUserRequest) (domain.CreateUserResponse, error) {
+	var rtn domain.CreateUserResponse
+	got, err := u.userRepository.Create(ctx, req.Name)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *userUsecase) Get(ctx context.Context, id int64) (domain.User, error) {
+	got, err := u.userRepository.Get(ctx, id)
+	if err != nil {
+		return got, err
+	}
+	return got, nil
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"testing"
+)
+
+func Test_userUsecase_Create(t *testing.T) {
+	type fields struct {
+		userRepository domain.UserRepository
+	}
+	type args struct {
+		ctx context.Context
+		req domain.CreateUserRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    domain.CreateUserResponse
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			fields: fields{
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: domain.CreateUserRequest{
+					Name: "userName",
+				},
+			},
+			want: domain.CreateUserResponse{
+				Result: domain.User{
+					ID:   1,
+					Name: "userName",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &userUsecase{
+				userRepository: tt.fields.userRepository,
+			}
+			got, err := u.Create(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userUsecase_List(t *testing.T) {
+	type fields struct {
+		userRepository domain.UserRepository
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(repo domain.UserRepository)
+		fields     fields
+		args       args
+		want       domain.ListUserResponse
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(repo domain.UserRepository) {
+				_, _ = repo.Create(context.Background(), "userName1")
+				_, _ = repo.Create(context.Background(), "userName2")
+			},
+			fields: fields{
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: domain.ListUserResponse{
+				Result: []domain.User{
+					{ID: 1, Name: "userName1"},
+					{ID: 2, Name: "userName2"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OKEmpty",
+			buildStubs: func(repo domain.UserRepository) {
+			},
+			fields: fields{
+				userRepository: inmemory.NewUserRepository(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: domain.ListUserResponse{
+				Result: []domain.User{},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &userUsecase{
+				userRepository: tt.fields.userRepository,
+			}
+			tt.buildStubs(u.userRepository)
+			got, err := u.List(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userUsecase_Get(t *testing.T) {
+	type fields struct {
+		userRepository domain.UserRepository
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name       string
+		buildStubs func(repo domain.UserRepository)
+		fields     fields
+		args       args
+		want       domain.User
+		wantErr    bool
+	}{
+		{
+			name: "OK",
+			buildStubs: func(repo domain.UserRepository) {
+				_, _ = repo.Create(context.Background(), "userName1")
+			},
+			fields: fields{userRepository: inmemory.NewUserRepository()},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want:    domain.User{ID: 1, Name: "userName1"},
+			wantErr: false,
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(repo domain.UserRepository) {
+			},
+			fields: fields{userRepository: inmemory.NewUserRepository()},
+			args: args{
+				ctx: context.Background(),
+				id:  5,
+			},
+			want:    domain.User{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &userUsecase{
+				userRepository: tt.fields.userRepository,
+			}
+			tt.buildStubs(u.userRepository)
+			got, err := u.Get(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
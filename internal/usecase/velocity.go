@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"time"
+)
+
+type velocityUsecase struct {
+	taskRepository domain.TaskRepository
+	now            func() time.Time
+}
+
+func NewVelocityUsecase(taskRepository domain.TaskRepository) *velocityUsecase {
+	return &velocityUsecase{
+		taskRepository: taskRepository,
+		now:            time.Now,
+	}
+}
+
+// Velocity buckets the completed tasks, archived ones included, by the week of their
+// completion timestamp. Weeks before the reported ones are read as well so the rolling
+// velocity of the oldest reported weeks covers a full window.
+func (u *velocityUsecase) Velocity(ctx context.Context, req domain.VelocityRequest) (domain.VelocityResponse, error) {
+	var rtn domain.VelocityResponse
+	if req.Weeks == 0 {
+		req.Weeks = domain.DefaultVelocityWeeks
+	}
+	if req.Window == 0 {
+		req.Window = domain.DefaultVelocityWindow
+	}
+	if req.Unit == "" {
+		req.Unit = domain.EstimateUnitPoints
+	}
+	got, err := u.taskRepository.List(ctx, domain.TaskFilter{IncludeArchived: true})
+	if err != nil {
+		return rtn, err
+	}
+
+	// weeks[0] is the oldest week read, weeks[len(weeks)-1] the current one, the last
+	// finished week is always read even if only the current one is reported.
+	reported := req.Weeks
+	if reported < 2 {
+		reported = 2
+	}
+	current := weekStart(u.now())
+	weeks := make([]domain.WeekVelocity, reported+req.Window-1)
+	first := current.AddDate(0, 0, -7*(len(weeks)-1))
+	for i := range weeks {
+		weeks[i].WeekStart = first.AddDate(0, 0, 7*i).Format(domain.DateFormat)
+	}
+	for _, task := range got {
+		if task.Status != domain.StatusComplete || task.CompletedAt == nil {
+			continue
+		}
+		i := int(weekStart(*task.CompletedAt).Sub(first) / (7 * 24 * time.Hour))
+		if i < 0 || i >= len(weeks) {
+			continue
+		}
+		weeks[i].Tasks++
+		if task.Estimate == nil || task.Estimate.Unit != req.Unit {
+			weeks[i].Unestimated++
+			continue
+		}
+		weeks[i].Completed += task.Estimate.Value
+	}
+	for i := req.Window - 1; i < len(weeks); i++ {
+		var sum float64
+		for _, week := range weeks[i-req.Window+1 : i+1] {
+			sum += week.Completed
+		}
+		weeks[i].Rolling = sum / float64(req.Window)
+	}
+
+	rtn.Unit = req.Unit
+	rtn.Window = req.Window
+	rtn.Velocity = weeks[len(weeks)-2].Rolling
+	rtn.Weeks = weeks[len(weeks)-req.Weeks:]
+	return rtn, nil
+}
+
+// weekStart returns the Monday, 00:00 UTC, of the week t falls in.
+func weekStart(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"oa-gogolook/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// listedTasks is a task repository whose List returns fixed tasks.
+type listedTasks struct {
+	domain.TaskRepository
+	tasks []domain.Task
+}
+
+func (r listedTasks) List(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	return r.tasks, nil
+}
+
+func Test_weekStart(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "Monday",
+			t:    time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Sunday",
+			t:    time.Date(2022, 11, 6, 23, 59, 0, 0, time.UTC),
+			want: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "OtherZone",
+			t:    time.Date(2022, 11, 7, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			want: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weekStart(tt.t); !got.Equal(tt.want) {
+				t.Errorf("weekStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_velocityUsecase_Velocity(t *testing.T) {
+	// Wednesday of the week starting 2022-11-14.
+	now := time.Date(2022, 11, 16, 12, 0, 0, 0, time.UTC)
+	completed := func(days int, estimate *domain.Estimate) domain.Task {
+		at := now.AddDate(0, 0, -days)
+		return domain.Task{Status: domain.StatusComplete, CompletedAt: &at, Estimate: estimate}
+	}
+	points := func(value float64) *domain.Estimate {
+		return &domain.Estimate{Value: value, Unit: domain.EstimateUnitPoints}
+	}
+	tasks := []domain.Task{
+		completed(0, points(5)),
+		completed(7, points(3)),
+		completed(8, points(2)),
+		completed(8, &domain.Estimate{Value: 4, Unit: domain.EstimateUnitHours}),
+		completed(14, points(8)),
+		completed(21, nil),
+		completed(28, points(1)),
+		{Estimate: points(13)},
+	}
+	tests := []struct {
+		name string
+		req  domain.VelocityRequest
+		want domain.VelocityResponse
+	}{
+		{
+			name: "Points",
+			req:  domain.VelocityRequest{Weeks: 3, Window: 2},
+			want: domain.VelocityResponse{
+				Unit:     domain.EstimateUnitPoints,
+				Window:   2,
+				Velocity: 6.5,
+				Weeks: []domain.WeekVelocity{
+					{WeekStart: "2022-10-31", Completed: 8, Tasks: 1, Rolling: 4},
+					{WeekStart: "2022-11-07", Completed: 5, Tasks: 3, Unestimated: 1, Rolling: 6.5},
+					{WeekStart: "2022-11-14", Completed: 5, Tasks: 1, Rolling: 5},
+				},
+			},
+		},
+		{
+			name: "Hours",
+			req:  domain.VelocityRequest{Weeks: 1, Window: 1, Unit: domain.EstimateUnitHours},
+			want: domain.VelocityResponse{
+				Unit:     domain.EstimateUnitHours,
+				Window:   1,
+				Velocity: 4,
+				Weeks: []domain.WeekVelocity{
+					{WeekStart: "2022-11-14", Tasks: 1, Unestimated: 1},
+				},
+			},
+		},
+		{
+			name: "Defaults",
+			req:  domain.VelocityRequest{},
+			want: domain.VelocityResponse{
+				Unit:     domain.EstimateUnitPoints,
+				Window:   domain.DefaultVelocityWindow,
+				Velocity: 13.0 / 3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &velocityUsecase{
+				taskRepository: listedTasks{tasks: tasks},
+				now: func() time.Time {
+					return now
+				},
+			}
+			got, err := u.Velocity(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Velocity() error = %v", err)
+			}
+			if tt.want.Weeks == nil {
+				if len(got.Weeks) != domain.DefaultVelocityWeeks || got.Velocity != tt.want.Velocity {
+					t.Errorf("Velocity() got = %v", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Velocity() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
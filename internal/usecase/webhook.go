@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"oa-gogolook/internal/domain"
+	"sync"
+	"time"
+)
+
+type webhookUsecase struct {
+	webhookRepository  domain.WebhookRepository
+	deliveryRepository domain.WebhookDeliveryRepository
+	sender             domain.WebhookSender
+	maxAttempts        int
+	backoff            time.Duration
+	now                func() time.Time
+}
+
+// NewWebhookUsecase retries a failed delivery after backoff, doubling the wait after every
+// further failure up to domain.MaxWebhookBackoff, and gives up after maxAttempts.
+func NewWebhookUsecase(webhookRepository domain.WebhookRepository, deliveryRepository domain.WebhookDeliveryRepository, sender domain.WebhookSender, maxAttempts int, backoff time.Duration) *webhookUsecase {
+	return &webhookUsecase{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		sender:             sender,
+		maxAttempts:        maxAttempts,
+		backoff:            backoff,
+		now:                time.Now,
+	}
+}
+
+func (u *webhookUsecase) Create(ctx context.Context, req domain.CreateWebhookRequest) (domain.WebhookResponse, error) {
+	var rtn domain.WebhookResponse
+	got, err := u.webhookRepository.Create(ctx, domain.WebhookSubscription{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+	})
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *webhookUsecase) List(ctx context.Context) (domain.ListWebhookResponse, error) {
+	var rtn domain.ListWebhookResponse
+	got, err := u.webhookRepository.List(ctx)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *webhookUsecase) Get(ctx context.Context, id int64) (domain.WebhookResponse, error) {
+	var rtn domain.WebhookResponse
+	got, err := u.webhookRepository.Get(ctx, id)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *webhookUsecase) Delete(ctx context.Context, id int64) error {
+	return u.webhookRepository.Delete(ctx, id)
+}
+
+func (u *webhookUsecase) Publish(ctx context.Context, event domain.WebhookEvent, task domain.Task) error {
+	subscriptions, err := u.webhookRepository.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := u.now()
+	payload, err := json.Marshal(domain.WebhookPayload{
+		Event:      event,
+		OccurredAt: now,
+		Task:       task,
+	})
+	if err != nil {
+		return err
+	}
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(event) {
+			continue
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			Event:          event,
+			Payload:        payload,
+			NextAttemptAt:  now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	_, err = u.deliveryRepository.Enqueue(ctx, deliveries)
+	return err
+}
+
+// deliveryWorkers bounds the subscriptions DeliverDue sends to at the same time.
+const deliveryWorkers = 8
+
+// DeliverDue sends the due deliveries, those of one subscription one after the other and
+// up to deliveryWorkers subscriptions at the same time. Deliveries of a deleted
+// subscription go straight to the dead-letter list.
+func (u *webhookUsecase) DeliverDue(ctx context.Context) (int, error) {
+	due, err := u.deliveryRepository.Due(ctx, u.now())
+	if err != nil {
+		return 0, err
+	}
+	bySubscription := make(map[int64][]domain.WebhookDelivery)
+	subscriptionIDs := make([]int64, 0)
+	for _, delivery := range due {
+		if _, ok := bySubscription[delivery.SubscriptionID]; !ok {
+			subscriptionIDs = append(subscriptionIDs, delivery.SubscriptionID)
+		}
+		bySubscription[delivery.SubscriptionID] = append(bySubscription[delivery.SubscriptionID], delivery)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	delivered, failed := 0, 0
+	var lastErr, stopErr error
+	jobs := make(chan int64)
+	workers := deliveryWorkers
+	if len(subscriptionIDs) < workers {
+		workers = len(subscriptionIDs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				result := u.deliver(ctx, id, bySubscription[id])
+				mu.Lock()
+				delivered += result.delivered
+				failed += result.failed
+				if result.lastErr != nil {
+					lastErr = result.lastErr
+				}
+				if result.err != nil {
+					stopErr = result.err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range subscriptionIDs {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+
+	if stopErr != nil {
+		return delivered, stopErr
+	}
+	if failed > 0 {
+		return delivered, fmt.Errorf("%d webhook deliveries failed, last error: %w", failed, lastErr)
+	}
+	return delivered, nil
+}
+
+// deliveryResult is the outcome of sending the due deliveries of one subscription. lastErr
+// is the last failed send and err the error that stopped the sending.
+type deliveryResult struct {
+	delivered int
+	failed    int
+	lastErr   error
+	err       error
+}
+
+// deliver sends the due deliveries of the subscription in order.
+func (u *webhookUsecase) deliver(ctx context.Context, subscriptionID int64, deliveries []domain.WebhookDelivery) deliveryResult {
+	var rtn deliveryResult
+	subscription, err := u.webhookRepository.Get(ctx, subscriptionID)
+	if err != nil && err != domain.ErrDataNotFound {
+		rtn.err = err
+		return rtn
+	}
+	for _, delivery := range deliveries {
+		if err == domain.ErrDataNotFound {
+			dead := u.now()
+			delivery.DeadAt = &dead
+			delivery.LastError = "subscription deleted"
+			if _, err := u.deliveryRepository.Update(ctx, delivery); err != nil {
+				rtn.err = err
+				return rtn
+			}
+			continue
+		}
+
+		delivery.Attempts++
+		sendErr := u.sender.Send(ctx, subscription, delivery)
+		now := u.now()
+		if sendErr == nil {
+			delivery.DeliveredAt = &now
+			delivery.LastError = ""
+			rtn.delivered++
+		} else {
+			rtn.failed++
+			rtn.lastErr = sendErr
+			delivery.LastError = sendErr.Error()
+			if delivery.Attempts >= u.maxAttempts {
+				delivery.DeadAt = &now
+			} else {
+				delivery.NextAttemptAt = now.Add(u.backoffAfter(delivery.Attempts))
+			}
+		}
+		if _, err := u.deliveryRepository.Update(ctx, delivery); err != nil {
+			rtn.err = err
+			return rtn
+		}
+	}
+	return rtn
+}
+
+// backoffAfter returns the wait after the given number of failed attempts.
+func (u *webhookUsecase) backoffAfter(attempts int) time.Duration {
+	wait := u.backoff
+	for i := 1; i < attempts && wait < domain.MaxWebhookBackoff; i++ {
+		wait *= 2
+	}
+	if wait > domain.MaxWebhookBackoff {
+		wait = domain.MaxWebhookBackoff
+	}
+	return wait
+}
+
+func (u *webhookUsecase) ListDeadLetters(ctx context.Context) (domain.ListWebhookDeliveryResponse, error) {
+	var rtn domain.ListWebhookDeliveryResponse
+	got, err := u.deliveryRepository.ListDead(ctx)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+func (u *webhookUsecase) Retry(ctx context.Context, deliveryID int64) (domain.WebhookDeliveryResponse, error) {
+	var rtn domain.WebhookDeliveryResponse
+	delivery, err := u.deliveryRepository.Get(ctx, deliveryID)
+	if err != nil {
+		return rtn, err
+	}
+	if delivery.DeadAt == nil {
+		return rtn, domain.ErrDeliveryNotDead
+	}
+	delivery.DeadAt = nil
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.NextAttemptAt = u.now()
+	got, err := u.deliveryRepository.Update(ctx, delivery)
+	if err != nil {
+		return rtn, err
+	}
+	rtn.Result = got
+	return rtn, nil
+}
+
+// HandleTaskEvent is the domain.EventHandler queueing the webhook events of the created,
+// completed and deleted tasks. Restoring a task from the trash is not announced.
+func (u *webhookUsecase) HandleTaskEvent(ctx context.Context, event domain.DomainEvent) error {
+	switch e := event.(type) {
+	case domain.TaskCreated:
+		if e.Restored {
+			return nil
+		}
+		return u.Publish(ctx, domain.WebhookEventTaskCreated, e.Task)
+	case domain.TaskUpdated:
+		if e.Before.Status == domain.StatusComplete || e.Task.Status != domain.StatusComplete {
+			return nil
+		}
+		return u.Publish(ctx, domain.WebhookEventTaskCompleted, e.Task)
+	case domain.TaskDeleted:
+		return u.Publish(ctx, domain.WebhookEventTaskDeleted, e.Task)
+	}
+	return nil
+}
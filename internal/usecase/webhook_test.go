@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"oa-gogolook/internal/domain"
+	"oa-gogolook/internal/eventbus"
+	"oa-gogolook/internal/repository/inmemory"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSender keeps the deliveries it gets and fails while err is set.
+type recordingSender struct {
+	mu  sync.Mutex
+	got []domain.WebhookDelivery
+	err error
+}
+
+func (s *recordingSender) Send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.got = append(s.got, delivery)
+	return nil
+}
+
+func newTestWebhookUsecase(clock *fakeClock, sender domain.WebhookSender) *webhookUsecase {
+	u := NewWebhookUsecase(inmemory.NewWebhookRepository(), inmemory.NewWebhookDeliveryRepository(), sender, 3, time.Minute)
+	u.now = clock.Now
+	return u
+}
+
+func Test_webhookUsecase_HandleTaskEvent(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	sender := &recordingSender{}
+	webhooks := newTestWebhookUsecase(clock, sender)
+	events := eventbus.NewBus()
+	events.Subscribe("webhooks", webhooks.HandleTaskEvent)
+	u := &taskUsecase{taskRepository: inmemory.NewTaskRepository(), events: events}
+	ctx := context.Background()
+	_, _ = webhooks.Create(ctx, domain.CreateWebhookRequest{
+		URL:    "http://example.com/all",
+		Events: []domain.WebhookEvent{domain.WebhookEventTaskCreated, domain.WebhookEventTaskCompleted, domain.WebhookEventTaskDeleted},
+		Secret: "0123456789abcdef",
+	})
+	_, _ = webhooks.Create(ctx, domain.CreateWebhookRequest{
+		URL:    "http://example.com/deleted",
+		Events: []domain.WebhookEvent{domain.WebhookEventTaskDeleted},
+		Secret: "0123456789abcdef",
+	})
+	complete := domain.StatusComplete
+
+	_, _ = u.Create(ctx, domain.CreateTaskRequest{Name: "taskName1"})
+	_, _ = u.Update(ctx, domain.UpdateTaskRequest{ID: 1, Name: "taskName1", Status: &complete})
+	_, _ = u.Update(ctx, domain.UpdateTaskRequest{ID: 1, Name: "taskName1", Status: &complete})
+	_, _ = u.Delete(ctx, 1)
+	_, _ = u.Restore(ctx, 1)
+	_, _ = u.Delete(ctx, 1)
+	if _, err := u.Delete(ctx, 1); err != domain.ErrDataNotFound {
+		t.Fatalf("Delete() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+
+	if n, err := webhooks.DeliverDue(ctx); err != nil || n != 6 {
+		t.Fatalf("DeliverDue() = %v, %v, want 6, nil", n, err)
+	}
+	// The subscriptions are sent to at the same time, each one gets its events in order.
+	want := map[int64][]domain.WebhookEvent{
+		1: {domain.WebhookEventTaskCreated, domain.WebhookEventTaskCompleted, domain.WebhookEventTaskDeleted, domain.WebhookEventTaskDeleted},
+		2: {domain.WebhookEventTaskDeleted, domain.WebhookEventTaskDeleted},
+	}
+	got := map[int64][]domain.WebhookEvent{}
+	for i, delivery := range sender.got {
+		var payload domain.WebhookPayload
+		if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+			t.Fatalf("payload %d: %v", i, err)
+		}
+		if payload.Event != delivery.Event || payload.Task.ID != 1 {
+			t.Errorf("delivery %d = %+v %+v, want the payload of task 1 for its event", i, delivery, payload)
+		}
+		got[delivery.SubscriptionID] = append(got[delivery.SubscriptionID], delivery.Event)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deliveries = %v, want %v", got, want)
+	}
+}
+
+func Test_webhookUsecase_DeliverDue(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	sender := &recordingSender{err: errors.New("connection refused")}
+	u := newTestWebhookUsecase(clock, sender)
+	ctx := context.Background()
+	_, _ = u.Create(ctx, domain.CreateWebhookRequest{
+		URL:    "http://example.com/hook",
+		Events: []domain.WebhookEvent{domain.WebhookEventTaskCreated},
+		Secret: "0123456789abcdef",
+	})
+	_ = u.Publish(ctx, domain.WebhookEventTaskCreated, domain.Task{ID: 1, Name: "taskName1"})
+
+	// The first retry comes after the backoff, the second one after twice the backoff.
+	for _, wait := range []time.Duration{0, time.Minute, 2 * time.Minute} {
+		if wait > 0 {
+			clock.Advance(wait - time.Second)
+			if n, err := u.DeliverDue(ctx); n != 0 || err != nil {
+				t.Fatalf("DeliverDue() before the backoff = %v, %v, want 0, nil", n, err)
+			}
+			clock.Advance(time.Second)
+		}
+		if _, err := u.DeliverDue(ctx); err == nil {
+			t.Fatalf("DeliverDue() error = nil, want the sender error")
+		}
+	}
+
+	dead, _ := u.ListDeadLetters(ctx)
+	if len(dead.Result) != 1 || dead.Result[0].Attempts != 3 || dead.Result[0].LastError != "connection refused" {
+		t.Fatalf("ListDeadLetters() = %+v, want the delivery after 3 attempts", dead.Result)
+	}
+	clock.Advance(time.Hour)
+	if n, err := u.DeliverDue(ctx); n != 0 || err != nil {
+		t.Errorf("DeliverDue() of a dead delivery = %v, %v, want 0, nil", n, err)
+	}
+
+	sender.err = nil
+	if _, err := u.Retry(ctx, 1); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if n, err := u.DeliverDue(ctx); n != 1 || err != nil {
+		t.Fatalf("DeliverDue() after Retry() = %v, %v, want 1, nil", n, err)
+	}
+	if _, err := u.Retry(ctx, 1); err != domain.ErrDeliveryNotDead {
+		t.Errorf("Retry() of a delivered delivery error = %v, want %v", err, domain.ErrDeliveryNotDead)
+	}
+	if _, err := u.Retry(ctx, 2); err != domain.ErrDataNotFound {
+		t.Errorf("Retry() of an unknown delivery error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func Test_webhookUsecase_DeliverDue_DeletedSubscription(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	sender := &recordingSender{}
+	u := newTestWebhookUsecase(clock, sender)
+	ctx := context.Background()
+	_, _ = u.Create(ctx, domain.CreateWebhookRequest{
+		URL:    "http://example.com/hook",
+		Events: []domain.WebhookEvent{domain.WebhookEventTaskCreated},
+		Secret: "0123456789abcdef",
+	})
+	_ = u.Publish(ctx, domain.WebhookEventTaskCreated, domain.Task{ID: 1, Name: "taskName1"})
+	_ = u.Delete(ctx, 1)
+
+	if n, err := u.DeliverDue(ctx); n != 0 || err != nil {
+		t.Fatalf("DeliverDue() = %v, %v, want 0, nil", n, err)
+	}
+	dead, _ := u.ListDeadLetters(ctx)
+	if len(sender.got) != 0 || len(dead.Result) != 1 || dead.Result[0].Attempts != 0 {
+		t.Errorf("ListDeadLetters() = %+v, sent %d, want one unsent dead delivery", dead.Result, len(sender.got))
+	}
+}
+
+func Test_webhookUsecase_backoffAfter(t *testing.T) {
+	u := &webhookUsecase{backoff: 30 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, domain.MaxWebhookBackoff},
+		{40, domain.MaxWebhookBackoff},
+	}
+	for _, tt := range tests {
+		if got := u.backoffAfter(tt.attempts); got != tt.want {
+			t.Errorf("backoffAfter(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// blockingSender holds every send until release is closed and counts the sends in flight.
+type blockingSender struct {
+	mu       sync.Mutex
+	inFlight int
+	most     int
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func (s *blockingSender) Send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) error {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.most {
+		s.most = s.inFlight
+	}
+	s.mu.Unlock()
+	s.started <- struct{}{}
+	<-s.release
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	return nil
+}
+
+func Test_webhookUsecase_DeliverDue_Parallel(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)}
+	sender := &blockingSender{started: make(chan struct{}), release: make(chan struct{})}
+	u := newTestWebhookUsecase(clock, sender)
+	ctx := context.Background()
+	subscriptions := 2 * deliveryWorkers
+	for i := 0; i < subscriptions; i++ {
+		_, _ = u.Create(ctx, domain.CreateWebhookRequest{
+			URL:    "http://example.com/hook",
+			Events: []domain.WebhookEvent{domain.WebhookEventTaskCreated},
+			Secret: "0123456789abcdef",
+		})
+	}
+	_ = u.Publish(ctx, domain.WebhookEventTaskCreated, domain.Task{ID: 1, Name: "taskName1"})
+
+	done := make(chan int)
+	go func() {
+		n, _ := u.DeliverDue(ctx)
+		done <- n
+	}()
+	// Every worker is busy with a subscription of its own before any send returns.
+	for i := 0; i < deliveryWorkers; i++ {
+		<-sender.started
+	}
+	close(sender.release)
+	for i := deliveryWorkers; i < subscriptions; i++ {
+		<-sender.started
+	}
+	if n := <-done; n != subscriptions {
+		t.Errorf("DeliverDue() = %d, want %d", n, subscriptions)
+	}
+	if sender.most != deliveryWorkers {
+		t.Errorf("DeliverDue() sent %d at the same time, want %d", sender.most, deliveryWorkers)
+	}
+}
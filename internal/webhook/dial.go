@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// NewPublicClient returns a client with DefaultTimeout that refuses to connect to loopback,
+// private, link-local, multicast and unspecified addresses. The address is checked when the
+// connection is made, after the name is resolved, so a name that resolves to an internal
+// address is refused too. Proxies from the environment are not used, they would be dialed
+// instead of the endpoint.
+func NewPublicClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   DefaultTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   refuseInternal,
+	}
+	return &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: DefaultTimeout,
+		},
+	}
+}
+
+// refuseInternal is the dialer control that fails for addresses that are not public.
+func refuseInternal(network string, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("webhook: %s is not a public address", host)
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds a post when no client is given.
+const DefaultTimeout = 10 * time.Second
+
+// Poster posts JSON bodies and fails unless the endpoint answers with a 2xx status.
+type Poster struct {
+	client *http.Client
+}
+
+// NewPoster posts with client, a nil client gets one with DefaultTimeout.
+func NewPoster(client *http.Client) Poster {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return Poster{
+		client: client,
+	}
+}
+
+// Post sends body to url with header added to the JSON content type.
+func (p Poster) Post(ctx context.Context, url string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// PostJSON marshals v and posts it like Post.
+func (p Poster) PostJSON(ctx context.Context, url string, v interface{}, header http.Header) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return p.Post(ctx, url, body, header)
+}
@@ -0,0 +1,68 @@
+// Package webhook posts signed webhook deliveries.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"oa-gogolook/internal/domain"
+	"strconv"
+	"time"
+)
+
+// MaxSignatureAge is how far the timestamp of a delivery may be off the clock of the
+// receiver, an older delivery is taken for a replay.
+const MaxSignatureAge = 5 * time.Minute
+
+// Sign returns the value of the signature header for body sent at timestamp, the Unix time
+// in seconds of the timestamp header.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at timestamp, in constant
+// time, and the timestamp is within MaxSignatureAge of now.
+func Verify(secret string, timestamp string, body []byte, signature string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > MaxSignatureAge || age < -MaxSignatureAge {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+type sender struct {
+	poster Poster
+	now    func() time.Time
+}
+
+// NewSender signs every delivery and posts it with client. A nil client gets one with
+// DefaultTimeout that only connects to public addresses, so a subscription can not reach
+// the services next to this one.
+func NewSender(client *http.Client) *sender {
+	if client == nil {
+		client = NewPublicClient()
+	}
+	return &sender{
+		poster: NewPoster(client),
+		now:    time.Now,
+	}
+}
+
+func (s *sender) Send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) error {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	header := http.Header{}
+	header.Set(domain.WebhookEventHeader, string(delivery.Event))
+	header.Set(domain.WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	header.Set(domain.WebhookTimestampHeader, timestamp)
+	header.Set(domain.WebhookSignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+	return s.poster.Post(ctx, subscription.URL, delivery.Payload, header)
+}
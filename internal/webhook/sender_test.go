@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"oa-gogolook/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"task.created"}`)
+	timestamp := "1667296800"
+	sentAt := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	// printf '1667296800.{"event":"task.created"}' | openssl dgst -sha256 -hmac s3cr3t
+	want := "sha256=90f3c14977f50f5cb7f31d8382222f64277a67ed804394c086f444b557c4c3c4"
+	if got := Sign("s3cr3t", timestamp, body); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		now       time.Time
+		want      bool
+	}{
+		{name: "OK", secret: "s3cr3t", timestamp: timestamp, now: sentAt.Add(time.Minute), want: true},
+		{name: "OtherSecret", secret: "other", timestamp: timestamp, now: sentAt, want: false},
+		{name: "OtherTimestamp", secret: "s3cr3t", timestamp: "1667296801", now: sentAt, want: false},
+		{name: "Replayed", secret: "s3cr3t", timestamp: timestamp, now: sentAt.Add(MaxSignatureAge + time.Second), want: false},
+		{name: "BadTimestamp", secret: "s3cr3t", timestamp: "yesterday", now: sentAt, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.timestamp, body, want, tt.now); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sender_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "OK",
+			status:  http.StatusOK,
+			wantErr: false,
+		},
+		{
+			name:    "Gone",
+			status:  http.StatusGone,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := domain.WebhookSubscription{ID: 1, Secret: "s3cr3t"}
+			delivery := domain.WebhookDelivery{ID: 7, Event: domain.WebhookEventTaskCreated, Payload: []byte(`{"event":"task.created"}`)}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if !Verify(subscription.Secret, r.Header.Get(domain.WebhookTimestampHeader), body, r.Header.Get(domain.WebhookSignatureHeader), time.Now()) {
+					t.Errorf("Send() signature %q does not match", r.Header.Get(domain.WebhookSignatureHeader))
+				}
+				if r.Header.Get(domain.WebhookDeliveryHeader) != "7" || r.Header.Get(domain.WebhookEventHeader) != "task.created" {
+					t.Errorf("Send() headers = %v", r.Header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			subscription.URL = server.URL
+
+			err := NewSender(server.Client()).Send(context.Background(), subscription, delivery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSender_InternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Send() reached %s", r.URL)
+	}))
+	defer server.Close()
+	subscription := domain.WebhookSubscription{ID: 1, URL: server.URL, Secret: "s3cr3t"}
+	delivery := domain.WebhookDelivery{ID: 7, Event: domain.WebhookEventTaskCreated, Payload: []byte(`{}`)}
+	if err := NewSender(nil).Send(context.Background(), subscription, delivery); err == nil {
+		t.Errorf("Send() to a loopback address error = nil, want an error")
+	}
+}
+
+func Test_isPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublic(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}